@@ -0,0 +1,145 @@
+package archive
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// How often records past the retention period are removed.
+	pruneInterval time.Duration = time.Hour
+)
+
+// Read-only archive of completed games, kept independently of the live games.
+type Archive struct {
+	store Store
+
+	// How long records are kept after they are archived, or zero to keep records forever.
+	retention time.Duration
+}
+
+// A summary of an archived game, as shown when browsing the archive.
+type Summary struct {
+	GameID     string    `json:"gameID"`
+	Title      string    `json:"title"`
+	Category   string    `json:"category"`
+	Players    int       `json:"players"`
+	Rounds     int       `json:"rounds"`
+	CreatedAt  time.Time `json:"createdAt"`
+	ArchivedAt time.Time `json:"archivedAt"`
+}
+
+// Create a new archive in the store, with the given retention period (zero keeps records forever).
+func New(store Store, retention time.Duration) *Archive {
+	return &Archive{
+		store:     store,
+		retention: retention,
+	}
+}
+
+// Check if a record is past the retention period.
+func (archive *Archive) isExpired(record Record, now time.Time) bool {
+	return archive.retention > 0 && now.Sub(record.ArchivedAt) > archive.retention
+}
+
+// Save a completed game into the archive, replacing any previous record of the game.
+func (archive *Archive) Save(record Record) error {
+	record.ArchivedAt = time.Now()
+	return archive.store.Put(record)
+}
+
+// Check the store can save records, returning an error if it cannot.
+func (archive *Archive) Ping() error {
+	return archive.store.Ping()
+}
+
+// Get an archived game by its ID. Records past the retention period are not returned, even if not yet pruned.
+func (archive *Archive) Get(gameID string) (Record, bool) {
+	record, ok := archive.store.Get(gameID)
+	if !ok || archive.isExpired(record, time.Now()) {
+		return Record{}, false
+	}
+	return record, true
+}
+
+// Search the listed games for a query, matching the title, category and player names. An empty query matches all listed games.
+// Returns at most limit summaries, most recently archived first.
+func (archive *Archive) Search(query string, limit int) []Summary {
+	query = strings.ToLower(strings.TrimSpace(query))
+	now := time.Now()
+
+	summaries := make([]Summary, 0)
+	for _, record := range archive.store.List() {
+		if !record.Listed || archive.isExpired(record, now) || !recordMatches(record, query) {
+			continue
+		}
+		summaries = append(summaries, Summary{
+			GameID:     record.Transcript.GameID,
+			Title:      record.Transcript.Title,
+			Category:   record.Category,
+			Players:    len(record.Transcript.Players),
+			Rounds:     len(record.Transcript.Rounds),
+			CreatedAt:  record.Transcript.CreatedAt,
+			ArchivedAt: record.ArchivedAt,
+		})
+	}
+
+	sort.Slice(summaries, func(i, j int) bool {
+		return summaries[i].ArchivedAt.After(summaries[j].ArchivedAt)
+	})
+	if len(summaries) > limit {
+		summaries = summaries[:limit]
+	}
+	return summaries
+}
+
+// Check if a record matches a lower case search query.
+func recordMatches(record Record, query string) bool {
+	if query == "" {
+		return true
+	}
+
+	searchable := []string{record.Transcript.Title, record.Category}
+	for _, player := range record.Transcript.Players {
+		searchable = append(searchable, player.Name)
+	}
+	for _, value := range searchable {
+		if strings.Contains(strings.ToLower(value), query) {
+			return true
+		}
+	}
+	return false
+}
+
+// Remove all records past the retention period. Returns the number of records removed.
+func (archive *Archive) Prune() int {
+	now := time.Now()
+	pruned := 0
+	for _, record := range archive.store.List() {
+		if !archive.isExpired(record, now) {
+			continue
+		}
+
+		err := archive.store.Delete(record.Transcript.GameID)
+		if err != nil {
+			log.Error().Str("GameID", record.Transcript.GameID).Err(err).Msg("Failed to prune archived game")
+			continue
+		}
+		pruned += 1
+	}
+	return pruned
+}
+
+// Periodically prune the archive. Never returns.
+func (archive *Archive) PrunePeriodically() {
+	if archive.retention == 0 {
+		return
+	}
+	for range time.Tick(pruneInterval) {
+		pruned := archive.Prune()
+		log.Info().Int("Pruned", pruned).Msg("Pruned Archive")
+	}
+}
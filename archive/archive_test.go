@@ -0,0 +1,61 @@
+package archive
+
+import (
+	"testing"
+	"time"
+)
+
+func TestArchive(t *testing.T) {
+	store := NewMemoryStore()
+	expired := testRecord("old", "Expired Game")
+	expired.ArchivedAt = time.Now().Add(-2 * time.Hour)
+	unlisted := testRecord("private", "Private Game")
+	unlisted.Listed = false
+	newer := testRecord("new", "Newer Game")
+	newer.ArchivedAt = time.Now().Add(time.Minute)
+	for _, record := range []Record{testRecord("abc", "Zoo Trip"), expired, unlisted, newer} {
+		store.Put(record)
+	}
+	archive := New(store, time.Hour)
+
+	testCases := []struct {
+		query string
+		limit int
+		want  []string
+	}{
+		{"", 10, []string{"new", "abc"}},
+		{"zoo", 10, []string{"abc"}},
+		{"ANIMALS", 10, []string{"new", "abc"}},
+		{"alice", 10, []string{"new", "abc"}},
+		{"private", 10, []string{}},
+		{"expired", 10, []string{}},
+		{"", 1, []string{"new"}},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.query, func(t *testing.T) {
+			summaries := archive.Search(testCase.query, testCase.limit)
+			if len(summaries) != len(testCase.want) {
+				t.Fatalf("got %+v, want %v", summaries, testCase.want)
+			}
+			for i, summary := range summaries {
+				if summary.GameID != testCase.want[i] {
+					t.Errorf("result %d is %s, want %s", i, summary.GameID, testCase.want[i])
+				}
+			}
+		})
+	}
+
+	if _, ok := archive.Get("private"); !ok {
+		t.Error("unlisted game not found by its ID")
+	}
+	if _, ok := archive.Get("old"); ok {
+		t.Error("expired game found before pruning")
+	}
+	if pruned := archive.Prune(); pruned != 1 {
+		t.Errorf("pruned %d records, want 1", pruned)
+	}
+	if _, ok := store.Get("old"); ok {
+		t.Error("expired game still stored after pruning")
+	}
+}
@@ -0,0 +1,215 @@
+package archive
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/hmcalister/twentyquestions/transcript"
+)
+
+var (
+	// Game IDs are used as file names on disk, so only allow the characters the game master generates.
+	validGameID = regexp.MustCompile(`^[a-zA-Z0-9]+$`)
+)
+
+// A completed game kept in the archive.
+type Record struct {
+	Transcript transcript.Transcript `json:"transcript"`
+	Category   string                `json:"category"`
+
+	// Set if the game was public, and hence may be browsed and searched. Other games are only found by their URL.
+	Listed bool `json:"listed"`
+
+	// The last time the record was saved -- the retention policy is measured from this time.
+	ArchivedAt time.Time `json:"archivedAt"`
+}
+
+// Storage for archived games. Implementations must be safe for concurrent use.
+type Store interface {
+	// Save a record, replacing any existing record for the same game.
+	Put(record Record) error
+
+	// Get the record for a game, if it exists.
+	Get(gameID string) (Record, bool)
+
+	// Remove the record for a game. Removing a game that does not exist is not an error.
+	Delete(gameID string) error
+
+	// Get all records, in no particular order.
+	List() []Record
+
+	// Check the store can save records, returning an error if it cannot.
+	Ping() error
+}
+
+// --------------------------------------------------------------------------------
+// Memory Store
+// --------------------------------------------------------------------------------
+
+// Store keeping all records in memory -- the archive is lost when the server restarts.
+type MemoryStore struct {
+	records map[string]Record
+
+	// Mutex to handle async reading and writing of the records.
+	recordsMutex sync.RWMutex
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		records: make(map[string]Record),
+	}
+}
+
+func (store *MemoryStore) Put(record Record) error {
+	store.recordsMutex.Lock()
+	defer store.recordsMutex.Unlock()
+	store.records[record.Transcript.GameID] = record
+	return nil
+}
+
+func (store *MemoryStore) Get(gameID string) (Record, bool) {
+	store.recordsMutex.RLock()
+	defer store.recordsMutex.RUnlock()
+	record, ok := store.records[gameID]
+	return record, ok
+}
+
+func (store *MemoryStore) Delete(gameID string) error {
+	store.recordsMutex.Lock()
+	defer store.recordsMutex.Unlock()
+	delete(store.records, gameID)
+	return nil
+}
+
+func (store *MemoryStore) List() []Record {
+	store.recordsMutex.RLock()
+	defer store.recordsMutex.RUnlock()
+	records := make([]Record, 0, len(store.records))
+	for _, record := range store.records {
+		records = append(records, record)
+	}
+	return records
+}
+
+func (store *MemoryStore) Ping() error {
+	return nil
+}
+
+// --------------------------------------------------------------------------------
+// Disk Store
+// --------------------------------------------------------------------------------
+
+// Store writing each record to a JSON file in a directory, so the archive survives restarts.
+// Records are also kept in memory, so reads never touch the disk.
+type DiskStore struct {
+	directory string
+	memory    *MemoryStore
+
+	// Mutex to ensure the files and the in memory records are changed together.
+	filesMutex sync.Mutex
+}
+
+// Create a disk store in the given directory, creating the directory if needed and loading any records already there.
+func NewDiskStore(directory string) (*DiskStore, error) {
+	err := os.MkdirAll(directory, 0o755)
+	if err != nil {
+		return nil, err
+	}
+
+	store := &DiskStore{
+		directory: directory,
+		memory:    NewMemoryStore(),
+	}
+
+	recordFiles, err := filepath.Glob(filepath.Join(directory, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	for _, recordFile := range recordFiles {
+		recordBytes, err := os.ReadFile(recordFile)
+		if err != nil {
+			return nil, err
+		}
+
+		var record Record
+		err = json.Unmarshal(recordBytes, &record)
+		if err != nil {
+			return nil, errors.Join(errors.New("invalid archive record "+recordFile), err)
+		}
+		store.memory.Put(record)
+	}
+	return store, nil
+}
+
+// The file a game is stored in.
+func (store *DiskStore) recordPath(gameID string) (string, error) {
+	if !validGameID.MatchString(gameID) {
+		return "", errors.New("invalid game ID")
+	}
+	return filepath.Join(store.directory, gameID+".json"), nil
+}
+
+// Save the record to disk, then to memory. The file is written atomically, so a crash never leaves a partial record.
+func (store *DiskStore) Put(record Record) error {
+	recordPath, err := store.recordPath(record.Transcript.GameID)
+	if err != nil {
+		return err
+	}
+	recordBytes, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	store.filesMutex.Lock()
+	defer store.filesMutex.Unlock()
+
+	temporaryPath := recordPath + ".tmp"
+	err = os.WriteFile(temporaryPath, recordBytes, 0o644)
+	if err != nil {
+		return err
+	}
+	err = os.Rename(temporaryPath, recordPath)
+	if err != nil {
+		return err
+	}
+	return store.memory.Put(record)
+}
+
+func (store *DiskStore) Get(gameID string) (Record, bool) {
+	return store.memory.Get(gameID)
+}
+
+func (store *DiskStore) Delete(gameID string) error {
+	recordPath, err := store.recordPath(gameID)
+	if err != nil {
+		return err
+	}
+
+	store.filesMutex.Lock()
+	defer store.filesMutex.Unlock()
+
+	err = os.Remove(recordPath)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return store.memory.Delete(gameID)
+}
+
+func (store *DiskStore) List() []Record {
+	return store.memory.List()
+}
+
+// Check the directory can still be written to, by creating and removing a temporary file.
+func (store *DiskStore) Ping() error {
+	probeFile, err := os.CreateTemp(store.directory, ".ping-*.tmp")
+	if err != nil {
+		return err
+	}
+	probeFile.Close()
+	return os.Remove(probeFile.Name())
+}
@@ -0,0 +1,146 @@
+package archive
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hmcalister/twentyquestions/transcript"
+)
+
+// Create a record of a game with the given ID and title.
+func testRecord(gameID string, title string) Record {
+	return Record{
+		Transcript: transcript.Transcript{
+			GameID:  gameID,
+			Title:   title,
+			Players: []transcript.Player{{Name: "Alice", Score: 1}},
+			Rounds:  []transcript.Round{{Number: 1, OracleName: "Bob", Outcome: transcript.Outcome_Correct, Secret: "Giraffe"}},
+		},
+		Category:   "Animals",
+		Listed:     true,
+		ArchivedAt: time.Now().Round(0),
+	}
+}
+
+func TestDiskStore(t *testing.T) {
+	testCases := []struct {
+		name string
+
+		// Changes made to the store before it is reopened.
+		change    func(store *DiskStore) error
+		wantErr   bool
+		wantGames map[string]string
+	}{
+		{"put", func(store *DiskStore) error {
+			return store.Put(testRecord("abc", "First"))
+		}, false, map[string]string{"abc": "First"}},
+		{"put replaces", func(store *DiskStore) error {
+			store.Put(testRecord("abc", "First"))
+			return store.Put(testRecord("abc", "Second"))
+		}, false, map[string]string{"abc": "Second"}},
+		{"delete", func(store *DiskStore) error {
+			store.Put(testRecord("abc", "First"))
+			store.Put(testRecord("def", "Other"))
+			return store.Delete("abc")
+		}, false, map[string]string{"def": "Other"}},
+		{"delete missing game", func(store *DiskStore) error {
+			return store.Delete("missing")
+		}, false, map[string]string{}},
+		{"put invalid game ID", func(store *DiskStore) error {
+			return store.Put(testRecord("../escape", "Escaped"))
+		}, true, map[string]string{}},
+		{"delete invalid game ID", func(store *DiskStore) error {
+			return store.Delete("../escape")
+		}, true, map[string]string{}},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			directory := filepath.Join(t.TempDir(), "archive")
+			store, err := NewDiskStore(directory)
+			if err != nil {
+				t.Fatalf("failed to create store: %v", err)
+			}
+			err = testCase.change(store)
+			if (err != nil) != testCase.wantErr {
+				t.Fatalf("error = %v, want error %v", err, testCase.wantErr)
+			}
+
+			reopened, err := NewDiskStore(directory)
+			if err != nil {
+				t.Fatalf("failed to reopen store: %v", err)
+			}
+			for name, checkedStore := range map[string]*DiskStore{"store": store, "reopened store": reopened} {
+				if records := checkedStore.List(); len(records) != len(testCase.wantGames) {
+					t.Errorf("%s has %d records, want %d", name, len(records), len(testCase.wantGames))
+				}
+				for gameID, title := range testCase.wantGames {
+					record, ok := checkedStore.Get(gameID)
+					if !ok || record.Transcript.Title != title {
+						t.Errorf("%s has game %s titled %q (found %v), want %q", name, gameID, record.Transcript.Title, ok, title)
+					}
+				}
+			}
+
+			// Records are written atomically, so no temporary files are left behind.
+			temporaryFiles, _ := filepath.Glob(filepath.Join(directory, "*.tmp"))
+			if len(temporaryFiles) != 0 {
+				t.Errorf("temporary files left in the store: %v", temporaryFiles)
+			}
+		})
+	}
+}
+
+func TestDiskStoreReloadsRecord(t *testing.T) {
+	directory := t.TempDir()
+	store, err := NewDiskStore(directory)
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	record := testRecord("abc", "First")
+	if err := store.Put(record); err != nil {
+		t.Fatalf("failed to put record: %v", err)
+	}
+
+	reopened, err := NewDiskStore(directory)
+	if err != nil {
+		t.Fatalf("failed to reopen store: %v", err)
+	}
+	got, ok := reopened.Get("abc")
+	if !ok {
+		t.Fatal("record missing after reopening")
+	}
+	if got.Category != record.Category || got.Listed != record.Listed || !got.ArchivedAt.Equal(record.ArchivedAt) {
+		t.Errorf("reloaded record %+v, want %+v", got, record)
+	}
+	if len(got.Transcript.Rounds) != 1 || got.Transcript.Rounds[0].Secret != "Giraffe" || got.Transcript.Players[0].Name != "Alice" {
+		t.Errorf("reloaded transcript %+v, want %+v", got.Transcript, record.Transcript)
+	}
+}
+
+func TestNewDiskStoreRejectsInvalidRecord(t *testing.T) {
+	directory := t.TempDir()
+	if err := os.WriteFile(filepath.Join(directory, "abc.json"), []byte("not json"), 0o644); err != nil {
+		t.Fatalf("failed to write record: %v", err)
+	}
+	if _, err := NewDiskStore(directory); err == nil {
+		t.Error("opened a store with an invalid record")
+	}
+}
+
+func TestDiskStorePing(t *testing.T) {
+	directory := t.TempDir()
+	store, err := NewDiskStore(directory)
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	if err := store.Ping(); err != nil {
+		t.Errorf("store failed ping: %v", err)
+	}
+	os.RemoveAll(directory)
+	if err := store.Ping(); err == nil {
+		t.Error("store with a missing directory passed ping")
+	}
+}
@@ -0,0 +1,240 @@
+package audit
+
+import (
+	"bufio"
+	"compress/gzip"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+// Roles an actor may have when performing an action.
+const (
+	Role_Oracle  = "oracle"
+	Role_Guesser = "guesser"
+	Role_Admin   = "admin"
+)
+
+// A single action recorded in the audit log, written as one line of JSON.
+type Entry struct {
+	Time   time.Time `json:"time"`
+	GameID string    `json:"gameID"`
+	Round  int       `json:"round"`
+	Action string    `json:"action"`
+
+	// The player who performed the action, and the role they had in the round.
+	ActorID   string `json:"actorID"`
+	ActorName string `json:"actorName"`
+	Role      string `json:"role"`
+
+	// The HMAC-SHA256 of the text the action carried (e.g. the question), keyed with the key of the log, so the log can be
+	// checked against a transcript without holding the text itself. Keying the hash stops anyone without the key recovering
+	// the text by hashing guesses, such as every word in a word pack. Empty if the action carried no text.
+	PayloadHash string `json:"payloadHash,omitempty"`
+}
+
+// Append-only log of game actions, kept in its own rotating file apart from the server log.
+// A nil log records nothing, so the audit log may be disabled.
+type Log struct {
+	// Path of the current log file. Rotated files are kept alongside it, named with the time they were rotated.
+	filename string
+
+	writer *lumberjack.Logger
+
+	// Secret key the payloads are hashed with, kept beside the log so hashes stay comparable across restarts.
+	key []byte
+}
+
+const (
+	// Length of the key payloads are hashed with, in bytes.
+	keyLength int = 32
+)
+
+// Open the audit log at the given path, creating it if needed. Rotated files are compressed and kept forever.
+//
+// The key payloads are hashed with is read from the path with ".key" appended, or created there if this is a new log.
+func Open(filename string) (*Log, error) {
+	key, err := loadOrCreateKey(filename + ".key")
+	if err != nil {
+		return nil, err
+	}
+
+	return &Log{
+		filename: filename,
+		writer: &lumberjack.Logger{
+			Filename: filename,
+			MaxSize:  100,
+			Compress: true,
+		},
+		key: key,
+	}, nil
+}
+
+// Read the key from the key file, or create the key file with a new random key if it does not exist.
+func loadOrCreateKey(keyFilename string) ([]byte, error) {
+	encodedKey, err := os.ReadFile(keyFilename)
+	if err == nil {
+		key, err := hex.DecodeString(strings.TrimSpace(string(encodedKey)))
+		if err != nil || len(key) != keyLength {
+			return nil, fmt.Errorf("audit key file %s is not a %d byte hex key", keyFilename, keyLength)
+		}
+		return key, nil
+	}
+	if !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+
+	key := make([]byte, keyLength)
+	_, err = rand.Read(key)
+	if err != nil {
+		return nil, err
+	}
+	err = os.MkdirAll(filepath.Dir(keyFilename), 0755)
+	if err != nil {
+		return nil, err
+	}
+	// Exclusive create, so a key is never overwritten -- every hash made with it would become uncheckable.
+	keyFile, err := os.OpenFile(keyFilename, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return nil, err
+	}
+	defer keyFile.Close()
+	_, err = keyFile.WriteString(hex.EncodeToString(key) + "\n")
+	return key, err
+}
+
+// Hash the text carried by an action with the key of the log, or an empty string if there is none.
+func (auditLog *Log) HashPayload(payload string) string {
+	if auditLog == nil || payload == "" {
+		return ""
+	}
+	mac := hmac.New(sha256.New, auditLog.key)
+	mac.Write([]byte(payload))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Append an entry to the log, timestamped now unless it already has the time of the action.
+func (auditLog *Log) Record(entry Entry) {
+	if auditLog == nil {
+		return
+	}
+
+	if entry.Time.IsZero() {
+		entry.Time = time.Now()
+	}
+	line, err := json.Marshal(entry)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to encode audit entry")
+		return
+	}
+
+	// Each entry is a single write, so entries from concurrent games are never interleaved.
+	_, err = auditLog.writer.Write(append(line, '\n'))
+	if err != nil {
+		log.Error().Err(err).Str("GameID", entry.GameID).Str("Action", entry.Action).Msg("Failed to write audit entry")
+	}
+}
+
+// Check the directory of the log can be written to, creating it if needed -- the same as the log does on its first write.
+// A nil log is always fine.
+func (auditLog *Log) Ping() error {
+	if auditLog == nil {
+		return nil
+	}
+
+	directory := filepath.Dir(auditLog.filename)
+	err := os.MkdirAll(directory, 0755)
+	if err != nil {
+		return err
+	}
+	probeFile, err := os.CreateTemp(directory, ".ping-*.tmp")
+	if err != nil {
+		return err
+	}
+	probeFile.Close()
+	return os.Remove(probeFile.Name())
+}
+
+// Get every entry for a game, oldest first, reading the rotated files and then the current file.
+func (auditLog *Log) Query(gameID string) ([]Entry, error) {
+	entries := make([]Entry, 0)
+	if auditLog == nil {
+		return entries, nil
+	}
+
+	filenames, err := auditLog.rotatedFilenames()
+	if err != nil {
+		return nil, err
+	}
+	filenames = append(filenames, auditLog.filename)
+
+	for _, filename := range filenames {
+		entries, err = readEntries(filename, gameID, entries)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+	}
+	return entries, nil
+}
+
+// Find the files rotated out of the current log file, oldest first.
+// Rotated files are named by lumberjack as the current name with the rotation time before the extension.
+func (auditLog *Log) rotatedFilenames() ([]string, error) {
+	extension := filepath.Ext(auditLog.filename)
+	prefix := strings.TrimSuffix(auditLog.filename, extension) + "-"
+	filenames, err := filepath.Glob(prefix + "*" + extension + "*")
+	if err != nil {
+		return nil, err
+	}
+
+	// The rotation time sorts lexically, whether or not the file is compressed.
+	sort.Strings(filenames)
+	return filenames, nil
+}
+
+// Read the entries for a game from a single file, appending them to entries.
+// Lines that cannot be parsed, such as a line still being written, are skipped.
+func readEntries(filename string, gameID string, entries []Entry) ([]Entry, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return entries, err
+	}
+	defer file.Close()
+
+	var reader io.Reader = file
+	if strings.HasSuffix(filename, ".gz") {
+		gzipReader, err := gzip.NewReader(file)
+		if err != nil {
+			return entries, err
+		}
+		defer gzipReader.Close()
+		reader = gzipReader
+	}
+
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		// Skip parsing lines that cannot be for the game.
+		if !strings.Contains(scanner.Text(), gameID) {
+			continue
+		}
+		var entry Entry
+		if json.Unmarshal(scanner.Bytes(), &entry) == nil && entry.GameID == gameID {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, scanner.Err()
+}
@@ -0,0 +1,111 @@
+package audit
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestHashPayloadIsKeyed(t *testing.T) {
+	directory := t.TempDir()
+	firstLog := mustOpen(t, filepath.Join(directory, "first.log"))
+	secondLog := mustOpen(t, filepath.Join(directory, "second.log"))
+	reopenedLog := mustOpen(t, filepath.Join(directory, "first.log"))
+
+	testCases := []struct {
+		name      string
+		hash      string
+		otherHash string
+		wantEqual bool
+	}{
+		{"same log", firstLog.HashPayload("giraffe"), firstLog.HashPayload("giraffe"), true},
+		{"reopened log", firstLog.HashPayload("giraffe"), reopenedLog.HashPayload("giraffe"), true},
+		{"different key", firstLog.HashPayload("giraffe"), secondLog.HashPayload("giraffe"), false},
+		{"different payload", firstLog.HashPayload("giraffe"), firstLog.HashPayload("elephant"), false},
+		{"empty payload", firstLog.HashPayload(""), "", true},
+		{"nil log", (*Log)(nil).HashPayload("giraffe"), "", true},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			if (testCase.hash == testCase.otherHash) != testCase.wantEqual {
+				t.Errorf("hashes %q and %q, want equal = %v", testCase.hash, testCase.otherHash, testCase.wantEqual)
+			}
+		})
+	}
+}
+
+func TestOpenRejectsMalformedKey(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "audit.log")
+	if err := os.WriteFile(filename+".key", []byte("not a key\n"), 0600); err != nil {
+		t.Fatalf("failed to write key file: %v", err)
+	}
+	if _, err := Open(filename); err == nil {
+		t.Error("opened a log with a malformed key, want an error")
+	}
+}
+
+func TestQuery(t *testing.T) {
+	auditLog := mustOpen(t, filepath.Join(t.TempDir(), "audit.log"))
+	auditLog.Record(Entry{GameID: "game1", Action: "secretSet", PayloadHash: auditLog.HashPayload("giraffe")})
+	auditLog.Record(Entry{GameID: "game2", Action: "secretSet"})
+	auditLog.Record(Entry{GameID: "game1", Action: "chatToggled"})
+
+	testCases := []struct {
+		gameID      string
+		wantActions []string
+	}{
+		{"game1", []string{"secretSet", "chatToggled"}},
+		{"game2", []string{"secretSet"}},
+		{"game3", []string{}},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.gameID, func(t *testing.T) {
+			entries, err := auditLog.Query(testCase.gameID)
+			if err != nil {
+				t.Fatalf("failed to query: %v", err)
+			}
+			if len(entries) != len(testCase.wantActions) {
+				t.Fatalf("got %d entries, want %d", len(entries), len(testCase.wantActions))
+			}
+			for i, entry := range entries {
+				if entry.Action != testCase.wantActions[i] {
+					t.Errorf("entry %d has action %s, want %s", i, entry.Action, testCase.wantActions[i])
+				}
+			}
+		})
+	}
+}
+
+func TestRecordKeepsTimeOfAction(t *testing.T) {
+	auditLog := mustOpen(t, filepath.Join(t.TempDir(), "audit.log"))
+	actionTime := time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)
+	auditLog.Record(Entry{GameID: "game1", Action: "secretSet", Time: actionTime})
+	auditLog.Record(Entry{GameID: "game1", Action: "chatToggled"})
+
+	entries, err := auditLog.Query("game1")
+	if err != nil {
+		t.Fatalf("failed to query: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("got %d entries, want 2", len(entries))
+	}
+	if !entries[0].Time.Equal(actionTime) {
+		t.Errorf("entry timestamped %v, want the time of the action %v", entries[0].Time, actionTime)
+	}
+	if entries[1].Time.IsZero() || entries[1].Time.Before(actionTime) {
+		t.Errorf("entry without a time timestamped %v, want now", entries[1].Time)
+	}
+}
+
+// Open a log, failing the test if it cannot be opened.
+func mustOpen(t *testing.T, filename string) *Log {
+	t.Helper()
+	auditLog, err := Open(filename)
+	if err != nil {
+		t.Fatalf("failed to open audit log: %v", err)
+	}
+	return auditLog
+}
@@ -0,0 +1,373 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hmcalister/twentyquestions/transcript"
+)
+
+const (
+	// Maximum number of bytes of an error response kept in a StatusError.
+	errorBodyLimit int64 = 1024
+)
+
+var (
+	// Returned when the game does not exist, e.g. because it has expired.
+	ErrNotFound = errors.New("game not found")
+
+	// Returned when the player may not take the action, e.g. a guesser giving a verdict.
+	ErrUnauthorized = errors.New("not allowed for this player")
+
+	// Returned when the action is not allowed at this point in the game, e.g. asking while a question is awaiting an answer.
+	ErrNotAllowedNow = errors.New("not allowed at this point in the game")
+
+	// Returned when the server or the game is at capacity.
+	ErrAtCapacity = errors.New("server or game is at capacity")
+
+	// Returned when joining a protected game without the passphrase, or with an incorrect passphrase.
+	ErrPassphraseRequired = errors.New("correct passphrase required")
+)
+
+// Error for a response with an unexpected status code. Matches the sentinel errors with errors.Is by status code.
+type StatusError struct {
+	StatusCode int
+	Body       string
+}
+
+func (statusError *StatusError) Error() string {
+	if statusError.Body == "" {
+		return fmt.Sprintf("unexpected status %d", statusError.StatusCode)
+	}
+	return fmt.Sprintf("unexpected status %d: %s", statusError.StatusCode, statusError.Body)
+}
+
+// Match the sentinel error for the status code.
+func (statusError *StatusError) Is(target error) bool {
+	switch statusError.StatusCode {
+	case http.StatusNotFound:
+		return target == ErrNotFound
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return target == ErrUnauthorized
+	case http.StatusBadRequest:
+		return target == ErrNotAllowedNow
+	case http.StatusServiceUnavailable, http.StatusTooManyRequests:
+		return target == ErrAtCapacity
+	default:
+		return false
+	}
+}
+
+// Read the status error from a response, closing the body.
+func statusErrorFromResponse(response *http.Response) error {
+	defer response.Body.Close()
+	bodyBytes, _ := io.ReadAll(io.LimitReader(response.Body, errorBodyLimit))
+	return &StatusError{
+		StatusCode: response.StatusCode,
+		Body:       strings.TrimSpace(string(bodyBytes)),
+	}
+}
+
+// --------------------------------------------------------------------------------
+// Client
+// --------------------------------------------------------------------------------
+
+// Client for a Twenty Questions server. Each client is a single player -- the player identity is kept in a cookie jar,
+// so every game created or joined by the client is played as the same player.
+type Client struct {
+	baseURL    *url.URL
+	httpClient *http.Client
+}
+
+// Create a client for the server at the base URL, e.g. "http://localhost:3000", as a new player.
+func New(baseURL string) (*Client, error) {
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		return nil, err
+	}
+	return NewWithJar(baseURL, jar)
+}
+
+// Create a client for the server at the base URL that keeps its cookies in the given jar -- e.g. a jar saved to disk,
+// so the same player identity and passphrases are used across runs.
+func NewWithJar(baseURL string, jar http.CookieJar) (*Client, error) {
+	parsedURL, err := url.Parse(strings.TrimSuffix(baseURL, "/"))
+	if err != nil {
+		return nil, err
+	}
+	if parsedURL.Scheme == "" || parsedURL.Host == "" {
+		return nil, errors.New("base URL must include a scheme and host")
+	}
+
+	return &Client{
+		baseURL:    parsedURL,
+		httpClient: &http.Client{Jar: jar},
+	}, nil
+}
+
+// Resolve a path against the base URL of the server.
+func (client *Client) resolve(path string) string {
+	return client.baseURL.String() + path
+}
+
+// Send a request, returning a StatusError for any response outside the 2xx and 3xx ranges.
+// The caller must close the body of the response.
+func (client *Client) do(ctx context.Context, method string, path string, form url.Values) (*http.Response, error) {
+	var body io.Reader
+	if form != nil {
+		body = strings.NewReader(form.Encode())
+	}
+	request, err := http.NewRequestWithContext(ctx, method, client.resolve(path), body)
+	if err != nil {
+		return nil, err
+	}
+	if form != nil {
+		request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+	request.Header.Set("Accept", "application/json")
+
+	response, err := client.httpClient.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	if response.StatusCode >= 400 {
+		return nil, statusErrorFromResponse(response)
+	}
+	return response, nil
+}
+
+// Send a request, discarding the body of the response.
+func (client *Client) send(ctx context.Context, method string, path string, form url.Values) error {
+	response, err := client.do(ctx, method, path, form)
+	if err != nil {
+		return err
+	}
+	io.Copy(io.Discard, response.Body)
+	return response.Body.Close()
+}
+
+// Options for a new game, matching the new game form. Zero values use the server defaults.
+type GameOptions struct {
+	Title    string
+	Category string
+	Public   bool
+
+	// Either "roundRobin" or "winner".
+	Rotation string
+
+	QuestionBudget int
+	HintCost       int
+
+	Voting       bool
+	VotingWindow time.Duration
+
+	Passphrase string
+
+	// Pick a secret for the oracle from the word packs, with the difficulty "any", "easy", "medium" or "hard".
+	PickSecret bool
+	Difficulty string
+
+	// Play against the computer oracle, with the creating player as the first guesser.
+	ComputerOracle bool
+}
+
+// Encode the options as the new game form.
+func (options GameOptions) form() url.Values {
+	form := url.Values{}
+	setIf := func(condition bool, key string, value string) {
+		if condition {
+			form.Set(key, value)
+		}
+	}
+	setIf(options.Title != "", "title", options.Title)
+	setIf(options.Category != "", "category", options.Category)
+	setIf(options.Public, "public", "on")
+	setIf(options.Rotation != "", "rotation", options.Rotation)
+	setIf(options.QuestionBudget != 0, "questionBudget", strconv.Itoa(options.QuestionBudget))
+	setIf(options.HintCost != 0, "hintCost", strconv.Itoa(options.HintCost))
+	setIf(options.Voting, "voting", "on")
+	setIf(options.VotingWindow != 0, "votingWindow", strconv.Itoa(int(options.VotingWindow.Seconds())))
+	setIf(options.Passphrase != "", "passphrase", options.Passphrase)
+	setIf(options.PickSecret, "pickSecret", "on")
+	setIf(options.Difficulty != "", "difficulty", options.Difficulty)
+	setIf(options.ComputerOracle, "computerOracle", "on")
+	return form
+}
+
+// Create a new game, with this client as the oracle of the first round unless playing against the computer oracle.
+func (client *Client) CreateGame(ctx context.Context, options GameOptions) (*Game, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, client.resolve("/game/new?"+options.form().Encode()), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// The new game is found from the redirect, so the redirect is not followed.
+	noRedirectClient := *client.httpClient
+	noRedirectClient.CheckRedirect = func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+	response, err := noRedirectClient.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	if response.StatusCode < 300 || response.StatusCode >= 400 {
+		return nil, statusErrorFromResponse(response)
+	}
+	response.Body.Close()
+
+	gameID, err := ParseGameID(response.Header.Get("Location"))
+	if err != nil {
+		return nil, err
+	}
+	return client.Join(ctx, gameID, "")
+}
+
+// Join a game by its ID or URL, entering the passphrase first if one is given.
+// Returns ErrPassphraseRequired if the game is protected and the passphrase is missing or incorrect.
+func (client *Client) Join(ctx context.Context, gameIDOrURL string, passphrase string) (*Game, error) {
+	gameID, err := ParseGameID(gameIDOrURL)
+	if err != nil {
+		return nil, err
+	}
+	game := &Game{
+		client: client,
+		ID:     gameID,
+		URL:    client.resolve("/game/" + gameID + "/"),
+	}
+
+	if passphrase != "" {
+		err = game.send(ctx, http.MethodPost, "passphrase", url.Values{"passphrase": {passphrase}})
+		if errors.Is(err, ErrUnauthorized) {
+			return nil, ErrPassphraseRequired
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// The game page asks for the passphrase rather than failing, so the state is read first to check the player is admitted.
+	_, err = game.State(ctx)
+	if errors.Is(err, ErrUnauthorized) {
+		return nil, ErrPassphraseRequired
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Opening the game page joins it, the same as in the browser.
+	err = game.send(ctx, http.MethodGet, "", nil)
+	if err != nil {
+		return nil, err
+	}
+	return game, nil
+}
+
+// Get the game ID from a game URL, e.g. "http://localhost:3000/game/abc/", or return the argument if it is already an ID.
+func ParseGameID(gameIDOrURL string) (string, error) {
+	gameIDOrURL = strings.TrimSpace(gameIDOrURL)
+	if !strings.Contains(gameIDOrURL, "/") {
+		if gameIDOrURL == "" {
+			return "", errors.New("game ID must not be empty")
+		}
+		return gameIDOrURL, nil
+	}
+
+	parsedURL, err := url.Parse(gameIDOrURL)
+	if err != nil {
+		return "", err
+	}
+	pathParts := strings.Split(strings.Trim(parsedURL.Path, "/"), "/")
+	for i, part := range pathParts {
+		if part == "game" && i+1 < len(pathParts) && pathParts[i+1] != "" {
+			return pathParts[i+1], nil
+		}
+	}
+	return "", errors.New("URL is not a game URL")
+}
+
+// --------------------------------------------------------------------------------
+// Game
+// --------------------------------------------------------------------------------
+
+// A game joined by a client. All actions are taken as the player of the client.
+type Game struct {
+	client *Client
+
+	ID  string
+	URL string
+}
+
+// Send a request to a route of the game, discarding the body of the response.
+func (game *Game) send(ctx context.Context, method string, route string, form url.Values) error {
+	return game.client.send(ctx, method, "/game/"+game.ID+"/"+route, form)
+}
+
+// Ask a question as a guesser. Returns ErrNotAllowedNow if the round is awaiting an answer, is over,
+// has no questions remaining, or if the game is in voting mode.
+func (game *Game) Ask(ctx context.Context, question string) error {
+	return game.send(ctx, http.MethodPost, "submitResponse", url.Values{"response": {question}})
+}
+
+// Answer the pending question as the oracle. Returns ErrNotAllowedNow if no question is awaiting an answer.
+func (game *Game) Answer(ctx context.Context, answer string) error {
+	return game.send(ctx, http.MethodPost, "submitResponse", url.Values{"response": {answer}})
+}
+
+// End the round with a verdict as the oracle. Returns ErrUnauthorized if the player is not the oracle.
+func (game *Game) Verdict(ctx context.Context, correct bool) error {
+	if correct {
+		return game.send(ctx, http.MethodGet, "oracleVerdictCorrect", nil)
+	}
+	return game.send(ctx, http.MethodGet, "oracleVerdictIncorrect", nil)
+}
+
+// Give a hint as the oracle, costing the guessers questions from their budget.
+func (game *Game) Hint(ctx context.Context, hint string) error {
+	return game.send(ctx, http.MethodPost, "giveHint", url.Values{"response": {hint}})
+}
+
+// Record the secret as the oracle, revealed to the guessers once the round is over.
+func (game *Game) SetSecret(ctx context.Context, secret string) error {
+	return game.send(ctx, http.MethodPost, "setSecret", url.Values{"secret": {secret}})
+}
+
+// Start the next round, once the current round is over.
+func (game *Game) NextRound(ctx context.Context) error {
+	return game.send(ctx, http.MethodPost, "nextRound", nil)
+}
+
+// Change the name of the player, in this and all future games.
+func (game *Game) SetName(ctx context.Context, name string) error {
+	return game.send(ctx, http.MethodPost, "setName", url.Values{"name": {name}})
+}
+
+// Send a chat message to the other players.
+func (game *Game) Chat(ctx context.Context, message string) error {
+	return game.send(ctx, http.MethodPost, "chat", url.Values{"message": {message}})
+}
+
+// Get the transcript of the game. Only finished rounds are included, along with the current round for its oracle.
+// Returns ErrUnauthorized if no round is visible to the player yet.
+func (game *Game) Transcript(ctx context.Context) (*transcript.Transcript, error) {
+	response, err := game.client.do(ctx, http.MethodGet, "/game/"+game.ID+"/export?format=json", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	var gameTranscript transcript.Transcript
+	err = json.NewDecoder(response.Body).Decode(&gameTranscript)
+	if err != nil {
+		return nil, err
+	}
+	return &gameTranscript, nil
+}
@@ -0,0 +1,267 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog"
+
+	"github.com/hmcalister/twentyquestions/archive"
+	"github.com/hmcalister/twentyquestions/game"
+	"github.com/hmcalister/twentyquestions/knowledge"
+	"github.com/hmcalister/twentyquestions/stats"
+	"github.com/hmcalister/twentyquestions/transcript"
+	"github.com/hmcalister/twentyquestions/webhooks"
+	"github.com/hmcalister/twentyquestions/wordpacks"
+)
+
+// Silence the server log, so test failures are not buried in it.
+func TestMain(m *testing.M) {
+	zerolog.SetGlobalLevel(zerolog.Disabled)
+	os.Exit(m.Run())
+}
+
+// Start a game master served over HTTP, routed the same as the server, returning its base URL.
+func newTestServer(t *testing.T) string {
+	t.Helper()
+
+	wordPacks, err := wordpacks.Load("../data/wordpacks")
+	if err != nil {
+		t.Fatalf("failed to load word packs: %v", err)
+	}
+	knowledgeBase, err := knowledge.Load("../data/knowledge/base.json")
+	if err != nil {
+		t.Fatalf("failed to load knowledge base: %v", err)
+	}
+
+	master := game.NewGameMaster(game.CapacityLimits{}, archive.New(archive.NewMemoryStore(), 0), stats.NewTracker(), nil, wordPacks, knowledgeBase, webhooks.NewDispatcher(false), nil)
+	router := chi.NewRouter()
+	router.Mount("/game", master.Router)
+	router.Mount("/api", master.APIRouter)
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+	return server.URL
+}
+
+// Create a client for the server, failing the test if it cannot be created.
+func newTestClient(t *testing.T, baseURL string) *Client {
+	t.Helper()
+	testClient, err := New(baseURL)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	return testClient
+}
+
+// Get the state of a game, failing the test if it cannot be fetched.
+func mustState(t *testing.T, testGame *Game) *GameState {
+	t.Helper()
+	state, err := testGame.State(context.Background())
+	if err != nil {
+		t.Fatalf("failed to get state: %v", err)
+	}
+	return state
+}
+
+func TestPlayRound(t *testing.T) {
+	ctx := context.Background()
+	baseURL := newTestServer(t)
+	oracleGame, err := newTestClient(t, baseURL).CreateGame(ctx, GameOptions{Title: "Client Test", Category: "Animals"})
+	if err != nil {
+		t.Fatalf("failed to create game: %v", err)
+	}
+	guesserGame, err := newTestClient(t, baseURL).Join(ctx, oracleGame.URL, "")
+	if err != nil {
+		t.Fatalf("failed to join game: %v", err)
+	}
+
+	steps := []struct {
+		name          string
+		action        func() error
+		wantStatus    string
+		wantOutcome   string
+		wantQuestions int
+	}{
+		{"joined", func() error { return nil }, RoundStatus_AwaitingQuestion, transcript.Outcome_InProgress, 20},
+		{"secret set", func() error { return oracleGame.SetSecret(ctx, "Giraffe") }, RoundStatus_AwaitingQuestion, transcript.Outcome_InProgress, 20},
+		{"question asked", func() error { return guesserGame.Ask(ctx, "Is it tall?") }, RoundStatus_AwaitingAnswer, transcript.Outcome_InProgress, 19},
+		{"question answered", func() error { return oracleGame.Answer(ctx, "Yes") }, RoundStatus_AwaitingQuestion, transcript.Outcome_InProgress, 19},
+		{"final question asked", func() error { return guesserGame.Ask(ctx, "Is it a giraffe?") }, RoundStatus_AwaitingAnswer, transcript.Outcome_InProgress, 18},
+		{"verdict given", func() error { return oracleGame.Verdict(ctx, true) }, RoundStatus_Over, transcript.Outcome_Correct, 18},
+	}
+
+	for _, step := range steps {
+		if err := step.action(); err != nil {
+			t.Fatalf("%s: %v", step.name, err)
+		}
+		for _, testGame := range []*Game{oracleGame, guesserGame} {
+			state := mustState(t, testGame)
+			if state.Status != step.wantStatus || state.Round.Outcome != step.wantOutcome || state.QuestionsRemaining != step.wantQuestions {
+				t.Errorf("%s: status %s, outcome %s and %d questions remaining, want %s, %s and %d", step.name,
+					state.Status, state.Round.Outcome, state.QuestionsRemaining, step.wantStatus, step.wantOutcome, step.wantQuestions)
+			}
+		}
+	}
+
+	oracleState := mustState(t, oracleGame)
+	guesserState := mustState(t, guesserGame)
+	if !oracleState.IsOracle() || guesserState.IsOracle() {
+		t.Errorf("roles are %s and %s, want oracle and guesser", oracleState.Role, guesserState.Role)
+	}
+	if oracleState.Title != "Client Test" || oracleState.Category != "Animals" {
+		t.Errorf("title %q and category %q, want the options of the game", oracleState.Title, oracleState.Category)
+	}
+	if guesserState.Round.Secret != "Giraffe" {
+		t.Errorf("secret = %q once the round is over, want Giraffe", guesserState.Round.Secret)
+	}
+	if len(guesserState.Round.Entries) != 2 || guesserState.Round.Entries[0].Answer != "Yes" {
+		t.Errorf("entries = %+v, want both questions with the first answered", guesserState.Round.Entries)
+	}
+	for _, player := range guesserState.Players {
+		if player.IsYou && (player.IsOracle || player.Score != 1) {
+			t.Errorf("guesser is %+v, want a guesser with a score of 1", player)
+		}
+	}
+}
+
+func TestSecretHiddenFromGuessers(t *testing.T) {
+	ctx := context.Background()
+	baseURL := newTestServer(t)
+	oracleGame, err := newTestClient(t, baseURL).CreateGame(ctx, GameOptions{})
+	if err != nil {
+		t.Fatalf("failed to create game: %v", err)
+	}
+	guesserGame, err := newTestClient(t, baseURL).Join(ctx, oracleGame.ID, "")
+	if err != nil {
+		t.Fatalf("failed to join game: %v", err)
+	}
+	if err := oracleGame.SetSecret(ctx, "Giraffe"); err != nil {
+		t.Fatalf("failed to set secret: %v", err)
+	}
+
+	if secret := mustState(t, oracleGame).Round.Secret; secret != "Giraffe" {
+		t.Errorf("oracle sees secret %q, want Giraffe", secret)
+	}
+	if secret := mustState(t, guesserGame).Round.Secret; secret != "" {
+		t.Errorf("guesser sees secret %q during the round, want none", secret)
+	}
+}
+
+func TestActionErrors(t *testing.T) {
+	ctx := context.Background()
+	baseURL := newTestServer(t)
+	oracleGame, err := newTestClient(t, baseURL).CreateGame(ctx, GameOptions{Passphrase: "open sesame"})
+	if err != nil {
+		t.Fatalf("failed to create game: %v", err)
+	}
+	guesserGame, err := newTestClient(t, baseURL).Join(ctx, oracleGame.ID, "open sesame")
+	if err != nil {
+		t.Fatalf("failed to join game with the passphrase: %v", err)
+	}
+
+	testCases := []struct {
+		name    string
+		action  func() error
+		wantErr error
+	}{
+		{"join missing game", func() error {
+			_, err := newTestClient(t, baseURL).Join(ctx, "missing", "")
+			return err
+		}, ErrNotFound},
+		{"join without passphrase", func() error {
+			_, err := newTestClient(t, baseURL).Join(ctx, oracleGame.ID, "")
+			return err
+		}, ErrPassphraseRequired},
+		{"join with wrong passphrase", func() error {
+			_, err := newTestClient(t, baseURL).Join(ctx, oracleGame.ID, "wrong")
+			return err
+		}, ErrPassphraseRequired},
+		{"guesser gives verdict", func() error { return guesserGame.Verdict(ctx, true) }, ErrUnauthorized},
+		{"answer without question", func() error { return oracleGame.Answer(ctx, "Yes") }, ErrNotAllowedNow},
+		{"state after passphrase", func() error {
+			_, err := guesserGame.State(ctx)
+			return err
+		}, nil},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			err := testCase.action()
+			if testCase.wantErr == nil && err != nil {
+				t.Errorf("got error %v, want none", err)
+			}
+			if testCase.wantErr != nil && !errors.Is(err, testCase.wantErr) {
+				t.Errorf("got error %v, want %v", err, testCase.wantErr)
+			}
+		})
+	}
+}
+
+func TestSubscribeDeliversState(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	baseURL := newTestServer(t)
+	oracleGame, err := newTestClient(t, baseURL).CreateGame(ctx, GameOptions{})
+	if err != nil {
+		t.Fatalf("failed to create game: %v", err)
+	}
+	guesserGame, err := newTestClient(t, baseURL).Join(ctx, oracleGame.ID, "")
+	if err != nil {
+		t.Fatalf("failed to join game: %v", err)
+	}
+
+	events := oracleGame.Subscribe(ctx)
+	if err := guesserGame.Ask(ctx, "Is it alive?"); err != nil {
+		t.Fatalf("failed to ask: %v", err)
+	}
+
+	for event := range events {
+		if event.Type == EventType_Reconnecting || event.Type == EventType_Closed {
+			t.Fatalf("subscription failed: %v", event.Err)
+		}
+		if event.State == nil {
+			t.Fatalf("%s event has no state: %v", event.Name, event.Err)
+		}
+		if event.State.Status == RoundStatus_AwaitingAnswer {
+			if question := event.State.Round.Entries[0].Question; question != "Is it alive?" {
+				t.Errorf("question = %q, want the question asked", question)
+			}
+			return
+		}
+	}
+	t.Fatal("subscription ended before the question arrived")
+}
+
+func TestParseGameID(t *testing.T) {
+	testCases := []struct {
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{"abc123", "abc123", false},
+		{"  abc123  ", "abc123", false},
+		{"http://localhost:3000/game/abc123/", "abc123", false},
+		{"http://localhost:3000/game/abc123/controls", "abc123", false},
+		{"https://example.com/prefix/game/abc123", "abc123", false},
+		{"", "", true},
+		{"http://localhost:3000/lobby/", "", true},
+		{"http://localhost:3000/game/", "", true},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.input, func(t *testing.T) {
+			got, err := ParseGameID(testCase.input)
+			if (err != nil) != testCase.wantErr {
+				t.Fatalf("error = %v, want error %v", err, testCase.wantErr)
+			}
+			if got != testCase.want {
+				t.Errorf("got %q, want %q", got, testCase.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,185 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	// The delay before the first reconnection attempt, doubled after each failed attempt up to the maximum.
+	reconnectDelayInitial time.Duration = 1 * time.Second
+	reconnectDelayMax     time.Duration = 30 * time.Second
+
+	// Number of events buffered in the subscription channel.
+	eventBufferSize int = 16
+)
+
+// Enum for EventType, determining what part of the game an event updates.
+type EventType int
+
+const (
+	// The rendered HTML of every question, answer and hint in the current round.
+	EventType_Responses EventType = iota
+
+	// The rendered HTML of the question proposals, in voting mode.
+	EventType_Proposals EventType = iota
+
+	// The rendered HTML of the scoreboard.
+	EventType_Scoreboard EventType = iota
+
+	// The rendered HTML of the chat.
+	EventType_Chat EventType = iota
+
+	// The controls of the player should be refetched, e.g. because the round ended or their role changed.
+	EventType_Controls EventType = iota
+
+	// The rendered HTML of the maintenance notice set by an admin -- empty when the notice is cleared.
+	EventType_Notice EventType = iota
+
+	// The connection was lost and is being reestablished -- the Err field holds the reason.
+	// The server sends the full state of the game again once reconnected.
+	EventType_Reconnecting EventType = iota
+
+	// The subscription has ended, e.g. because the game was deleted -- the Err field holds the reason.
+	// This is always the last event before the channel is closed, unless the context was cancelled.
+	EventType_Closed EventType = iota
+
+	// An event with a name this client does not know.
+	EventType_Unknown EventType = iota
+)
+
+// Get the event type from the name of a Server Sent Event.
+func eventTypeFromName(name string) EventType {
+	switch name {
+	case "message", "":
+		return EventType_Responses
+	case "proposals":
+		return EventType_Proposals
+	case "scoreboard":
+		return EventType_Scoreboard
+	case "chat":
+		return EventType_Chat
+	case "controls":
+		return EventType_Controls
+	case "notice":
+		return EventType_Notice
+	default:
+		return EventType_Unknown
+	}
+}
+
+// An update to a game, as sent by the server.
+type Event struct {
+	Type EventType
+
+	// The name of the Server Sent Event, and its data -- usually rendered HTML, meant for the browser.
+	Name string
+	Data string
+
+	// The state of the game after the update, fetched when the event arrived, so clients need not parse the HTML.
+	// Nil for EventType_Reconnecting and EventType_Closed events, and for events whose state could not be fetched,
+	// in which case the Err field holds the reason.
+	State *GameState
+
+	// The reason for EventType_Reconnecting and EventType_Closed events, or the reason the state could not be fetched.
+	Err error
+}
+
+// Subscribe to the updates of the game. The server first sends the full state of the game, then each update as it happens.
+// Each update carries the state of the game as it was when the update arrived.
+//
+// Lost connections are reestablished automatically, with an EventType_Reconnecting event sent for each attempt.
+// The channel is closed once the context is cancelled, or after an EventType_Closed event if the game can no longer be reached.
+func (game *Game) Subscribe(ctx context.Context) <-chan Event {
+	events := make(chan Event, eventBufferSize)
+
+	go func() {
+		defer close(events)
+
+		reconnectDelay := reconnectDelayInitial
+		for {
+			connected, err := game.streamEvents(ctx, events)
+			if ctx.Err() != nil {
+				return
+			}
+			if errors.Is(err, ErrNotFound) || errors.Is(err, ErrUnauthorized) {
+				select {
+				case events <- Event{Type: EventType_Closed, Err: err}:
+				case <-ctx.Done():
+				}
+				return
+			}
+
+			if connected {
+				reconnectDelay = reconnectDelayInitial
+			}
+			if err == nil {
+				err = errors.New("connection closed by server")
+			}
+			select {
+			case events <- Event{Type: EventType_Reconnecting, Err: err}:
+			case <-ctx.Done():
+				return
+			}
+
+			select {
+			case <-time.After(reconnectDelay):
+			case <-ctx.Done():
+				return
+			}
+			reconnectDelay = min(2*reconnectDelay, reconnectDelayMax)
+		}
+	}()
+	return events
+}
+
+// Open a single connection to the event stream, sending each event to the channel until the connection ends.
+// Returns true if the connection was established.
+func (game *Game) streamEvents(ctx context.Context, events chan<- Event) (bool, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, game.URL+"responsesSourceSSE", nil)
+	if err != nil {
+		return false, err
+	}
+	request.Header.Set("Accept", "text/event-stream")
+
+	response, err := game.client.httpClient.Do(request)
+	if err != nil {
+		return false, err
+	}
+	if response.StatusCode != http.StatusOK {
+		return false, statusErrorFromResponse(response)
+	}
+	defer response.Body.Close()
+
+	// Parse the SSE wire format -- an event is dispatched on each blank line, with multiple data fields joined by newlines.
+	scanner := bufio.NewScanner(response.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	eventName := ""
+	dataLines := make([]string, 0)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case line == "":
+			if len(dataLines) > 0 {
+				event := Event{Type: eventTypeFromName(eventName), Name: eventName, Data: strings.Join(dataLines, "\n")}
+				event.State, event.Err = game.State(ctx)
+				select {
+				case events <- event:
+				case <-ctx.Done():
+					return true, ctx.Err()
+				}
+			}
+			eventName = ""
+			dataLines = dataLines[:0]
+		case strings.HasPrefix(line, "event:"):
+			eventName = strings.TrimSpace(strings.TrimPrefix(line, "event:"))
+		case strings.HasPrefix(line, "data:"):
+			dataLines = append(dataLines, strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " "))
+		}
+	}
+	return true, scanner.Err()
+}
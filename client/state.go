@@ -0,0 +1,90 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/hmcalister/twentyquestions/transcript"
+)
+
+// Roles a player may have in the current round of a game.
+const (
+	Role_Oracle  = "oracle"
+	Role_Guesser = "guesser"
+)
+
+// Statuses of the current round of a game.
+const (
+	RoundStatus_AwaitingQuestion = "awaitingQuestion"
+	RoundStatus_AwaitingAnswer   = "awaitingAnswer"
+	RoundStatus_Over             = "over"
+)
+
+// The state of a game as the player of the client sees it. The secret is only included for the oracle until the round is over.
+type GameState struct {
+	GameID   string `json:"gameID"`
+	Title    string `json:"title,omitempty"`
+	Category string `json:"category,omitempty"`
+
+	// The role of the player in the current round, either Role_Oracle or Role_Guesser.
+	Role string `json:"role"`
+
+	// One of RoundStatus_AwaitingQuestion, RoundStatus_AwaitingAnswer or RoundStatus_Over.
+	Status             string `json:"status"`
+	QuestionsRemaining int    `json:"questionsRemaining"`
+
+	// Set once an admin has ended the game, so no new rounds may be started.
+	GameEnded bool `json:"gameEnded"`
+
+	// The cumulative scores, highest first.
+	Players []Player `json:"players"`
+
+	Round transcript.Round `json:"round"`
+
+	ChatEnabled bool          `json:"chatEnabled"`
+	Chat        []ChatMessage `json:"chat"`
+
+	// The maintenance notice set by an admin, or empty if there is none.
+	Notice string `json:"notice,omitempty"`
+}
+
+// Check if the player of the client is the oracle of the current round.
+func (state *GameState) IsOracle() bool {
+	return state.Role == Role_Oracle
+}
+
+// A player of a game and their score.
+type Player struct {
+	Name     string `json:"name"`
+	Score    int    `json:"score"`
+	IsOracle bool   `json:"isOracle"`
+
+	// Set for the player of the client.
+	IsYou bool `json:"isYou"`
+}
+
+// A message in the side chat of a game.
+type ChatMessage struct {
+	PlayerName string    `json:"playerName"`
+	Message    string    `json:"message"`
+	SentAt     time.Time `json:"sentAt"`
+}
+
+// Get the state of the game as the player of the client sees it.
+func (game *Game) State(ctx context.Context) (*GameState, error) {
+	// Read from the route of the game rather than the API, as the passphrase cookie is only sent to the game.
+	response, err := game.client.do(ctx, http.MethodGet, "/game/"+game.ID+"/state", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	var state GameState
+	err = json.NewDecoder(response.Body).Decode(&state)
+	if err != nil {
+		return nil, err
+	}
+	return &state, nil
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// A cookie saved to disk.
+type savedCookie struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+	Path  string `json:"path"`
+
+	// Zero for cookies that only last the browser session -- these are kept until the server replaces them.
+	Expires time.Time `json:"expires,omitempty"`
+}
+
+// Cookie jar that saves the cookies of a server to a file after every change, so each run of tq plays as the same player
+// and keeps the passphrases it has entered. The file holds the cookies of every server, by base URL.
+type persistentJar struct {
+	jar       *cookiejar.Jar
+	filename  string
+	serverURL *url.URL
+
+	// Every cookie the server has set, by name and path.
+	cookies map[string]savedCookie
+
+	// The last error writing the file -- reported once the game is over, rather than over the game screen.
+	saveErr error
+
+	// Mutex to ensure the file is not written by two requests at once.
+	mutex sync.Mutex
+}
+
+// Get the default path of the cookie file, in the user configuration directory.
+func defaultCookieFile() string {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(configDir, "twentyquestions", "cookies.json")
+}
+
+// Load the saved cookies of the server from the file, leaving out any that have expired.
+// A missing file is an empty jar -- the file is created on the first change.
+func loadPersistentJar(filename string, serverURL *url.URL) (*persistentJar, error) {
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		return nil, err
+	}
+	persistent := &persistentJar{
+		jar:       jar,
+		filename:  filename,
+		serverURL: serverURL,
+		cookies:   make(map[string]savedCookie),
+	}
+
+	servers, err := persistent.readFile()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	for _, cookie := range servers[serverURL.String()] {
+		if !cookie.Expires.IsZero() && cookie.Expires.Before(now) {
+			continue
+		}
+		persistent.cookies[cookie.Name+" "+cookie.Path] = cookie
+		jar.SetCookies(serverURL, []*http.Cookie{{Name: cookie.Name, Value: cookie.Value, Path: cookie.Path, Expires: cookie.Expires}})
+	}
+	return persistent, nil
+}
+
+// Read the cookies of every server from the file.
+func (persistent *persistentJar) readFile() (map[string][]savedCookie, error) {
+	servers := make(map[string][]savedCookie)
+	fileBytes, err := os.ReadFile(persistent.filename)
+	if errors.Is(err, fs.ErrNotExist) {
+		return servers, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	err = json.Unmarshal(fileBytes, &servers)
+	return servers, err
+}
+
+// Write the cookies of the server to the file, keeping those of every other server.
+//
+// The caller must hold the mutex.
+func (persistent *persistentJar) save() error {
+	servers, err := persistent.readFile()
+	if err != nil {
+		return err
+	}
+	cookies := make([]savedCookie, 0, len(persistent.cookies))
+	for _, cookie := range persistent.cookies {
+		cookies = append(cookies, cookie)
+	}
+	servers[persistent.serverURL.String()] = cookies
+
+	fileBytes, err := json.MarshalIndent(servers, "", "  ")
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(filepath.Dir(persistent.filename), 0700)
+	if err != nil {
+		return err
+	}
+	// The cookies identify the player, so only the user may read them.
+	return os.WriteFile(persistent.filename, fileBytes, 0600)
+}
+
+// Set the cookies of a response, saving them to the file.
+func (persistent *persistentJar) SetCookies(u *url.URL, cookies []*http.Cookie) {
+	persistent.jar.SetCookies(u, cookies)
+
+	persistent.mutex.Lock()
+	defer persistent.mutex.Unlock()
+	for _, cookie := range cookies {
+		path := cookie.Path
+		if path == "" {
+			path = "/"
+		}
+		key := cookie.Name + " " + path
+		expires := cookie.Expires
+		if cookie.MaxAge > 0 {
+			expires = time.Now().Add(time.Duration(cookie.MaxAge) * time.Second)
+		}
+		if cookie.MaxAge < 0 || (!expires.IsZero() && expires.Before(time.Now())) {
+			delete(persistent.cookies, key)
+			continue
+		}
+		persistent.cookies[key] = savedCookie{Name: cookie.Name, Value: cookie.Value, Path: path, Expires: expires}
+	}
+
+	// Only the next run loses out if the file cannot be written, so the game goes on.
+	persistent.saveErr = persistent.save()
+}
+
+// Get the last error writing the file, if any.
+func (persistent *persistentJar) err() error {
+	persistent.mutex.Lock()
+	defer persistent.mutex.Unlock()
+	return persistent.saveErr
+}
+
+// Get the cookies to send with a request.
+func (persistent *persistentJar) Cookies(u *url.URL) []*http.Cookie {
+	return persistent.jar.Cookies(u)
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestPersistentJar(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "cookies.json")
+	serverURL, _ := url.Parse("http://localhost:3000")
+	otherServerURL, _ := url.Parse("http://example.com")
+	gameURL, _ := url.Parse("http://localhost:3000/game/abc/")
+
+	jar, err := loadPersistentJar(filename, serverURL)
+	if err != nil {
+		t.Fatalf("failed to load jar: %v", err)
+	}
+	jar.SetCookies(serverURL, []*http.Cookie{
+		{Name: "player", Value: "token", Path: "/", Expires: time.Now().Add(time.Hour)},
+		{Name: "stale", Value: "gone", Path: "/", MaxAge: -1},
+	})
+	jar.SetCookies(gameURL, []*http.Cookie{{Name: "abc", Value: "admitted", Path: "/game/abc/", MaxAge: 3600}})
+
+	otherJar, err := loadPersistentJar(filename, otherServerURL)
+	if err != nil {
+		t.Fatalf("failed to load jar: %v", err)
+	}
+	otherJar.SetCookies(otherServerURL, []*http.Cookie{{Name: "player", Value: "other", Path: "/"}})
+	if err := jar.err(); err != nil {
+		t.Fatalf("failed to save jar: %v", err)
+	}
+
+	reloaded, err := loadPersistentJar(filename, serverURL)
+	if err != nil {
+		t.Fatalf("failed to reload jar: %v", err)
+	}
+
+	testCases := []struct {
+		name string
+		url  *url.URL
+		want map[string]string
+	}{
+		{"server root", serverURL, map[string]string{"player": "token"}},
+		{"game path", gameURL, map[string]string{"player": "token", "abc": "admitted"}},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			got := make(map[string]string)
+			for _, cookie := range reloaded.Cookies(testCase.url) {
+				got[cookie.Name] = cookie.Value
+			}
+			if len(got) != len(testCase.want) {
+				t.Fatalf("got cookies %v, want %v", got, testCase.want)
+			}
+			for name, value := range testCase.want {
+				if got[name] != value {
+					t.Errorf("cookie %s = %q, want %q", name, got[name], value)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,363 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net/url"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hmcalister/twentyquestions/client"
+)
+
+const (
+	// Number of chat messages shown below the game log.
+	chatLinesShown int = 5
+
+	// ANSI sequence to clear the terminal and move the cursor to the top left.
+	clearScreen string = "\033[H\033[2J"
+)
+
+const usage = `tq is a terminal client for Twenty Questions.
+
+Usage:
+  tq [-server URL] new [options]       Create a game as the oracle, printing the link for guessers
+  tq [-server URL] join [options] GAME Join a game by its URL or ID, as a guesser or with -oracle as the oracle
+
+Your player and the passphrases you enter are saved to -cookies, so you play as the same player on every run
+and can rejoin your games. Pass -cookies "" to play as a new player.
+
+Run "tq new -h" or "tq join -h" for the options of each command.
+`
+
+const commandHelp = `Type a question (as a guesser) or an answer (as the oracle) and press enter. Commands:
+  /correct          End the round with a correct verdict (oracle)
+  /incorrect        End the round with an incorrect verdict (oracle)
+  /hint TEXT        Give a hint, costing the guessers questions (oracle)
+  /secret TEXT      Record the secret, revealed once the round is over (oracle)
+  /next             Start the next round, once the round is over
+  /name NAME        Change your name
+  /chat TEXT        Send a chat message
+  /help             Show this help
+  /quit             Leave the game`
+
+func main() {
+	flag.Usage = func() { fmt.Fprint(os.Stderr, usage) }
+	server := flag.String("server", "http://localhost:3000", "The URL of the Twenty Questions server.")
+	cookieFile := flag.String("cookies", defaultCookieFile(), "The file your player is saved to. Empty to play as a new player.")
+	flag.Parse()
+
+	if flag.NArg() < 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+
+	var err error
+
+	var tqClient *client.Client
+	var jar *persistentJar
+	if *cookieFile == "" {
+		tqClient, err = client.New(*server)
+	} else {
+		jar, err = openJar(*cookieFile, *server)
+		if err == nil {
+			tqClient, err = client.NewWithJar(*server, jar)
+		}
+	}
+	if err != nil {
+		fatal(err)
+	}
+
+	var game *client.Game
+	var name string
+	var wantOracle bool
+	switch flag.Arg(0) {
+	case "new":
+		game, name, err = newGame(ctx, tqClient, flag.Args()[1:])
+	case "join":
+		game, name, wantOracle, err = joinGame(ctx, tqClient, flag.Args()[1:])
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+	if err != nil {
+		fatal(err)
+	}
+
+	if name != "" {
+		err = game.SetName(ctx, name)
+		if err != nil {
+			fatal(err)
+		}
+	}
+
+	// Printed outside the game screen too, so the role is still in the terminal history after quitting.
+	state, err := game.State(ctx)
+	if err != nil {
+		fatal(err)
+	}
+	if wantOracle && !state.IsOracle() {
+		fatal(fmt.Errorf("you are not the oracle of game %s -- %s is", game.ID, state.Round.OracleName))
+	}
+	fmt.Println(describeRole(state))
+
+	play(ctx, game)
+	if jar != nil && jar.err() != nil {
+		fmt.Fprintln(os.Stderr, "tq: failed to save your player:", jar.err())
+	}
+}
+
+// Print the error and exit.
+func fatal(err error) {
+	fmt.Fprintln(os.Stderr, "tq:", err)
+	os.Exit(1)
+}
+
+// --------------------------------------------------------------------------------
+// Commands
+// --------------------------------------------------------------------------------
+
+// Create a game from the options of the new command, printing the link for guessers.
+func newGame(ctx context.Context, tqClient *client.Client, arguments []string) (*client.Game, string, error) {
+	flags := flag.NewFlagSet("new", flag.ExitOnError)
+	name := flags.String("name", "", "Your name in the game.")
+	title := flags.String("title", "", "The title of the game, shown in the lobby.")
+	category := flags.String("category", "", "The category of the game, e.g. Animals.")
+	public := flags.Bool("public", false, "List the game in the public lobby.")
+	rotation := flags.String("rotation", "", "How the next oracle is chosen, either roundRobin or winner.")
+	questionBudget := flags.Int("budget", 0, "The number of questions per round. Zero for the server default.")
+	passphrase := flags.String("passphrase", "", "A passphrase guessers must enter to join.")
+	pickSecret := flags.Bool("pickSecret", false, "Pick a secret for the oracle from the category.")
+	computerOracle := flags.Bool("computerOracle", false, "Play against the computer oracle, as a guesser.")
+	flags.Parse(arguments)
+
+	game, err := tqClient.CreateGame(ctx, client.GameOptions{
+		Title:          *title,
+		Category:       *category,
+		Public:         *public,
+		Rotation:       *rotation,
+		QuestionBudget: *questionBudget,
+		Passphrase:     *passphrase,
+		PickSecret:     *pickSecret,
+		ComputerOracle: *computerOracle,
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	// Printed outside the game screen too, so the link is still in the terminal history after quitting.
+	fmt.Printf("Created game %s\nSend this link to the guessers: %s\n", game.ID, game.URL)
+	if *passphrase != "" {
+		fmt.Println("Guessers will need the passphrase to join.")
+	}
+	return game, *name, nil
+}
+
+// Join the game given to the join command. Returns true if the player asked to join as the oracle.
+func joinGame(ctx context.Context, tqClient *client.Client, arguments []string) (*client.Game, string, bool, error) {
+	flags := flag.NewFlagSet("join", flag.ExitOnError)
+	name := flags.String("name", "", "Your name in the game.")
+	passphrase := flags.String("passphrase", "", "The passphrase of the game, if it is protected.")
+	oracle := flags.Bool("oracle", false, "Join as the oracle, e.g. to rejoin a game you created. Fails unless you are the oracle of the current round.")
+	flags.Parse(arguments)
+
+	if flags.NArg() != 1 {
+		return nil, "", false, errors.New("join needs exactly one game URL or ID")
+	}
+	game, err := tqClient.Join(ctx, flags.Arg(0), *passphrase)
+	return game, *name, *oracle, err
+}
+
+// Open the cookie jar of the server, saved in the cookie file.
+func openJar(cookieFile string, server string) (*persistentJar, error) {
+	serverURL, err := url.Parse(strings.TrimSuffix(server, "/"))
+	if err != nil {
+		return nil, err
+	}
+	return loadPersistentJar(cookieFile, serverURL)
+}
+
+// --------------------------------------------------------------------------------
+// Playing
+// --------------------------------------------------------------------------------
+
+// The latest state of the game, drawn as text.
+type screen struct {
+	game *client.Game
+
+	// Nil until the first event arrives.
+	state *client.GameState
+
+	// A message about the last command or the connection, shown at the bottom of the screen.
+	status string
+
+	// Mutex to ensure the screen is not drawn while it is being updated.
+	screenMutex sync.Mutex
+}
+
+// Redraw the whole screen.
+//
+// The caller must hold the screenMutex.
+func (gameScreen *screen) draw() {
+	var output strings.Builder
+	output.WriteString(clearScreen)
+	fmt.Fprintf(&output, "Twenty Questions - %s\n", gameScreen.game.URL)
+	if state := gameScreen.state; state != nil {
+		if state.Notice != "" {
+			fmt.Fprintf(&output, "Notice: %s\n", state.Notice)
+		}
+		fmt.Fprintf(&output, "Scores: %s\n", renderScores(state))
+		output.WriteString(strings.Repeat("-", 80) + "\n")
+		if entries := renderEntries(state.Round); entries != "" {
+			output.WriteString(entries + "\n")
+		}
+		output.WriteString(renderRoundStatus(state) + "\n")
+
+		if len(state.Chat) > 0 {
+			output.WriteString(strings.Repeat("-", 80) + "\nChat:\n")
+			output.WriteString(renderChat(state.Chat, chatLinesShown) + "\n")
+		}
+	}
+
+	output.WriteString(strings.Repeat("-", 80) + "\n")
+	if gameScreen.status != "" {
+		output.WriteString(gameScreen.status + "\n")
+	}
+	output.WriteString("> ")
+	fmt.Print(output.String())
+}
+
+// Update the screen with an event from the game. Returns false if the game has ended.
+func (gameScreen *screen) update(event client.Event) bool {
+	gameScreen.screenMutex.Lock()
+	defer gameScreen.screenMutex.Unlock()
+
+	switch event.Type {
+	case client.EventType_Reconnecting:
+		gameScreen.status = fmt.Sprintf("Connection lost (%v), reconnecting...", event.Err)
+	case client.EventType_Closed:
+		gameScreen.status = fmt.Sprintf("The game has ended: %v", event.Err)
+		gameScreen.draw()
+		fmt.Println()
+		return false
+	default:
+		if event.State == nil {
+			gameScreen.status = fmt.Sprintf("Failed to fetch the game: %v", event.Err)
+		} else {
+			gameScreen.state = event.State
+		}
+	}
+	gameScreen.draw()
+	return true
+}
+
+// Show a message about the last command.
+func (gameScreen *screen) setStatus(status string) {
+	gameScreen.screenMutex.Lock()
+	defer gameScreen.screenMutex.Unlock()
+	gameScreen.status = status
+	gameScreen.draw()
+}
+
+// Play the game interactively, until the player quits or the game ends.
+func play(ctx context.Context, game *client.Game) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	gameScreen := &screen{game: game, status: "Other players join with the link above. Type /help for commands."}
+	go func() {
+		for event := range game.Subscribe(ctx) {
+			if !gameScreen.update(event) {
+				cancel()
+				return
+			}
+		}
+	}()
+
+	lines := make(chan string)
+	go func() {
+		scanner := bufio.NewScanner(os.Stdin)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+		close(lines)
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case line, ok := <-lines:
+			if !ok {
+				return
+			}
+			line = strings.TrimSpace(line)
+			if line == "" {
+				continue
+			}
+			if line == "/quit" {
+				return
+			}
+			gameScreen.setStatus(runCommand(ctx, game, line))
+		}
+	}
+}
+
+// Run a line of input as a command, returning the status to show.
+func runCommand(ctx context.Context, game *client.Game, line string) string {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	command, argument, _ := strings.Cut(line, " ")
+	argument = strings.TrimSpace(argument)
+
+	var err error
+	switch {
+	case !strings.HasPrefix(line, "/"):
+		err = game.Ask(ctx, line)
+	case command == "/correct":
+		err = game.Verdict(ctx, true)
+	case command == "/incorrect":
+		err = game.Verdict(ctx, false)
+	case command == "/hint" && argument != "":
+		err = game.Hint(ctx, argument)
+	case command == "/secret" && argument != "":
+		err = game.SetSecret(ctx, argument)
+		if err == nil {
+			return fmt.Sprintf("Secret recorded: %s", argument)
+		}
+	case command == "/next":
+		err = game.NextRound(ctx)
+	case command == "/name" && argument != "":
+		err = game.SetName(ctx, argument)
+		if err == nil {
+			return fmt.Sprintf("Name changed to %s.", argument)
+		}
+	case command == "/chat" && argument != "":
+		err = game.Chat(ctx, argument)
+	case command == "/help":
+		return commandHelp
+	default:
+		return "Unknown command, or missing text. Type /help for commands."
+	}
+
+	switch {
+	case err == nil:
+		return ""
+	case errors.Is(err, client.ErrUnauthorized):
+		return "Only the oracle can do that."
+	case errors.Is(err, client.ErrNotAllowedNow):
+		return "Not allowed right now -- it may not be your turn."
+	default:
+		return fmt.Sprintf("Error: %v", err)
+	}
+}
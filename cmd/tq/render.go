@@ -0,0 +1,89 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hmcalister/twentyquestions/client"
+	"github.com/hmcalister/twentyquestions/transcript"
+)
+
+// Describe the role of the player in the current round, e.g. when joining.
+func describeRole(state *client.GameState) string {
+	if state.IsOracle() {
+		return fmt.Sprintf("You are the oracle of round %d -- answer the questions of the guessers.", state.Round.Number)
+	}
+	return fmt.Sprintf("You are a guesser in round %d -- %s is the oracle.", state.Round.Number, state.Round.OracleName)
+}
+
+// Render the scores of the players on a single line, marking the oracle and the player.
+func renderScores(state *client.GameState) string {
+	scores := make([]string, 0, len(state.Players))
+	for _, player := range state.Players {
+		score := fmt.Sprintf("%s %d", player.Name, player.Score)
+		if player.IsOracle {
+			score += " (oracle)"
+		}
+		if player.IsYou {
+			score += " (you)"
+		}
+		scores = append(scores, score)
+	}
+	return strings.Join(scores, " | ")
+}
+
+// Describe the status of the current round, from the point of view of the player.
+func renderRoundStatus(state *client.GameState) string {
+	round := state.Round
+	switch round.Outcome {
+	case transcript.Outcome_Correct:
+		return fmt.Sprintf("Round %d is over -- %s guessed correctly. Type /next for the next round.", round.Number, round.WinnerName)
+	case transcript.Outcome_Incorrect:
+		return fmt.Sprintf("Round %d is over -- nobody guessed correctly. Type /next for the next round.", round.Number)
+	case transcript.Outcome_Ended:
+		return fmt.Sprintf("Round %d was ended by an admin.", round.Number)
+	}
+
+	switch {
+	case state.Status == client.RoundStatus_AwaitingAnswer && state.IsOracle():
+		return fmt.Sprintf("Round %d -- type your answer.", round.Number)
+	case state.Status == client.RoundStatus_AwaitingAnswer:
+		return fmt.Sprintf("Round %d -- waiting for %s to answer.", round.Number, round.OracleName)
+	case state.IsOracle():
+		return fmt.Sprintf("Round %d -- waiting for a question, %d remaining.", round.Number, state.QuestionsRemaining)
+	default:
+		return fmt.Sprintf("Round %d -- type a question, %d remaining.", round.Number, state.QuestionsRemaining)
+	}
+}
+
+// Render the questions, answers and hints of the current round, one line each, so answers stand out from questions.
+func renderEntries(round transcript.Round) string {
+	lines := make([]string, 0, 2*len(round.Entries)+1)
+	for _, entry := range round.Entries {
+		switch {
+		case entry.Kind == transcript.EntryKind_Hint:
+			lines = append(lines, "  * Hint: "+entry.Hint)
+		case entry.Retracted:
+			lines = append(lines, fmt.Sprintf("   %s: %s (retracted)", entry.AskerName, entry.Question))
+		default:
+			lines = append(lines, fmt.Sprintf("%d. %s: %s", entry.Index, entry.AskerName, entry.Question))
+			if entry.AnsweredAt != nil {
+				lines = append(lines, "    => "+entry.Answer)
+			}
+		}
+	}
+	if round.Secret != "" {
+		lines = append(lines, "Secret: "+round.Secret)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Render the last few chat messages, one line each.
+func renderChat(chat []client.ChatMessage, limit int) string {
+	chat = chat[max(len(chat)-limit, 0):]
+	lines := make([]string, 0, len(chat))
+	for _, message := range chat {
+		lines = append(lines, fmt.Sprintf("%s: %s", message.PlayerName, message.Message))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,11 @@
+// Package data holds the data files the server cannot run without, embedded so the server does not depend on its working directory.
+//
+// The word packs and knowledge base in this directory are loaded from disk instead, as they may be replaced when deploying.
+package data
+
+import _ "embed"
+
+// The OpenAPI document describing the JSON API.
+//
+//go:embed openapi.json
+var OpenAPIDocument []byte
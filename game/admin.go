@@ -0,0 +1,346 @@
+package game
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"html/template"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog/log"
+
+	"github.com/hmcalister/twentyquestions/templates"
+)
+
+const (
+	// Longest a game may be extended by at once.
+	maxGameExtension time.Duration = 7 * 24 * time.Hour
+
+	// Longest maintenance notice an admin may set, in bytes.
+	maxNoticeLength int = 500
+)
+
+var (
+	adminGamesTemplate    = template.Must(template.New("adminGames.html").ParseFS(templates.Files, "adminGames.html"))
+	adminSpectateTemplate = template.Must(template.New("adminSpectate.html").ParseFS(templates.Files, "adminSpectate.html"))
+	noticeTemplate        = template.Must(template.New("notice.html").ParseFS(templates.Files, "notice.html"))
+)
+
+var (
+	// The identity admin actions are recorded under, in the audit log and the events of a round.
+	adminIdentity = playerIdentity{
+		ID:   "admin",
+		Name: "Admin",
+	}
+)
+
+// Actions taken by an admin, recorded in the audit log.
+const (
+	auditAction_AdminEnded    = "adminEnded"
+	auditAction_AdminDeleted  = "adminDeleted"
+	auditAction_AdminExtended = "adminExtended"
+)
+
+// --------------------------------------------------------------------------------
+// Session Management
+// --------------------------------------------------------------------------------
+
+// Check if an admin has ended the session.
+func (session *GameSession) hasEnded() bool {
+	session.sessionMutex.Lock()
+	defer session.sessionMutex.Unlock()
+	return session.ended
+}
+
+// Get the time the session will be deleted.
+func (session *GameSession) expiry() time.Time {
+	session.sessionMutex.Lock()
+	defer session.sessionMutex.Unlock()
+	return session.expiresAt
+}
+
+// Delay the deletion of the session by the given duration.
+func (session *GameSession) extend(duration time.Duration) {
+	session.sessionMutex.Lock()
+	defer session.sessionMutex.Unlock()
+	session.expiresAt = session.expiresAt.Add(duration)
+	session.expiryTimer.Reset(time.Until(session.expiresAt))
+}
+
+// End the session, ending the current round without a winner so no more questions may be asked and no new rounds started.
+// Players stay connected, and the session is deleted when it expires as usual. Returns an error if the session has already ended.
+func (session *GameSession) forceEnd() error {
+	session.sessionMutex.Lock()
+	if session.ended {
+		session.sessionMutex.Unlock()
+		return errors.New("game has already ended")
+	}
+	session.ended = true
+	round := session.rounds[len(session.rounds)-1]
+	session.sessionMutex.Unlock()
+
+	// A round that was already over has already been archived and published.
+	if round.forceEnd() == nil {
+		session.saveToArchive()
+		session.publishGameOver(round)
+	}
+
+	session.broadcastResponses(round)
+	session.broadcaster.broadcast(round.proposalsEvent())
+	session.broadcaster.broadcast(controlsEvent())
+	log.Info().Str("GameID", session.gameID).Msg("Game Ended By Admin")
+	return nil
+}
+
+// Record an action by an admin in the audit log, against the current round of the session.
+func (session *GameSession) auditAdmin(action string, payload string) {
+	session.currentRound().audit(action, adminIdentity, payload)
+}
+
+// Describe the state of the round for the admin console.
+func (data *GameData) stateDescription() string {
+	data.gameStateMutex.Lock()
+	defer data.gameStateMutex.Unlock()
+
+	switch {
+	case data.forceEnded:
+		return "Ended by admin"
+	case data.gameState == gameState_AwaitingQuestion:
+		return "Awaiting question"
+	case data.gameState == gameState_AwaitingAnswer:
+		return "Awaiting answer"
+	default:
+		return "Round over"
+	}
+}
+
+// --------------------------------------------------------------------------------
+// Maintenance Notice
+// --------------------------------------------------------------------------------
+
+// Render the maintenance notice, or an empty event that clears it if there is none.
+func (master *GameMaster) noticeEvent() sseEvent {
+	master.noticeMutex.Lock()
+	notice := master.notice
+	master.noticeMutex.Unlock()
+
+	var noticeBytes bytes.Buffer
+	err := noticeTemplate.Execute(&noticeBytes, notice)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to write notice template")
+	}
+	return sseEvent{name: "notice", data: noticeBytes.String()}
+}
+
+// Set the maintenance notice, sending it to every client in the lobby and in every game. An empty notice clears it.
+func (master *GameMaster) setNotice(notice string) {
+	master.noticeMutex.Lock()
+	master.notice = notice
+	master.noticeMutex.Unlock()
+
+	event := master.noticeEvent()
+	master.lobbyBroadcaster.broadcast(event)
+	for _, session := range master.sessions() {
+		session.broadcaster.broadcast(event)
+	}
+	log.Info().Str("Notice", notice).Msg("Maintenance Notice Set")
+}
+
+// Get every session currently alive.
+func (master *GameMaster) sessions() []*GameSession {
+	master.gameMapMutex.RLock()
+	defer master.gameMapMutex.RUnlock()
+
+	sessions := make([]*GameSession, 0, len(master.gameMap))
+	for _, session := range master.gameMap {
+		sessions = append(sessions, session)
+	}
+	return sessions
+}
+
+// --------------------------------------------------------------------------------
+// Routing Functions
+// --------------------------------------------------------------------------------
+
+// Data to be passed to adminGames.html template
+type adminGamesTemplateData struct {
+	Games  []adminGameListing
+	Notice string
+}
+
+// Summary of a single game for the admin console.
+type adminGameListing struct {
+	GameID      string
+	Title       string
+	Public      bool
+	Locked      bool
+	Round       int
+	State       string
+	Players     []string
+	Connections int
+	Age         time.Duration
+	ExpiresIn   time.Duration
+}
+
+// Summarize the session for the admin console.
+func (session *GameSession) adminListing() adminGameListing {
+	session.sessionMutex.Lock()
+	round := session.rounds[len(session.rounds)-1]
+	playerNames := make([]string, 0, len(session.players))
+	for _, player := range session.players {
+		playerNames = append(playerNames, player.Name)
+	}
+	expiresAt := session.expiresAt
+	session.sessionMutex.Unlock()
+
+	return adminGameListing{
+		GameID:      session.gameID,
+		Title:       session.config.title,
+		Public:      session.config.isPublic,
+		Locked:      session.hasPassphrase(),
+		Round:       round.roundNumber,
+		State:       round.stateDescription(),
+		Players:     playerNames,
+		Connections: session.broadcaster.clientCount(),
+		Age:         time.Since(session.createdAt).Round(time.Second),
+		ExpiresIn:   time.Until(expiresAt).Round(time.Second),
+	}
+}
+
+// Find the game named in the URL, writing a 404 and returning nil if it does not exist.
+func (master *GameMaster) adminSession(w http.ResponseWriter, r *http.Request) *GameSession {
+	master.gameMapMutex.RLock()
+	session, ok := master.gameMap[chi.URLParam(r, "gameID")]
+	master.gameMapMutex.RUnlock()
+	if !ok {
+		http.Error(w, "game not found", http.StatusNotFound)
+		return nil
+	}
+	return session
+}
+
+// Render the admin console -- every live game, newest first, and the maintenance notice.
+func (master *GameMaster) renderAdminGames(w http.ResponseWriter, r *http.Request) {
+	sessions := master.sessions()
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].createdAt.After(sessions[j].createdAt)
+	})
+
+	templateData := adminGamesTemplateData{
+		Games: make([]adminGameListing, 0, len(sessions)),
+	}
+	for _, session := range sessions {
+		templateData.Games = append(templateData.Games, session.adminListing())
+	}
+	master.noticeMutex.Lock()
+	templateData.Notice = master.notice
+	master.noticeMutex.Unlock()
+
+	err := adminGamesTemplate.Execute(w, templateData)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to write admin games template")
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
+
+// Render a game for an admin to watch, without joining it as a player.
+func (master *GameMaster) renderAdminSpectate(w http.ResponseWriter, r *http.Request) {
+	session := master.adminSession(w, r)
+	if session == nil {
+		return
+	}
+
+	err := adminSpectateTemplate.Execute(w, session.adminListing())
+	if err != nil {
+		log.Error().Str("GameID", session.gameID).Err(err).Msg("Failed to write admin spectate template")
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
+
+// SSE endpoint for an admin watching a game -- sends the same updates as players receive, without joining the session.
+// Admins are let in even if the game is full, and do not take the place of a player.
+func (master *GameMaster) adminSpectateSSE(w http.ResponseWriter, r *http.Request) {
+	session := master.adminSession(w, r)
+	if session == nil {
+		return
+	}
+
+	session.broadcaster.serveSpectator(w, r, func() []sseEvent {
+		round := session.currentRound()
+		return []sseEvent{
+			{name: "message", data: round.responsesHTML()},
+			round.proposalsEvent(),
+			session.scoreboardEvent(),
+			session.chatEvent(),
+			master.noticeEvent(),
+		}
+	})
+}
+
+// End a game, so no more questions may be asked and no new rounds started.
+func (master *GameMaster) adminEndGame(w http.ResponseWriter, r *http.Request) {
+	session := master.adminSession(w, r)
+	if session == nil {
+		return
+	}
+
+	err := session.forceEnd()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	session.auditAdmin(auditAction_AdminEnded, "")
+	session.notifyListingChange()
+	http.Redirect(w, r, "/admin/", http.StatusSeeOther)
+}
+
+// Delete a game now, archiving it and disconnecting every player.
+func (master *GameMaster) adminDeleteGame(w http.ResponseWriter, r *http.Request) {
+	session := master.adminSession(w, r)
+	if session == nil {
+		return
+	}
+
+	session.auditAdmin(auditAction_AdminDeleted, "")
+	master.deleteSession(session)
+	http.Redirect(w, r, "/admin/", http.StatusSeeOther)
+}
+
+// Delay the deletion of a game by the form value duration (e.g. "2h"), or by the usual lifetime of a game if not given.
+func (master *GameMaster) adminExtendGame(w http.ResponseWriter, r *http.Request) {
+	session := master.adminSession(w, r)
+	if session == nil {
+		return
+	}
+
+	duration := gameDuration
+	if durationValue := r.FormValue("duration"); durationValue != "" {
+		var err error
+		duration, err = time.ParseDuration(durationValue)
+		if err != nil || duration <= 0 || duration > maxGameExtension {
+			http.Error(w, fmt.Sprintf("duration must be positive and at most %s", maxGameExtension), http.StatusBadRequest)
+			return
+		}
+	}
+
+	session.extend(duration)
+	session.auditAdmin(auditAction_AdminExtended, duration.String())
+	log.Info().Str("GameID", session.gameID).Dur("Duration", duration).Time("ExpiresAt", session.expiry()).Msg("Game Extended By Admin")
+	http.Redirect(w, r, "/admin/", http.StatusSeeOther)
+}
+
+// Set the maintenance notice from the form value notice, or clear it if empty.
+func (master *GameMaster) adminSetNotice(w http.ResponseWriter, r *http.Request) {
+	notice := strings.TrimSpace(r.FormValue("notice"))
+	if len(notice) > maxNoticeLength {
+		http.Error(w, fmt.Sprintf("notice must be at most %d bytes", maxNoticeLength), http.StatusBadRequest)
+		return
+	}
+
+	master.setNotice(notice)
+	http.Redirect(w, r, "/admin/", http.StatusSeeOther)
+}
@@ -0,0 +1,70 @@
+package game
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestSpectatorsAreNotLimited(t *testing.T) {
+	broadcaster := newSSEBroadcaster(1)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if _, err := broadcaster.subscribe(ctx); err != nil {
+		t.Fatalf("first player could not subscribe: %v", err)
+	}
+	if _, err := broadcaster.subscribe(ctx); err != errBroadcasterFull {
+		t.Fatalf("second player subscribed to a full broadcaster, err = %v", err)
+	}
+
+	spectator := broadcaster.subscribeSpectator(ctx)
+	if spectator == nil {
+		t.Fatal("spectator could not subscribe to a full broadcaster")
+	}
+	if count := broadcaster.clientCount(); count != 1 {
+		t.Errorf("client count = %d, want 1 -- spectators are not counted", count)
+	}
+
+	broadcaster.broadcast(sseEvent{name: "message", data: "update"})
+	if event := <-spectator.eventsChannel; event.data != "update" {
+		t.Errorf("spectator received %q, want the broadcast event", event.data)
+	}
+}
+
+func TestForceEndRecordsRoundEnded(t *testing.T) {
+	server := newTestServer(t)
+	oracle := server.newPlayer(t)
+	guesser := server.newPlayer(t)
+
+	gameID := oracle.createGame(url.Values{})
+	guesser.join(gameID)
+	guesser.mustPost(gameID, "submitResponse", url.Values{"response": {"Is it blue?"}})
+
+	session, _ := server.master.sessionByID(gameID)
+	if err := session.forceEnd(); err != nil {
+		t.Fatalf("failed to end game: %v", err)
+	}
+	if err := session.forceEnd(); err == nil {
+		t.Error("ending an ended game succeeded, want an error")
+	}
+
+	round := session.currentRound()
+	lastEvent := round.events[len(round.events)-1]
+	if lastEvent.Kind != roundEventKind_RoundEnded || lastEvent.PlayerName != adminIdentity.Name {
+		t.Errorf("last event = %s by %s, want %s by %s", lastEvent.Kind, lastEvent.PlayerName, roundEventKind_RoundEnded, adminIdentity.Name)
+	}
+	if round.roundWinnerID() != "" {
+		t.Errorf("ended round has winner %s, want none", round.roundWinnerID())
+	}
+
+	status, body := guesser.get("/game/" + gameID + "/replay")
+	if status != http.StatusOK || !strings.Contains(body, string(roundEventKind_RoundEnded)) {
+		t.Errorf("replay responded %d without the ended event", status)
+	}
+	if status, _ := guesser.post("/game/"+gameID+"/nextRound", nil); status != http.StatusBadRequest {
+		t.Errorf("starting a round of an ended game responded %d, want %d", status, http.StatusBadRequest)
+	}
+}
@@ -0,0 +1,146 @@
+package game
+
+import (
+	"fmt"
+	"html/template"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog/log"
+
+	"github.com/hmcalister/twentyquestions/archive"
+	"github.com/hmcalister/twentyquestions/templates"
+	"github.com/hmcalister/twentyquestions/transcript"
+)
+
+const (
+	// Maximum number of games shown when browsing or searching the archive.
+	archiveSearchLimit int = 50
+)
+
+var (
+	// Templates for the archive of completed games.
+	archiveTemplate = template.Must(template.New("archive.html").Funcs(template.FuncMap{
+		"formatTimestamp": formatTimestamp,
+		"formatElapsed":   formatElapsed,
+	}).ParseFS(templates.Files, "archive.html", "archiveGame.html"))
+)
+
+// The permanent URL of the session in the archive.
+func archiveURL(gameID string) string {
+	return fmt.Sprintf("/archive/%s", gameID)
+}
+
+// Check if the session is kept in the archive once rounds are completed. Games protected by a passphrase are never archived.
+func (session *GameSession) isArchived() bool {
+	return !session.hasPassphrase()
+}
+
+// Save the completed rounds of the session into the archive, replacing any previous record.
+// Does nothing if the session is not archived or no round has been completed.
+func (session *GameSession) saveToArchive() {
+	if !session.isArchived() {
+		return
+	}
+
+	// Without a player, only the completed rounds are included -- with their secrets revealed.
+	completedTranscript := session.transcriptFor(playerIdentity{})
+	if len(completedTranscript.Rounds) == 0 {
+		return
+	}
+
+	err := session.master.archive.Save(archive.Record{
+		Transcript: completedTranscript,
+		Category:   session.config.category,
+		Listed:     session.config.isPublic,
+	})
+	if err != nil {
+		log.Error().Str("GameID", session.gameID).Err(err).Msg("Failed to archive game")
+	}
+}
+
+// --------------------------------------------------------------------------------
+// Routing Functions
+// --------------------------------------------------------------------------------
+
+// Data to be passed to archive.html template
+type archiveTemplateData struct {
+	Query     string
+	Summaries []archive.Summary
+}
+
+// Data to be passed to archiveGame.html template
+type archiveGameTemplateData struct {
+	Record     archive.Record
+	ArchiveURL string
+}
+
+// Render the archive, listing the most recent public games matching the search query.
+func (master *GameMaster) renderArchive(w http.ResponseWriter, r *http.Request) {
+	query := r.FormValue("q")
+	err := archiveTemplate.ExecuteTemplate(w, "archive.html", archiveTemplateData{
+		Query:     query,
+		Summaries: master.archive.Search(query, archiveSearchLimit),
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to write archive template")
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
+
+// Render a single archived game -- the permanent, shareable page of a completed game.
+func (master *GameMaster) renderArchivedGame(w http.ResponseWriter, r *http.Request) {
+	record, ok := master.archive.Get(chi.URLParam(r, "gameID"))
+	if !ok {
+		http.Error(w, "game is not in the archive", http.StatusNotFound)
+		return
+	}
+
+	err := archiveTemplate.ExecuteTemplate(w, "archiveGame.html", archiveGameTemplateData{
+		Record:     record,
+		ArchiveURL: archiveURL(record.Transcript.GameID),
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to write archived game template")
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
+
+// Export an archived game in the requested format (json, csv or md).
+func (master *GameMaster) exportArchivedGame(w http.ResponseWriter, r *http.Request) {
+	record, ok := master.archive.Get(chi.URLParam(r, "gameID"))
+	if !ok {
+		http.Error(w, "game is not in the archive", http.StatusNotFound)
+		return
+	}
+	format, err := transcript.ParseFormat(r.FormValue("format"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	archivedTranscript := record.Transcript
+	archivedTranscript.ExportedAt = time.Now()
+	w.Header().Set("Content-Type", format.ContentType())
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"twentyquestions-%s.%s\"", archivedTranscript.GameID, format.FileExtension()))
+	err = transcript.Write(w, archivedTranscript, format)
+	if err != nil {
+		log.Error().Str("GameID", archivedTranscript.GameID).Err(err).Msg("Failed to write archived transcript")
+	}
+}
+
+// JSON endpoint to browse and search the archive.
+func (master *GameMaster) apiArchive(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, master.archive.Search(r.FormValue("q"), archiveSearchLimit))
+}
+
+// JSON endpoint for a single archived game.
+func (master *GameMaster) apiArchivedGame(w http.ResponseWriter, r *http.Request) {
+	record, ok := master.archive.Get(chi.URLParam(r, "gameID"))
+	if !ok {
+		writeJSONError(w, http.StatusNotFound, "game is not in the archive")
+		return
+	}
+	writeJSON(w, http.StatusOK, record)
+}
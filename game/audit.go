@@ -0,0 +1,87 @@
+package game
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog/log"
+
+	"github.com/hmcalister/twentyquestions/audit"
+)
+
+// Actions recorded in the audit log that are not round events -- round events are recorded under their kind.
+const (
+	auditAction_SecretSet        = "secretSet"
+	auditAction_QuestionProposed = "questionProposed"
+	auditAction_ProposalUpvoted  = "proposalUpvoted"
+
+	// Changes to the session, recorded against the round they were made in.
+	auditAction_ChatToggled          = "chatToggled"
+	auditAction_RoundStarted         = "roundStarted"
+	auditAction_NameChanged          = "nameChanged"
+	auditAction_WebhookAdded         = "webhookAdded"
+	auditAction_WebhookRemoved       = "webhookRemoved"
+	auditAction_ComputerGuesserAdded = "computerGuesserAdded"
+)
+
+// Get the identity of the oracle of this round.
+func (data *GameData) oracleIdentity() playerIdentity {
+	return playerIdentity{
+		ID:   data.oracleID,
+		Name: data.oracleName,
+	}
+}
+
+// Record an action by a player, or an admin, in the audit log, along with the role they have in this round.
+// Only a hash of the payload is recorded, so the log does not hold questions, answers or secrets.
+//
+// The caller must not hold the gameStateMutex -- use queueAudit instead.
+func (data *GameData) audit(action string, actor playerIdentity, payload string) {
+	data.session.master.auditLog.Record(data.auditEntry(action, actor, payload))
+}
+
+// Record an action in the audit log as in audit, once the gameStateMutex is released. The entry is timestamped now.
+//
+// The caller must hold the gameStateMutex, and release it with unlockGameState.
+func (data *GameData) queueAudit(action string, actor playerIdentity, payload string) {
+	entry := data.auditEntry(action, actor, payload)
+	entry.Time = time.Now()
+	data.queueNotification(func() { data.session.master.auditLog.Record(entry) })
+}
+
+// Create the audit log entry for an action by a player, or an admin, in this round.
+func (data *GameData) auditEntry(action string, actor playerIdentity, payload string) audit.Entry {
+	role := audit.Role_Guesser
+	switch actor.ID {
+	case data.oracleID:
+		role = audit.Role_Oracle
+	case adminIdentity.ID:
+		role = audit.Role_Admin
+	}
+
+	return audit.Entry{
+		GameID:      data.gameID,
+		Round:       data.roundNumber,
+		Action:      action,
+		ActorID:     actor.ID,
+		ActorName:   actor.Name,
+		Role:        role,
+		PayloadHash: data.session.master.auditLog.HashPayload(payload),
+	}
+}
+
+// --------------------------------------------------------------------------------
+// Routing Functions
+// --------------------------------------------------------------------------------
+
+// List every action recorded in the audit log for a game, oldest first. Games that have been deleted are included.
+func (master *GameMaster) adminAuditLog(w http.ResponseWriter, r *http.Request) {
+	entries, err := master.auditLog.Query(chi.URLParam(r, "gameID"))
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to read audit log")
+		writeJSONError(w, http.StatusInternalServerError, "failed to read audit log")
+		return
+	}
+	writeJSON(w, http.StatusOK, entries)
+}
@@ -0,0 +1,131 @@
+package game
+
+import (
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/hmcalister/twentyquestions/webhooks"
+)
+
+func TestSessionChangesAreAudited(t *testing.T) {
+	server := newTestServer(t)
+	server.master.auditLog = openTestAuditLog(t, filepath.Join(t.TempDir(), "audit.log"))
+	oracle := server.newPlayer(t)
+	guesser := server.newPlayer(t)
+
+	gameID := oracle.createGame(url.Values{})
+	guesser.join(gameID)
+	oracle.mustPost(gameID, "toggleChat", nil)
+	oracle.mustPost(gameID, "addComputerGuesser", nil)
+	guesser.mustPost(gameID, "setName", url.Values{"name": {"Alice"}})
+	playRound(oracle, guesser, gameID, true)
+	guesser.mustPost(gameID, "nextRound", nil)
+
+	entries, err := server.master.auditLog.Query(gameID)
+	if err != nil {
+		t.Fatalf("failed to query audit log: %v", err)
+	}
+	recorded := make(map[string]string)
+	for _, entry := range entries {
+		recorded[entry.Action] = entry.ActorID
+	}
+
+	testCases := []struct {
+		action    string
+		wantActor *testPlayer
+	}{
+		{auditAction_ChatToggled, oracle},
+		{auditAction_ComputerGuesserAdded, oracle},
+		{auditAction_NameChanged, guesser},
+		{auditAction_RoundStarted, guesser},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.action, func(t *testing.T) {
+			actorID, ok := recorded[testCase.action]
+			if !ok {
+				t.Fatalf("no %s entry in the audit log", testCase.action)
+			}
+			if actorID != testCase.wantActor.id() {
+				t.Errorf("%s recorded by %s, want %s", testCase.action, actorID, testCase.wantActor.id())
+			}
+		})
+	}
+}
+
+func TestWebhookChangesAreAudited(t *testing.T) {
+	server := newTestServer(t)
+	server.master.auditLog = openTestAuditLog(t, filepath.Join(t.TempDir(), "audit.log"))
+	server.master.webhooks = webhooks.NewDispatcher(true)
+	oracle := server.newPlayer(t)
+	gameID := oracle.createGame(url.Values{})
+
+	status, body := oracle.post("/game/"+gameID+"/webhooks", url.Values{"url": {"http://example.com/hook"}})
+	if status != http.StatusCreated {
+		t.Fatalf("adding webhook returned %d: %s", status, body)
+	}
+	subscriptions := server.master.webhooks.GameSubscriptions(gameID)
+	if len(subscriptions) != 1 {
+		t.Fatalf("game has %d subscriptions, want 1", len(subscriptions))
+	}
+	status, body = oracle.delete("/game/" + gameID + "/webhooks/" + subscriptions[0].ID)
+	if status != http.StatusNoContent {
+		t.Fatalf("removing webhook returned %d: %s", status, body)
+	}
+
+	entries, err := server.master.auditLog.Query(gameID)
+	if err != nil {
+		t.Fatalf("failed to query audit log: %v", err)
+	}
+	var actions []string
+	for _, entry := range entries {
+		actions = append(actions, entry.Action)
+	}
+	for _, want := range []string{auditAction_WebhookAdded, auditAction_WebhookRemoved} {
+		found := false
+		for _, action := range actions {
+			found = found || action == want
+		}
+		if !found {
+			t.Errorf("no %s entry in the audit log, got %v", want, actions)
+		}
+	}
+}
+
+func TestRoundEventsAreAuditedInOrder(t *testing.T) {
+	server := newTestServer(t)
+	server.master.auditLog = openTestAuditLog(t, filepath.Join(t.TempDir(), "audit.log"))
+	oracle := server.newPlayer(t)
+	guesser := server.newPlayer(t)
+	gameID := oracle.createGame(url.Values{})
+	guesser.join(gameID)
+
+	// Entries are written once the lock is released, but keep the order of the actions they record.
+	oracle.mustPost(gameID, "setSecret", url.Values{"secret": {"Whale"}})
+	playRound(oracle, guesser, gameID, true)
+
+	entries, err := server.master.auditLog.Query(gameID)
+	if err != nil {
+		t.Fatalf("failed to query audit log: %v", err)
+	}
+	var actions []string
+	for i, entry := range entries {
+		actions = append(actions, entry.Action)
+		if i > 0 && entry.Time.Before(entries[i-1].Time) {
+			t.Errorf("%s recorded before the %s entry it follows", entry.Action, entries[i-1].Action)
+		}
+	}
+	wantActions := []string{
+		string(roundEventKind_RoundStarted),
+		auditAction_SecretSet,
+		string(roundEventKind_QuestionAsked),
+		string(roundEventKind_QuestionAnswered),
+		string(roundEventKind_VerdictCorrect),
+	}
+	if !slices.Equal(actions[:min(len(actions), len(wantActions))], wantActions) {
+		t.Errorf("audit log actions = %v, want %v first", actions, wantActions)
+	}
+}
@@ -0,0 +1,104 @@
+package game
+
+import (
+	"expvar"
+	"html/template"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/hmcalister/twentyquestions/templates"
+)
+
+var (
+	// Template for the page shown when a request is turned away due to capacity limits.
+	capacityTemplate = template.Must(template.ParseFS(templates.Files, "capacity.html"))
+
+	// Number of requests turned away due to capacity limits, keyed by the limit that was hit.
+	capacityRejections = expvar.NewMap("capacityRejections")
+)
+
+// The reasons a request may be turned away due to capacity limits.
+type capacityRejection struct {
+	// Machine readable code, used in JSON errors and metrics.
+	Code string `json:"code"`
+
+	// Human readable title and message, used in JSON errors and the rejection page.
+	Title   string `json:"title"`
+	Message string `json:"error"`
+
+	status int
+}
+
+var (
+	rejection_ServerFull = capacityRejection{
+		Code:    "serverFull",
+		Title:   "Server Full",
+		Message: "There are too many games being played right now. Please try again later.",
+		status:  http.StatusServiceUnavailable,
+	}
+
+	rejection_TooManyGamesFromIP = capacityRejection{
+		Code:    "tooManyGames",
+		Title:   "Too Many Games",
+		Message: "You have created too many games recently. Please finish or wait for one of your games to expire.",
+		status:  http.StatusTooManyRequests,
+	}
+
+	rejection_GameFull = capacityRejection{
+		Code:    "gameFull",
+		Title:   "Game Full",
+		Message: "This game has too many players connected. Please try again later, or start your own game.",
+		status:  http.StatusServiceUnavailable,
+	}
+)
+
+// Maximums to protect the server from memory exhaustion. A maximum of zero means no limit.
+type CapacityLimits struct {
+	// Maximum number of games alive at once.
+	MaxGames int
+
+	// Maximum number of games alive at once created from a single IP address.
+	MaxGamesPerIP int
+
+	// Maximum number of SSE connections to a single game.
+	MaxConnectionsPerGame int
+}
+
+// Check if a limit has been reached. A limit of zero means no limit.
+func limitReached(limit int, count int) bool {
+	return limit > 0 && count >= limit
+}
+
+// Get the IP address of the client making a request, without the port.
+func requestIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// Check if a request would prefer a JSON response over an HTML page.
+func wantsJSON(r *http.Request) bool {
+	return strings.Contains(r.Header.Get("Accept"), "application/json")
+}
+
+// Turn away a request due to a capacity limit, with a JSON error or a friendly page depending on the request.
+func writeCapacityRejection(w http.ResponseWriter, r *http.Request, rejection capacityRejection) {
+	capacityRejections.Add(rejection.Code, 1)
+	log.Info().Str("Rejection", rejection.Code).Str("RemoteIP", r.RemoteAddr).Str("URL", r.URL.Path).Msg("Request Rejected Due To Capacity")
+
+	if wantsJSON(r) {
+		writeJSON(w, rejection.status, rejection)
+		return
+	}
+
+	w.WriteHeader(rejection.status)
+	err := capacityTemplate.Execute(w, rejection)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to write capacity template")
+	}
+}
@@ -0,0 +1,116 @@
+package game
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestLimitReached(t *testing.T) {
+	testCases := []struct {
+		name  string
+		limit int
+		count int
+		want  bool
+	}{
+		{"no limit", 0, 1000, false},
+		{"below limit", 3, 2, false},
+		{"at limit", 3, 3, true},
+		{"above limit", 3, 4, true},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			if got := limitReached(testCase.limit, testCase.count); got != testCase.want {
+				t.Errorf("got %v, want %v", got, testCase.want)
+			}
+		})
+	}
+}
+
+// Check a response is the rejection, as JSON or as the rejection page.
+func checkCapacityRejection(t *testing.T, status int, body string, asJSON bool, want capacityRejection) {
+	t.Helper()
+	if status != want.status {
+		t.Errorf("responded %d, want %d", status, want.status)
+	}
+	if !asJSON {
+		if !strings.Contains(body, "<h1>"+want.Title+"</h1>") || !strings.Contains(body, want.Message) {
+			t.Errorf("rejection page does not explain %s: %s", want.Code, body)
+		}
+		return
+	}
+
+	var rejection capacityRejection
+	if err := json.Unmarshal([]byte(body), &rejection); err != nil {
+		t.Fatalf("failed to decode rejection %q: %v", body, err)
+	}
+	if rejection.Code != want.Code || rejection.Title != want.Title || rejection.Message != want.Message {
+		t.Errorf("got rejection %+v, want %+v", rejection, want)
+	}
+}
+
+func TestGameCapacity(t *testing.T) {
+	server := newTestServerWithLimits(t, CapacityLimits{MaxGames: 3, MaxGamesPerIP: 2})
+	player := server.newPlayer(t)
+	config, err := parseGameSessionConfig(nil, LobbyCategories)
+	if err != nil {
+		t.Fatalf("failed to parse config: %v", err)
+	}
+
+	// Every request from the test client comes from the same IP, so other IPs create their games directly.
+	createGame := func(ip string) *capacityRejection {
+		_, rejection := server.master.createSession(playerIdentity{ID: ip, Name: ip}, ip, config)
+		return rejection
+	}
+
+	firstGameID := player.createGame(nil)
+	player.createGame(nil)
+
+	status, body := player.getJSON("/game/new")
+	checkCapacityRejection(t, status, body, true, rejection_TooManyGamesFromIP)
+	status, body = player.get("/game/new")
+	checkCapacityRejection(t, status, body, false, rejection_TooManyGamesFromIP)
+
+	if rejection := createGame("10.0.0.1"); rejection != nil {
+		t.Fatalf("game from another IP rejected: %+v", rejection)
+	}
+	if rejection := createGame("10.0.0.2"); rejection == nil || rejection.Code != rejection_ServerFull.Code {
+		t.Errorf("game created on a full server, rejection = %+v", rejection)
+	}
+	status, body = player.getJSON("/game/new")
+	checkCapacityRejection(t, status, body, true, rejection_ServerFull)
+	status, body = player.get("/game/new")
+	checkCapacityRejection(t, status, body, false, rejection_ServerFull)
+
+	// Deleting a game makes room for another from the same IP.
+	firstSession, _ := server.master.sessionByID(firstGameID)
+	server.master.deleteSession(firstSession)
+	player.createGame(nil)
+}
+
+func TestConnectionCapacity(t *testing.T) {
+	server := newTestServerWithLimits(t, CapacityLimits{MaxConnectionsPerGame: 1})
+	oracle := server.newPlayer(t)
+	guesser := server.newPlayer(t)
+	gameID := oracle.createGame(nil)
+	session, _ := server.master.sessionByID(gameID)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	if _, err := session.broadcaster.subscribe(ctx); err != nil {
+		t.Fatalf("first client could not subscribe: %v", err)
+	}
+
+	status, body := guesser.get("/game/" + gameID + "/")
+	checkCapacityRejection(t, status, body, false, rejection_GameFull)
+	status, body = guesser.getJSON("/game/" + gameID + "/responsesSourceSSE")
+	checkCapacityRejection(t, status, body, true, rejection_GameFull)
+
+	// Once the first client leaves, there is room for another.
+	cancel()
+	if status, _ := guesser.get("/game/" + gameID + "/"); status != http.StatusOK {
+		t.Errorf("joining after a client left responded %d, want %d", status, http.StatusOK)
+	}
+}
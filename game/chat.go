@@ -0,0 +1,125 @@
+package game
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// Maximum number of chat messages kept in a session -- older messages are dropped.
+	chatHistoryLimit int = 100
+
+	// Maximum length of a chat message in runes.
+	chatMessageMaxLength int = 280
+)
+
+// A single message in the side chat of a session.
+type chatMessage struct {
+	PlayerName string
+	Message    string
+	SentAt     time.Time
+}
+
+// Add a chat message to the session, dropping the oldest message if the history is full.
+// Returns an error if chat has been disabled by the oracle.
+func (session *GameSession) addChatMessage(player playerIdentity, message string) error {
+	session.sessionMutex.Lock()
+	defer session.sessionMutex.Unlock()
+
+	if !session.chatEnabled {
+		return errors.New("chat is disabled")
+	}
+
+	session.chatHistory = append(session.chatHistory, chatMessage{
+		PlayerName: player.Name,
+		Message:    message,
+		SentAt:     time.Now(),
+	})
+	if len(session.chatHistory) > chatHistoryLimit {
+		session.chatHistory = session.chatHistory[len(session.chatHistory)-chatHistoryLimit:]
+	}
+	return nil
+}
+
+// Check if chat is currently enabled in the session.
+func (session *GameSession) isChatEnabled() bool {
+	session.sessionMutex.Lock()
+	defer session.sessionMutex.Unlock()
+	return session.chatEnabled
+}
+
+// Data to be passed to chat.html template
+type chatTemplateData struct {
+	ChatEnabled bool
+	Messages    []chatMessage
+}
+
+// Render the chat history as an SSE event. The whole (bounded) history is sent so reconnecting clients never see duplicates.
+func (session *GameSession) chatEvent() sseEvent {
+	session.sessionMutex.Lock()
+	templateData := chatTemplateData{
+		ChatEnabled: session.chatEnabled,
+		Messages:    append([]chatMessage(nil), session.chatHistory...),
+	}
+	session.sessionMutex.Unlock()
+
+	var chatBytes bytes.Buffer
+	err := gameTemplate.ExecuteTemplate(&chatBytes, "chat.html", templateData)
+	if err != nil {
+		log.Error().Str("GameID", session.gameID).Err(err).Msg("Failed to write chat template")
+	}
+	return sseEvent{name: "chat", data: chatBytes.String()}
+}
+
+// --------------------------------------------------------------------------------
+// Routing Functions
+// --------------------------------------------------------------------------------
+
+// Post a message to the side chat -- separate from the questions and answers of the round.
+func (session *GameSession) handleChatMessage(w http.ResponseWriter, r *http.Request) {
+	message := truncateRunes(strings.TrimSpace(r.FormValue("message")), chatMessageMaxLength)
+	if len(message) == 0 {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	err := session.addChatMessage(playerFromRequest(r), message)
+	if err != nil {
+		log.Debug().Str("GameID", session.gameID).Msg("Chat Disabled!")
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	session.broadcaster.broadcast(session.chatEvent())
+}
+
+// Enable or disable the side chat -- only the oracle of the current round may do this.
+func (session *GameSession) handleToggleChat(w http.ResponseWriter, r *http.Request) {
+	isOracle := r.Context().Value("IsOracle").(bool)
+	if !isOracle {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	session.sessionMutex.Lock()
+	session.chatEnabled = !session.chatEnabled
+	chatEnabled := session.chatEnabled
+	log.Debug().Str("GameID", session.gameID).Bool("ChatEnabled", chatEnabled).Msg("Chat Toggled")
+	session.sessionMutex.Unlock()
+
+	auditPayload := "disabled"
+	if chatEnabled {
+		auditPayload = "enabled"
+	}
+	session.currentRound().audit(auditAction_ChatToggled, playerFromRequest(r), auditPayload)
+
+	w.WriteHeader(http.StatusOK)
+	session.broadcaster.broadcast(session.chatEvent())
+	session.broadcaster.broadcast(controlsEvent())
+}
@@ -0,0 +1,150 @@
+package game
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTruncateRunes(t *testing.T) {
+	testCases := []struct {
+		name   string
+		value  string
+		length int
+		want   string
+	}{
+		{"short", "Hello", 10, "Hello"},
+		{"exact", "Hello", 5, "Hello"},
+		{"long", "Hello there", 5, "Hello"},
+		{"multibyte", "héllo wörld", 7, "héllo w"},
+		{"empty", "", 5, ""},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			if got := truncateRunes(testCase.value, testCase.length); got != testCase.want {
+				t.Errorf("got %q, want %q", got, testCase.want)
+			}
+		})
+	}
+}
+
+func TestChat(t *testing.T) {
+	server := newTestServer(t)
+	oracle := server.newPlayer(t)
+	guesser := server.newPlayer(t)
+	gameID := oracle.createGame(url.Values{})
+	guesser.mustPost(gameID, "setName", url.Values{"name": {"Alice"}})
+
+	chat := func(message string) url.Values { return url.Values{"message": {message}} }
+	steps := []struct {
+		name       string
+		player     *testPlayer
+		route      string
+		form       url.Values
+		wantStatus int
+	}{
+		{"message", guesser, "chat", chat("Hello"), http.StatusOK},
+		{"blank message", guesser, "chat", chat("   "), http.StatusBadRequest},
+		{"long message", guesser, "chat", chat(strings.Repeat("é", chatMessageMaxLength+20)), http.StatusOK},
+		{"guesser disables chat", guesser, "toggleChat", nil, http.StatusUnauthorized},
+		{"oracle disables chat", oracle, "toggleChat", nil, http.StatusOK},
+		{"message while disabled", guesser, "chat", chat("Hello?"), http.StatusForbidden},
+		{"oracle enables chat", oracle, "toggleChat", nil, http.StatusOK},
+		{"message after enabled", guesser, "chat", chat("  Hello again  "), http.StatusOK},
+	}
+
+	for _, step := range steps {
+		status, body := step.player.post("/game/"+gameID+"/"+step.route, step.form)
+		if status != step.wantStatus {
+			t.Fatalf("%s: responded %d, want %d: %s", step.name, status, step.wantStatus, body)
+		}
+	}
+
+	state := oracle.state(gameID)
+	wantMessages := []string{"Hello", strings.Repeat("é", chatMessageMaxLength), "Hello again"}
+	if !state.ChatEnabled || len(state.Chat) != len(wantMessages) {
+		t.Fatalf("chat enabled %v with messages %+v, want %d messages", state.ChatEnabled, state.Chat, len(wantMessages))
+	}
+	for i, message := range state.Chat {
+		if message.PlayerName != "Alice" || message.Message != wantMessages[i] || message.SentAt.IsZero() {
+			t.Errorf("message %d = %+v, want %q from Alice", i, message, wantMessages[i])
+		}
+	}
+}
+
+func TestChatHistoryLimit(t *testing.T) {
+	server := newTestServer(t)
+	oracle := server.newPlayer(t)
+	gameID := oracle.createGame(url.Values{})
+	session, _ := server.master.sessionByID(gameID)
+	player := playerIdentity{ID: "player", Name: "Player"}
+
+	for i := range chatHistoryLimit + 10 {
+		if err := session.addChatMessage(player, strconv.Itoa(i)); err != nil {
+			t.Fatalf("failed to add message %d: %v", i, err)
+		}
+	}
+
+	// The oldest messages are dropped, keeping the most recent in order.
+	chat := oracle.state(gameID).Chat
+	if len(chat) != chatHistoryLimit {
+		t.Fatalf("%d messages kept, want %d", len(chat), chatHistoryLimit)
+	}
+	if chat[0].Message != "10" || chat[len(chat)-1].Message != strconv.Itoa(chatHistoryLimit+9) {
+		t.Errorf("kept messages %s to %s, want the most recent", chat[0].Message, chat[len(chat)-1].Message)
+	}
+}
+
+func TestChatIsBroadcast(t *testing.T) {
+	server := newTestServer(t)
+	oracle := server.newPlayer(t)
+	guesser := server.newPlayer(t)
+	gameID := oracle.createGame(url.Values{})
+	guesser.mustPost(gameID, "setName", url.Values{"name": {"Alice"}})
+	session, _ := server.master.sessionByID(gameID)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	client, err := session.broadcaster.subscribe(ctx)
+	if err != nil {
+		t.Fatalf("failed to subscribe: %v", err)
+	}
+	defer client.cancelFunc()
+
+	// Skip any other events, e.g. the scoreboard after the name change.
+	receiveChat := func() string {
+		t.Helper()
+		for {
+			select {
+			case event := <-client.eventsChannel:
+				if event.name == "chat" {
+					return event.data
+				}
+			case <-ctx.Done():
+				t.Fatal("no chat event sent")
+			}
+		}
+	}
+
+	guesser.mustPost(gameID, "chat", url.Values{"message": {"Is anyone there?"}})
+	if data := receiveChat(); !strings.Contains(data, "Alice") || !strings.Contains(data, "Is anyone there?") {
+		t.Errorf("chat event %q does not have the message", data)
+	}
+
+	// The history is sent whole, so the first message is sent again with the second.
+	oracle.mustPost(gameID, "chat", url.Values{"message": {"Yes, ask away"}})
+	if data := receiveChat(); !strings.Contains(data, "Is anyone there?") || !strings.Contains(data, "Yes, ask away") {
+		t.Errorf("chat event %q does not have the whole history", data)
+	}
+
+	oracle.mustPost(gameID, "toggleChat", nil)
+	receiveChat()
+	if session.isChatEnabled() {
+		t.Error("chat still enabled after the oracle disabled it")
+	}
+}
@@ -0,0 +1,136 @@
+package game
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/hmcalister/twentyquestions/knowledge"
+	"github.com/hmcalister/twentyquestions/wordpacks"
+)
+
+const (
+	// The player ID and name of the computer guesser. The ID cannot collide with a real player, whose IDs are random letters only.
+	computerGuesserPlayerID   string = "computer-guesser"
+	computerGuesserPlayerName string = "Computer Guesser"
+
+	// How long the computer guesser waits before asking, giving human guessers a chance to ask first.
+	computerGuesserQuestionDelay time.Duration = 2 * time.Second
+)
+
+// Check if the player may be the oracle of a round -- the computer guesser only ever guesses.
+func canBeOracle(player *sessionPlayer) bool {
+	return player.ID != computerGuesserPlayerID
+}
+
+// Add the computer guesser to the session, where it guesses in this and every following round.
+// Returns false if the computer guesser had already joined.
+func (session *GameSession) addComputerGuesser() bool {
+	session.sessionMutex.Lock()
+	if session.computerGuesser {
+		session.sessionMutex.Unlock()
+		return false
+	}
+	session.computerGuesser = true
+	session.players = append(session.players, &sessionPlayer{
+		ID:   computerGuesserPlayerID,
+		Name: computerGuesserPlayerName,
+	})
+	round := session.rounds[len(session.rounds)-1]
+	session.sessionMutex.Unlock()
+
+	round.startComputerGuesser()
+	return true
+}
+
+// Check if the computer guesser has joined the session.
+func (session *GameSession) hasComputerGuesser() bool {
+	session.sessionMutex.Lock()
+	defer session.sessionMutex.Unlock()
+	return session.computerGuesser
+}
+
+// Let the computer guesser play in this round, asking its first question after a short delay.
+func (data *GameData) startComputerGuesser() {
+	data.gameStateMutex.Lock()
+	defer data.gameStateMutex.Unlock()
+
+	data.computerGuesser = true
+	time.AfterFunc(computerGuesserQuestionDelay, data.askAsComputerGuesser)
+}
+
+// Get every answered question in the round, for the computer guesser to reason about.
+//
+// The caller must hold the gameStateMutex.
+func (data *GameData) observations() []knowledge.Observation {
+	observations := make([]knowledge.Observation, 0, len(data.questionAnswerPairs))
+	for _, pair := range data.questionAnswerPairs {
+		if pair.Kind == entryKind_Question && !pair.Retracted && !pair.AnsweredAt.IsZero() {
+			observations = append(observations, knowledge.Observation{
+				Question: pair.Question,
+				Answer:   pair.Answer,
+			})
+		}
+	}
+	return observations
+}
+
+// Ask the next question as the computer guesser, through the same path as a human guesser.
+// Every answered question in the round is used to choose the question, including those asked by other guessers.
+//
+// Does nothing if another guesser asked first, or if the computer guesser has run out of ideas.
+func (data *GameData) askAsComputerGuesser() {
+	data.gameStateMutex.Lock()
+	if data.gameState != gameState_AwaitingQuestion {
+		data.gameStateMutex.Unlock()
+		return
+	}
+	observations := data.observations()
+	questionsRemaining := data.session.config.questionBudget - data.budgetUsed()
+	data.gameStateMutex.Unlock()
+
+	// The catch all category, and categories the knowledge base does not know, consider every entity.
+	category := data.session.config.category
+	if category == wordpacks.Category_Anything || len(data.session.master.knowledgeBase.Entities(category)) == 0 {
+		category = ""
+	}
+
+	question, ok := data.session.master.knowledgeBase.NextQuestion(category, observations, questionsRemaining)
+	if !ok {
+		log.Debug().Str("GameID", data.gameID).Msg("Computer Guesser Has No Questions Left")
+		return
+	}
+
+	err := data.addNextQuestion(playerIdentity{ID: computerGuesserPlayerID, Name: computerGuesserPlayerName}, question)
+	if err != nil {
+		log.Debug().Str("GameID", data.gameID).Err(err).Msg("Computer Guesser Could Not Ask")
+		return
+	}
+	log.Debug().Str("GameID", data.gameID).Str("Question", question).Msg("Computer Guesser Asked")
+	data.session.broadcastResponses(data)
+}
+
+// --------------------------------------------------------------------------------
+// Routing Functions
+// --------------------------------------------------------------------------------
+
+// Add the computer guesser to the session -- only the oracle may do this, and not in voting mode, as the computer cannot vote.
+func (session *GameSession) handleAddComputerGuesser(w http.ResponseWriter, r *http.Request) {
+	isOracle := r.Context().Value("IsOracle").(bool)
+	if !isOracle {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	if session.config.votingMode || !session.addComputerGuesser() {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	log.Info().Str("GameID", session.gameID).Msg("Computer Guesser Joined")
+	session.currentRound().audit(auditAction_ComputerGuesserAdded, playerFromRequest(r), "")
+	session.broadcaster.broadcast(session.scoreboardEvent())
+	session.broadcaster.broadcast(controlsEvent())
+	session.notifyListingChange()
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,86 @@
+package game
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/hmcalister/twentyquestions/wordpacks"
+)
+
+const (
+	// The player ID and name of the computer oracle. The ID cannot collide with a real player, whose IDs are random letters only.
+	computerOraclePlayerID   string = "computer-oracle"
+	computerOraclePlayerName string = "Computer Oracle"
+
+	// How long the computer oracle waits before answering, so the question is seen before the answer arrives.
+	computerOracleAnswerDelay time.Duration = 1 * time.Second
+)
+
+// Check if the computer oracle is the oracle of this round.
+func (data *GameData) hasComputerOracle() bool {
+	return data.oracleID == computerOraclePlayerID
+}
+
+// Pick a random secret for the computer oracle from the knowledge base. Returns false if the knowledge base is empty.
+// Categories without entities, including the catch all category, pick from every entity.
+func (master *GameMaster) pickComputerOracleSecret(category string) (string, bool) {
+	candidates := master.knowledgeBase.Entities(category)
+	if category == wordpacks.Category_Anything || len(candidates) == 0 {
+		candidates = master.knowledgeBase.Entities("")
+	}
+	if len(candidates) == 0 {
+		return "", false
+	}
+
+	master.rngMutex.Lock()
+	defer master.rngMutex.Unlock()
+	return candidates[master.rng.Intn(len(candidates))], true
+}
+
+// Answer the question at the given position as the computer oracle, through the same path as a human oracle.
+// The round is ended once the secret is guessed, or once the guessers have no questions remaining.
+//
+// Does nothing if the question was retracted in the meantime, even if another question is now awaiting an answer.
+func (data *GameData) answerAsComputerOracle(position int) {
+	data.gameStateMutex.Lock()
+	if data.gameState != gameState_AwaitingAnswer || position != len(data.questionAnswerPairs)-1 {
+		data.gameStateMutex.Unlock()
+		return
+	}
+	question := data.questionAnswerPairs[position].Question
+
+	secret := data.secret
+	answer, guessed := data.session.master.knowledgeBase.Answer(secret, question)
+	answerText := answer.String()
+	if guessed {
+		answerText = fmt.Sprintf("Yes! The secret is %s.", secret)
+	}
+
+	err := data.answerQuestion(answerText)
+	data.unlockGameState()
+	if err != nil {
+		log.Debug().Str("GameID", data.gameID).Err(err).Msg("Computer Oracle Could Not Answer")
+		return
+	}
+	log.Debug().Str("GameID", data.gameID).Str("Question", question).Str("Answer", answerText).Msg("Computer Oracle Answered")
+	data.session.broadcastResponses(data)
+
+	if guessed {
+		data.endComputerOracleRound(true)
+	} else if data.questionsUsed() >= data.session.config.questionBudget {
+		data.endComputerOracleRound(false)
+	}
+}
+
+// End the round with the verdict of the computer oracle, as a human oracle does from the controls.
+func (data *GameData) endComputerOracleRound(correct bool) {
+	err := data.setVerdict(correct)
+	if err != nil {
+		return
+	}
+
+	data.session.broadcaster.broadcast(data.proposalsEvent())
+	data.session.finishRound(data)
+}
@@ -0,0 +1,163 @@
+package game
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog/log"
+
+	"github.com/hmcalister/twentyquestions/transcript"
+)
+
+// Snapshot the round for a transcript. The secret is only included once the round is over, unless includeSecret is set.
+func (data *GameData) transcriptRound(includeSecret bool) transcript.Round {
+	data.gameStateMutex.Lock()
+	defer data.gameStateMutex.Unlock()
+
+	round := transcript.Round{
+		Number:     data.roundNumber,
+		OracleName: data.oracleName,
+		StartedAt:  data.startedAt,
+		Outcome:    transcript.Outcome_InProgress,
+		Entries:    make([]transcript.Entry, 0, len(data.questionAnswerPairs)),
+	}
+
+	if data.gameState == gameState_GameOver {
+		switch {
+		case data.forceEnded:
+			round.Outcome = transcript.Outcome_Ended
+		case data.verdictCorrect:
+			round.Outcome = transcript.Outcome_Correct
+		default:
+			round.Outcome = transcript.Outcome_Incorrect
+		}
+	}
+	if !data.endedAt.IsZero() {
+		endedAt := data.endedAt
+		round.EndedAt = &endedAt
+	}
+	if data.gameState == gameState_GameOver || includeSecret {
+		round.Secret = data.secret
+	}
+
+	for _, pair := range data.questionAnswerPairs {
+		entry := transcript.Entry{
+			Kind:      transcript.EntryKind_Question,
+			Index:     pair.Index,
+			Cost:      pair.Cost,
+			AskerName: pair.AskerName,
+			Question:  pair.Question,
+			Answer:    pair.Answer,
+			Retracted: pair.Retracted,
+			AskedAt:   pair.AskedAt,
+		}
+		if pair.Kind == entryKind_Hint {
+			entry = transcript.Entry{
+				Kind:    transcript.EntryKind_Hint,
+				Cost:    pair.Cost,
+				Hint:    pair.Hint,
+				AskedAt: pair.AskedAt,
+			}
+		}
+		if !pair.AnsweredAt.IsZero() {
+			answeredAt := pair.AnsweredAt
+			entry.AnsweredAt = &answeredAt
+		}
+		if pair.Retracted {
+			entry.Index = 0
+		}
+
+		for _, revision := range pair.Revisions {
+			entry.Revisions = append(entry.Revisions, transcript.Revision{
+				Time:          revision.Time,
+				EditorName:    revision.EditorName,
+				Description:   revision.Description,
+				PreviousValue: revision.PreviousValue,
+			})
+		}
+		round.Entries = append(round.Entries, entry)
+	}
+	return round
+}
+
+// Create a transcript of the session as the given player may see it.
+//
+// Finished rounds are always included. The round in progress is only included for its oracle, who may export at any time.
+func (session *GameSession) transcriptFor(player playerIdentity) transcript.Transcript {
+	session.sessionMutex.Lock()
+	rounds := append([]*GameData(nil), session.rounds...)
+	playerNames := make(map[string]string, len(session.players))
+	sessionTranscript := transcript.Transcript{
+		GameID:     session.gameID,
+		Title:      session.config.title,
+		CreatedAt:  session.createdAt,
+		ExportedAt: time.Now(),
+		Players:    make([]transcript.Player, 0, len(session.players)),
+		Rounds:     make([]transcript.Round, 0, len(rounds)),
+	}
+	for _, sessionPlayer := range session.players {
+		playerNames[sessionPlayer.ID] = sessionPlayer.Name
+		sessionTranscript.Players = append(sessionTranscript.Players, transcript.Player{
+			Name:  sessionPlayer.Name,
+			Score: sessionPlayer.Score,
+		})
+	}
+	session.sessionMutex.Unlock()
+
+	for _, round := range rounds {
+		isOracle := round.oracleID == player.ID
+		if !round.isGameOver() && !isOracle {
+			continue
+		}
+
+		transcriptRound := round.transcriptRound(isOracle)
+		transcriptRound.WinnerName = playerNames[round.roundWinnerID()]
+		sessionTranscript.Rounds = append(sessionTranscript.Rounds, transcriptRound)
+	}
+	return sessionTranscript
+}
+
+// --------------------------------------------------------------------------------
+// Routing Functions
+// --------------------------------------------------------------------------------
+
+// Export the transcript of the session in the requested format (json, csv or md).
+//
+// Players may export once a round is over, and the oracle may export their round at any time.
+func (session *GameSession) handleExport(w http.ResponseWriter, r *http.Request) {
+	format, err := transcript.ParseFormat(r.FormValue("format"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	sessionTranscript := session.transcriptFor(playerFromRequest(r))
+	if len(sessionTranscript.Rounds) == 0 {
+		http.Error(w, "no rounds are available to export until the round is over", http.StatusForbidden)
+		return
+	}
+
+	w.Header().Set("Content-Type", format.ContentType())
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"twentyquestions-%s.%s\"", session.gameID, format.FileExtension()))
+	err = transcript.Write(w, sessionTranscript, format)
+	if err != nil {
+		log.Error().Str("GameID", session.gameID).Err(err).Msg("Failed to write transcript")
+	}
+}
+
+// JSON API equivalent of the export route, for players that have joined the game.
+func (master *GameMaster) apiExport(w http.ResponseWriter, r *http.Request) {
+	session, ok := master.sessionByID(chi.URLParam(r, "gameID"))
+	if !ok {
+		writeJSONError(w, http.StatusNotFound, "game does not exist")
+		return
+	}
+	if session.hasPassphrase() && !session.checkRequestAdmitted(r) {
+		writeJSONError(w, http.StatusUnauthorized, "passphrase required")
+		return
+	}
+
+	session.handleExport(w, r)
+}
@@ -4,21 +4,43 @@ import (
 	"bytes"
 	"context"
 	"errors"
-	"fmt"
 	"html/template"
 	"net/http"
 	"strings"
 	"sync"
+	"time"
 
-	"github.com/go-chi/chi/v5"
-	"github.com/golang-jwt/jwt/v5"
 	"github.com/microcosm-cc/bluemonday"
 	"github.com/rs/zerolog/log"
+
+	"github.com/hmcalister/twentyquestions/templates"
 )
 
 var (
 	// Templates for Game page.
-	gameTemplate = template.Must(template.ParseFiles("templates/gameBase.html", "templates/gameItem.html", "templates/gameOver.html"))
+	gameTemplate = template.Must(template.New("gameBase.html").Funcs(template.FuncMap{
+		"formatElapsed":   formatElapsed,
+		"formatTimestamp": formatTimestamp,
+	}).ParseFS(templates.Files,
+		"gameBase.html",
+		"gameItem.html",
+		"gameOver.html",
+		"gameControls.html",
+		"scoreboard.html",
+		"gamePassphrase.html",
+		"chat.html",
+		"proposals.html",
+		"roleStyle.html",
+		"replay.html",
+	))
+)
+
+const (
+	// Maximum length of the secret in runes.
+	secretMaxLength int = 64
+
+	// Maximum length of a question, answer or hint in runes -- longer responses are cut short.
+	responseMaxLength int = 280
 )
 
 // Enum for gameState, determining what is required next.
@@ -30,99 +52,70 @@ const (
 	gameState_GameOver         gameStateEnum = iota
 )
 
-// --------------------------------------------------------------------------------
-// JWT Data and Methods
-// --------------------------------------------------------------------------------
+// Enum for entryKind, determining what an entry in the game log represents.
+type entryKindEnum int
 
-// Check a request for the JWT to authenticate the oracle.
-func (data *GameData) checkRequestFromOracle(r *http.Request) bool {
-	// Ensure cookie actually exits
+const (
+	// A question from the guessers, and the answer from the oracle.
+	entryKind_Question entryKindEnum = iota
 
-	tokenCookie, err := r.Cookie(data.gameID)
-	if err != nil {
-		if err == http.ErrNoCookie {
-			log.Debug().Msg("Oracle JWT Check - No Token Cookie")
-			return false
-		}
-		log.Debug().Msg("Oracle JWT Check - Error Reading Cookie")
-		return false
-	}
+	// A free-form hint from the oracle.
+	entryKind_Hint entryKindEnum = iota
+)
 
-	// Get claims from cookie by decoding with key
+// --------------------------------------------------------------------------------
+// Game Data struct
+// --------------------------------------------------------------------------------
 
-	tokenString := tokenCookie.Value
-	claims := &jwt.RegisteredClaims{}
-	token, err := jwt.ParseWithClaims(tokenString, claims, func(t *jwt.Token) (interface{}, error) {
-		return data.oracleJWTKey, nil
-	})
+// Question and Answer pairs -- kept for ease of template parsing. Hints from the oracle are also kept as entries.
+type questionAnswerPair struct {
+	Kind entryKindEnum
 
-	// log.Debug().Interface("JWT Claims", claims).Msg("Oracle JWT Check")
+	// The number of the question in the round. Hints are not numbered, and have an index of zero.
+	Index int
 
-	// Ensure decoding did not fail
+	// The number of questions this entry used from the budget of the round.
+	Cost int
 
-	if err != nil {
-		if err == jwt.ErrSignatureInvalid {
-			log.Debug().Msg("Oracle JWT Check - Invalid Signature")
-			return false
-		}
-		log.Debug().Msg("Oracle JWT Check - Other Token Parse Error")
-		return false
-	}
+	// The player who asked the question -- the name is kept as it was when the question was asked.
+	AskerID   string
+	AskerName string
 
-	// Ensure the token is still valid (e.g. not expired)
+	Question string
+	Answer   string
 
-	if !token.Valid {
-		log.Debug().Msg("Oracle JWT Check - Token Invalid")
-		return false
-	}
+	// The text of the hint, for hint entries only.
+	Hint string
 
-	// Ensure claims match expected
+	// Set if the asker retracted the question before it was answered. Retracted questions do not count towards the budget.
+	Retracted bool
 
-	if claims.Issuer != data.gameID || claims.Subject != "oracle" {
-		return false
-	}
+	// All changes made to the entry after it was added, oldest first.
+	Revisions []entryRevision
 
-	return true
+	// Time the question was asked (or the hint given), and the time it was answered -- zero if not yet answered.
+	AskedAt    time.Time
+	AnsweredAt time.Time
 }
 
-// Middleware to wrap the check for oracleJWT, setting a context value in the request for IsOracle.
-func (data *GameData) checkRequestFromOracleMiddleware(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		ctx := context.WithValue(r.Context(), "IsOracle", data.checkRequestFromOracle(r))
-		next.ServeHTTP(w, r.WithContext(ctx))
-	})
+// Check if the entry is a hint from the oracle -- used by the gameItem.html template.
+func (pair questionAnswerPair) IsHint() bool {
+	return pair.Kind == entryKind_Hint
 }
 
-// --------------------------------------------------------------------------------
-// Game Data struct
-// --------------------------------------------------------------------------------
-
-// Question and Answer pairs -- kept for ease of template parsing.
-type questionAnswerPair struct {
-	Index    int
-	Question string
-	Answer   string
-}
-
-// Server Sent Event client -- to return the question and answers to the clients as responses roll in.
-type sseClient struct {
-	// Context, with cancel indicating if the client has left.
-	context context.Context
-
-	cancelFunc context.CancelFunc
-
-	// Channel to write responses back to user (ensure no newlines!!). Must not be sent data if context is done.
-	responsesChannel chan string
-}
-
-// Data representing an individual game.
+// Data representing an individual round of a game. A GameSession holds one GameData per round played.
 type GameData struct {
 	gameID string
 
-	// Signing key for the oracle JWT.
-	oracleJWTKey []byte
+	// The round number of this game within the session, starting at 1.
+	roundNumber int
+
+	// The player acting as oracle for this round.
+	oracleID   string
+	oracleName string
 
-	router *chi.Mux
+	// The session this round belongs to -- used to broadcast changes to all connected clients.
+	session *GameSession
 
 	// Current state of game -- switches between awaiting question and awaiting answer.
 	gameState gameStateEnum
@@ -130,94 +123,259 @@ type GameData struct {
 	// Mutex to ensure atomic read and write of the game state -- prevents double questions in edge cases.
 	gameStateMutex sync.Mutex
 
+	// Audit entries and webhook events made while the gameStateMutex is held, oldest first -- sent once it is released.
+	pendingNotifications []func()
+
 	// All question answer pairs in this game.
 	questionAnswerPairs []questionAnswerPair
 
+	// Candidate questions proposed by guessers in voting mode, in the order they were proposed.
+	proposals      []*questionProposal
+	nextProposalID int
+
+	// Timer closing the open voting window, or nil if no window is open. Each window is numbered, so a late timer is ignored.
+	votingTimer        *time.Timer
+	votingWindowNumber int
+
 	// String to store current HTML of question answer pairs, to avoid recomputation for every SSE client.
 	allResponsesHTML string
 
 	// BlueMonday HTML Sanitizer -- ensures user input is clean before sending to other clients.
 	htmlSanitizer *bluemonday.Policy
 
-	// Array of all sseClients -- pruned of closed clients when next event is sent.
-	sseClients []*sseClient
+	// Time the round started, and the time the oracle gave their verdict -- zero until the game is over.
+	startedAt time.Time
+	endedAt   time.Time
 
-	// Mutex to ensure atomic handling of SSE clients -- we don't want to accidentally miss a client!
-	sseClientsMutex sync.Mutex
+	// Every change to the round, oldest first -- used to replay the round once it is over.
+	events []roundEvent
+
+	// If the computer guesser plays in this round, asking a question whenever the guessers may ask.
+	computerGuesser bool
+
+	// The secret the oracle is thinking of, if they chose to record it. Only revealed to guessers once the game is over.
+	secret string
+
+	// The verdict given by the oracle, only meaningful once the game is over.
+	verdictCorrect bool
+
+	// Set if an admin ended the round instead of the oracle -- the round has no verdict and no winner.
+	forceEnded bool
+
+	// The player who won the round -- the guesser who asked the final question if correct, or the oracle if incorrect.
+	// Empty if the game is not over, or if the oracle declared a correct verdict before any question was asked.
+	winnerID string
 }
 
-// Create a new game data, including registering routes on router.
-func newGameData(gameID string, oracleJWTKey []byte, htmlSanitizer *bluemonday.Policy) *GameData {
+// Create a new game data for a round of the session.
+func newGameData(session *GameSession, roundNumber int, oracle *sessionPlayer) *GameData {
 	data := &GameData{
-		gameID:              gameID,
-		oracleJWTKey:        oracleJWTKey,
-		router:              chi.NewRouter(),
+		gameID:              session.gameID,
+		roundNumber:         roundNumber,
+		oracleID:            oracle.ID,
+		oracleName:          oracle.Name,
+		session:             session,
 		gameState:           gameState_AwaitingQuestion,
 		questionAnswerPairs: make([]questionAnswerPair, 0),
+		proposals:           make([]*questionProposal, 0),
 		allResponsesHTML:    "",
-		htmlSanitizer:       htmlSanitizer,
-		sseClients:          make([]*sseClient, 0),
+		startedAt:           time.Now(),
+		htmlSanitizer:       session.master.htmlSanitizer,
 	}
 
-	// Always check if request is from the oracle.
-	data.router.Use(data.checkRequestFromOracleMiddleware)
+	if data.hasComputerOracle() {
+		data.secret, _ = session.master.pickComputerOracleSecret(session.config.category)
+	} else if session.config.pickSecret {
+		data.secret, _ = session.master.pickSecret(session.config.category, session.config.secretDifficulty)
+	}
 
-	data.router.Get("/"+data.gameID+"/", data.renderGameBase)
-	data.router.Post("/"+data.gameID+"/submitResponse", data.handleNewResponse)
-	data.router.Get("/"+data.gameID+"/responsesSourceSSE", data.responsesSourceSSE)
-	data.router.Get("/"+data.gameID+"/oracleVerdictCorrect", data.oracleVerdictCorrect)
-	data.router.Get("/"+data.gameID+"/oracleVerdictIncorrect", data.oracleVerdictIncorrect)
+	data.gameStateMutex.Lock()
+	data.recordEvent(roundEventKind_RoundStarted, data.oracleIdentity(), -1, "")
+	data.updateResponsesHTML()
+	data.unlockGameState()
 
 	return data
 }
 
-func (data *GameData) sendClientsResponseHTML() {
-	// Loop over clients, splice out any that are closed, send to any that are alive.
-
-	log.Debug().Msg("SENDING CLIENTS")
-
-	data.sseClientsMutex.Lock()
-	defer data.sseClientsMutex.Unlock()
-
-	// Note this loop does NOT always increment i, as sometimes we splice out a done client and must repeat that index.
-	// If we splice out the last client, the i will now be equal to len(data.sseClients) so the loop will terminate, not overrun its bounds
-	for i := 0; i < len(data.sseClients); {
-		currentClient := data.sseClients[i]
-		select {
-		// If this client is done, the context is cancelled, and we can close the response channel to clean up some goroutines.
-		case <-currentClient.context.Done():
-			close(currentClient.responsesChannel)
-			// Splice out the done client with the end client. Then remove the end client.
-			// This requires us to look at the current index again, so don't update i.
-			data.sseClients[i] = data.sseClients[len(data.sseClients)-1]
-			data.sseClients = data.sseClients[:len(data.sseClients)-1]
-		default:
-			// This client is still alive, send them the new HTML and move to the next client.
-			currentClient.responsesChannel <- data.allResponsesHTML
-			i += 1
+// Queue a notification, such as an audit entry or a webhook event, to be sent once the gameStateMutex is released.
+// Writing the audit log and publishing to webhooks are kept out from under the lock, so they never hold up the game.
+//
+// The caller must hold the gameStateMutex, and release it with unlockGameState.
+func (data *GameData) queueNotification(notification func()) {
+	data.pendingNotifications = append(data.pendingNotifications, notification)
+}
+
+// Release the gameStateMutex, then send the notifications queued while it was held.
+func (data *GameData) unlockGameState() {
+	notifications := data.pendingNotifications
+	data.pendingNotifications = nil
+	data.gameStateMutex.Unlock()
+
+	for _, notification := range notifications {
+		notification()
+	}
+}
+
+// Data to be passed to gameItem.html template
+type gameItemTemplateData struct {
+	RoundNumber         int
+	OracleName          string
+	QuestionsRemaining  int
+	QuestionAnswerPairs []questionAnswerPair
+	IsGameOver          bool
+	VerdictCorrect      bool
+	ForceEnded          bool
+	Secret              string
+	StartedAt           time.Time
+	EndedAt             time.Time
+	ArchiveURL          string
+}
+
+// Data to be passed to gameOver.html template
+type gameOverTemplateData struct {
+	RoundNumber    int
+	VerdictCorrect bool
+	ForceEnded     bool
+	Secret         string
+	Duration       string
+	ArchiveURL     string
+}
+
+// Get the data for the game over card from the gameItem.html template data.
+func (templateData gameItemTemplateData) GameOver() gameOverTemplateData {
+	return gameOverTemplateData{
+		RoundNumber:    templateData.RoundNumber,
+		VerdictCorrect: templateData.VerdictCorrect,
+		ForceEnded:     templateData.ForceEnded,
+		Secret:         templateData.Secret,
+		Duration:       formatElapsed(templateData.StartedAt, templateData.EndedAt),
+		ArchiveURL:     templateData.ArchiveURL,
+	}
+}
+
+// Rerender the allResponsesHTML field from the current question answer pairs.
+//
+// The caller must hold the gameStateMutex.
+func (data *GameData) updateResponsesHTML() {
+	templateData := gameItemTemplateData{
+		RoundNumber:         data.roundNumber,
+		OracleName:          data.oracleName,
+		QuestionsRemaining:  data.session.config.questionBudget - data.budgetUsed(),
+		QuestionAnswerPairs: data.questionAnswerPairs,
+		IsGameOver:          data.gameState == gameState_GameOver,
+		VerdictCorrect:      data.verdictCorrect,
+		ForceEnded:          data.forceEnded,
+		Secret:              data.secret,
+		StartedAt:           data.startedAt,
+		EndedAt:             data.endedAt,
+	}
+	if data.session.isArchived() {
+		templateData.ArchiveURL = archiveURL(data.gameID)
+	}
+
+	var updatedResponsesBytes bytes.Buffer
+	err := gameTemplate.ExecuteTemplate(&updatedResponsesBytes, "gameItem.html", templateData)
+	if err != nil {
+		log.Error().Str("GameID", data.gameID).Err(err).Msg("Failed to write game item template")
+		return
+	}
+	data.allResponsesHTML = updatedResponsesBytes.String()
+}
+
+// Get the current HTML of all responses in this round.
+func (data *GameData) responsesHTML() string {
+	data.gameStateMutex.Lock()
+	defer data.gameStateMutex.Unlock()
+	return data.allResponsesHTML
+}
+
+// Check if the oracle has given a verdict for this round.
+func (data *GameData) isGameOver() bool {
+	data.gameStateMutex.Lock()
+	defer data.gameStateMutex.Unlock()
+	return data.gameState == gameState_GameOver
+}
+
+// Get the number of questions used from the budget so far in this round, including the cost of any hints.
+func (data *GameData) questionsUsed() int {
+	data.gameStateMutex.Lock()
+	defer data.gameStateMutex.Unlock()
+	return data.budgetUsed()
+}
+
+// Sum the cost of all entries in the round.
+//
+// The caller must hold the gameStateMutex.
+func (data *GameData) budgetUsed() int {
+	used := 0
+	for _, pair := range data.questionAnswerPairs {
+		used += pair.Cost
+	}
+	return used
+}
+
+// Count the question entries in the round, ignoring hints and retracted questions.
+//
+// The caller must hold the gameStateMutex.
+func (data *GameData) questionEntryCount() int {
+	count := 0
+	for _, pair := range data.questionAnswerPairs {
+		if pair.Kind == entryKind_Question && !pair.Retracted {
+			count += 1
 		}
 	}
+	return count
+}
+
+// Get the winner of this round, or an empty string if there is no winner (yet).
+func (data *GameData) roundWinnerID() string {
+	data.gameStateMutex.Lock()
+	defer data.gameStateMutex.Unlock()
+	return data.winnerID
 }
 
 // Add a new question. Returns an error if the game is currently awaiting an answer instead.
 // Change of state is handled internally by this function.
-func (data *GameData) addNextQuestion(question string) error {
+func (data *GameData) addNextQuestion(asker playerIdentity, question string) error {
 	// Ensure the game state is checked atomically.
 	//
 	// If two clients submit a question at the same time, one will get the lock and the question, and the other is turned away.
 	data.gameStateMutex.Lock()
-	defer data.gameStateMutex.Unlock()
+	defer data.unlockGameState()
+	return data.askQuestion(asker, question)
+}
 
+// Add a new question, as in addNextQuestion.
+//
+// The caller must hold the gameStateMutex, and release it with unlockGameState.
+func (data *GameData) askQuestion(asker playerIdentity, question string) error {
 	if data.gameState != gameState_AwaitingQuestion {
 		return errors.New("not currently awaiting question")
 	}
+	if data.budgetUsed() >= data.session.config.questionBudget {
+		return errors.New("no questions remaining")
+	}
 
 	nextQApair := questionAnswerPair{
-		Index:    len(data.questionAnswerPairs) + 1,
-		Question: question,
+		Kind:      entryKind_Question,
+		Index:     data.questionEntryCount() + 1,
+		Cost:      1,
+		AskerID:   asker.ID,
+		AskerName: asker.Name,
+		Question:  question,
+		AskedAt:   time.Now(),
 	}
 	data.questionAnswerPairs = append(data.questionAnswerPairs, nextQApair)
 	data.gameState = gameState_AwaitingAnswer
+	data.recordEvent(roundEventKind_QuestionAsked, asker, len(data.questionAnswerPairs)-1, question)
+	data.updateResponsesHTML()
+	data.queueNotification(func() { data.publishQuestionAsked(nextQApair) })
+
+	// The computer oracle answers on its own, after a short delay. The position is kept so the delayed answer is never given to a later question.
+	if data.hasComputerOracle() {
+		position := len(data.questionAnswerPairs) - 1
+		time.AfterFunc(computerOracleAnswerDelay, func() { data.answerAsComputerOracle(position) })
+	}
 	return nil
 }
 
@@ -228,204 +386,309 @@ func (data *GameData) addNextAnswer(answer string) error {
 	//
 	// If the oracle submits two answers at the same time, one will get the lock and the other is turned away.
 	data.gameStateMutex.Lock()
-	defer data.gameStateMutex.Unlock()
+	defer data.unlockGameState()
+	return data.answerQuestion(answer)
+}
 
+// Add a new answer, as in addNextAnswer.
+//
+// The caller must hold the gameStateMutex, and release it with unlockGameState.
+func (data *GameData) answerQuestion(answer string) error {
 	if data.gameState != gameState_AwaitingAnswer {
 		return errors.New("not currently awaiting answer")
 	}
 
-	data.questionAnswerPairs[len(data.questionAnswerPairs)-1].Answer = answer
+	answeredPair := &data.questionAnswerPairs[len(data.questionAnswerPairs)-1]
+	answeredPair.Answer = answer
+	answeredPair.AnsweredAt = time.Now()
+	recordAnswerTiming(answeredPair.AnsweredAt.Sub(answeredPair.AskedAt))
+	data.recordEvent(roundEventKind_QuestionAnswered, data.oracleIdentity(), len(data.questionAnswerPairs)-1, answer)
 	data.gameState = gameState_AwaitingQuestion
+	data.updateResponsesHTML()
+	// The entry is copied, as it may change once the lock is released.
+	answered := *answeredPair
+	data.queueNotification(func() { data.publishAnswerGiven(answered) })
+
+	// The computer guesser asks the next question on its own, unless another guesser asks first.
+	if data.computerGuesser {
+		time.AfterFunc(computerGuesserQuestionDelay, data.askAsComputerGuesser)
+	}
 	return nil
 }
 
-func (data *GameData) gameCleanup() {
-	data.sseClientsMutex.Lock()
-	defer data.sseClientsMutex.Unlock()
+// Record the secret the oracle is thinking of. Returns an error if the game is already over.
+func (data *GameData) setSecret(secret string) error {
+	data.gameStateMutex.Lock()
+	defer data.unlockGameState()
 
-	// Note this loop does NOT always increment i, as sometimes we splice out a done client and must repeat that index.
-	// If we splice out the last client, the i will now be equal to len(data.sseClients) so the loop will terminate, not overrun its bounds
-	for i := len(data.sseClients) - 1; i >= 0; i-- {
-		currentClient := data.sseClients[i]
-		close(currentClient.responsesChannel)
-		select {
-		case <-currentClient.context.Done():
-		default:
-			currentClient.cancelFunc()
+	if data.gameState == gameState_GameOver {
+		return errors.New("game is already over")
+	}
+	data.secret = secret
+	data.queueAudit(auditAction_SecretSet, data.oracleIdentity(), secret)
+	return nil
+}
+
+// Get the secret recorded by the oracle, or an empty string if none was recorded.
+func (data *GameData) recordedSecret() string {
+	data.gameStateMutex.Lock()
+	defer data.gameStateMutex.Unlock()
+	return data.secret
+}
+
+// Add a hint from the oracle, costing the guessers questions from their budget. Hints may only be given while awaiting a question.
+// Returns an error if the game is not awaiting a question.
+func (data *GameData) addHint(hint string) error {
+	data.gameStateMutex.Lock()
+	defer data.unlockGameState()
+
+	if data.gameState != gameState_AwaitingQuestion {
+		return errors.New("not currently awaiting question")
+	}
+
+	// The cost is capped so a hint never takes the guessers over budget.
+	hintCost := min(data.session.config.hintCost, max(data.session.config.questionBudget-data.budgetUsed(), 0))
+	data.questionAnswerPairs = append(data.questionAnswerPairs, questionAnswerPair{
+		Kind:    entryKind_Hint,
+		Cost:    hintCost,
+		Hint:    hint,
+		AskedAt: time.Now(),
+	})
+	data.recordEvent(roundEventKind_HintGiven, data.oracleIdentity(), len(data.questionAnswerPairs)-1, hint)
+	data.updateResponsesHTML()
+	return nil
+}
+
+// End the game with the oracle's verdict, determining the winner of the round.
+// Returns an error if the game is already over.
+func (data *GameData) setVerdict(correct bool) error {
+	data.gameStateMutex.Lock()
+	defer data.unlockGameState()
+
+	if data.gameState == gameState_GameOver {
+		return errors.New("game is already over")
+	}
+
+	data.gameState = gameState_GameOver
+	data.verdictCorrect = correct
+	data.endedAt = time.Now()
+	recordRoundTiming(data.endedAt.Sub(data.startedAt))
+	data.clearProposals()
+	if correct {
+		// The winner asked the final question -- hints have no asker and retracted questions were never answered, so both are skipped.
+		for i := len(data.questionAnswerPairs) - 1; i >= 0; i-- {
+			if data.questionAnswerPairs[i].Kind == entryKind_Question && !data.questionAnswerPairs[i].Retracted {
+				data.winnerID = data.questionAnswerPairs[i].AskerID
+				break
+			}
 		}
+	} else {
+		data.winnerID = data.oracleID
 	}
 
-	data.sseClients = make([]*sseClient, 0)
+	verdictEventKind := roundEventKind_VerdictIncorrect
+	if correct {
+		verdictEventKind = roundEventKind_VerdictCorrect
+	}
+	data.recordEvent(verdictEventKind, data.oracleIdentity(), -1, "")
+	data.updateResponsesHTML()
+	return nil
+}
+
+// End the round without a verdict, leaving it with no winner -- used when an admin ends the game.
+// Returns an error if the round is already over.
+func (data *GameData) forceEnd() error {
+	data.gameStateMutex.Lock()
+	defer data.unlockGameState()
+
+	if data.gameState == gameState_GameOver {
+		return errors.New("game is already over")
+	}
+
+	data.gameState = gameState_GameOver
+	data.forceEnded = true
+	data.endedAt = time.Now()
+	data.clearProposals()
+	data.recordEvent(roundEventKind_RoundEnded, adminIdentity, -1, "")
+	data.updateResponsesHTML()
+	return nil
 }
 
 // --------------------------------------------------------------------------------
-// Routing Functions
+// Player Actions
 // --------------------------------------------------------------------------------
 
-// Data to be passed to gameBase.html template
-type gameBaseTemplateData struct {
-	GameID   string
-	IsOracle bool
+// Ask a question as a guesser, sending the updated responses to all clients.
+// Returns an error if the game is not awaiting a question, or if guessers must propose questions in voting mode.
+func (data *GameData) ask(asker playerIdentity, question string) error {
+	// In voting mode guessers must propose questions instead, with the top proposal asked by the game.
+	if data.session.config.votingMode {
+		return errors.New("guessers must propose questions in voting mode")
+	}
+
+	err := data.addNextQuestion(asker, question)
+	if err != nil {
+		return err
+	}
+	data.session.broadcastResponses(data)
+	return nil
 }
 
-// Render the game base -- should the first call to the game router.
-func (data *GameData) renderGameBase(w http.ResponseWriter, r *http.Request) {
-	// Render the template with all current data. Ensures late players still get all previous questions and answers.
-	err := gameTemplate.ExecuteTemplate(w, "gameBase.html", gameBaseTemplateData{
-		GameID:   data.gameID,
-		IsOracle: r.Context().Value("IsOracle").(bool),
-	})
+// Answer the pending question as the oracle, sending the updated responses to all clients.
+// Returns an error if the game is not awaiting an answer.
+func (data *GameData) answer(answer string) error {
+	err := data.addNextAnswer(answer)
 	if err != nil {
-		log.Error().Interface("GameData", data).Err(err).Msg("Failed to write game base template")
-		w.WriteHeader(http.StatusInternalServerError)
+		return err
 	}
+	data.session.broadcastResponses(data)
+	return nil
+}
+
+// End the round with the oracle's verdict, then finish the round for the session.
+// Returns an error if the game is already over.
+func (data *GameData) giveVerdict(correct bool) error {
+	err := data.setVerdict(correct)
+	if err != nil {
+		return err
+	}
+	data.session.broadcaster.broadcast(data.proposalsEvent())
+	data.session.finishRound(data)
+	return nil
+}
+
+// --------------------------------------------------------------------------------
+// Routing Functions
+// --------------------------------------------------------------------------------
+
+// Set the IsOracle context value in the request for this round.
+func (data *GameData) withRoundRole(r *http.Request) *http.Request {
+	isOracle := playerFromRequest(r).ID == data.oracleID
+	ctx := context.WithValue(r.Context(), "IsOracle", isOracle)
+	return r.WithContext(ctx)
 }
 
 // Handle a response in the game -- this function handles both guesser and oracle responses.
 //
 // This function also updates the questionAnswerPairs and allResponsesHTML fields, and sends this data to all SSE clients.
 func (data *GameData) handleNewResponse(w http.ResponseWriter, r *http.Request) {
-	response := r.FormValue("response")
+	response := truncateRunes(r.FormValue("response"), responseMaxLength)
 	// response := data.htmlSanitizer.Sanitize(r.FormValue("response"))
 	log.Debug().Str("Game ID", data.gameID).Str("Response", response).Msg("Game Response")
 
-	if len(response) == 0 || data.gameState == gameState_GameOver {
+	if len(response) == 0 {
 		w.WriteHeader(http.StatusBadRequest)
 		return
 	}
 
 	isOracle := r.Context().Value("IsOracle").(bool)
 	if isOracle {
-		err := data.addNextAnswer(response)
+		err := data.answer(response)
 		if err != nil {
 			log.Debug().Msg("Not Oracles Turn!")
 			w.WriteHeader(http.StatusBadRequest)
 			return
 		}
 	} else {
-		err := data.addNextQuestion(response)
+		err := data.ask(playerFromRequest(r), response)
 		if err != nil {
-			log.Debug().Msg("Not Guessers Turn!")
+			log.Debug().Err(err).Msg("Not Guessers Turn!")
 			w.WriteHeader(http.StatusBadRequest)
 			return
 		}
 	}
 
 	w.WriteHeader(http.StatusOK)
+}
 
-	// Update the allResponseHTML field to send to all sse clients
+// Give a hint to the guessers -- only the oracle may do this.
+func (data *GameData) handleGiveHint(w http.ResponseWriter, r *http.Request) {
+	hint := truncateRunes(r.FormValue("response"), responseMaxLength)
+	isOracle := r.Context().Value("IsOracle").(bool)
+	if !isOracle {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	if len(hint) == 0 {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
 
-	var updatedResponsesBytes bytes.Buffer
-	err := gameTemplate.ExecuteTemplate(&updatedResponsesBytes, "gameItem.html", data.questionAnswerPairs)
+	err := data.addHint(hint)
 	if err != nil {
-		log.Error().Interface("GameData", data).Err(err).Msg("Failed to write game item template")
+		log.Debug().Msg("Hint Not Allowed Now!")
+		w.WriteHeader(http.StatusBadRequest)
 		return
 	}
-	data.allResponsesHTML = updatedResponsesBytes.String()
-	data.allResponsesHTML = strings.ReplaceAll(data.allResponsesHTML, "\n", "")
-	data.sendClientsResponseHTML()
-}
 
-// SSE endpoint
-func (data *GameData) responsesSourceSSE(w http.ResponseWriter, r *http.Request) {
-	log.Debug().Msg("New Client SSE Connection")
-	w.Header().Set("Content-Type", "text/event-stream")
-	w.Header().Set("Cache-Control", "no-cache")
-	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	data.session.broadcastResponses(data)
+}
 
-	data.gameStateMutex.Lock()
-	isGameOver := data.gameState == gameState_GameOver
-	data.gameStateMutex.Unlock()
-	if isGameOver {
-		fmt.Fprintf(w, "data: %s\n\n", data.allResponsesHTML)
-		w.(http.Flusher).Flush()
-		<-r.Context().Done()
+// Record the secret the oracle is thinking of -- only the oracle may do this. Responds with the updated controls.
+func (data *GameData) handleSetSecret(w http.ResponseWriter, r *http.Request) {
+	isOracle := r.Context().Value("IsOracle").(bool)
+	if !isOracle {
+		w.WriteHeader(http.StatusUnauthorized)
 		return
 	}
 
-	ctx, cancel := context.WithCancel(r.Context())
-	defer cancel()
-
-	// Make a new SSE client using the request context and responses channel
-	responsesChannel := make(chan string)
-	newClient := &sseClient{
-		context:          ctx,
-		cancelFunc:       cancel,
-		responsesChannel: responsesChannel,
+	err := data.setSecret(truncateRunes(strings.TrimSpace(r.FormValue("secret")), secretMaxLength))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
 	}
 
-	// Atomically add the new client to the clients list -- mutex avoids appending to list while splicing out list in handleResponse handler.
-	data.sseClientsMutex.Lock()
-	data.sseClients = append(data.sseClients, newClient)
-	log.Debug().Int("Total Clients", len(data.sseClients)).Msg("New Client Added")
-	data.sseClientsMutex.Unlock()
-
-	// This goroutine terminates when responsesChannel is closed, which is handled in handleResponse handler when the client context is cancelled (client leaves).
-	go func() {
-		for responsesHTML := range responsesChannel {
-			fmt.Fprintf(w, "data: %s\n\n", responsesHTML)
-			w.(http.Flusher).Flush()
-		}
-	}()
-
-	// Block return until the response is canceled -- ensures the client only makes ONE connection, rather than continually polling.
-	<-r.Context().Done()
+	data.session.renderControls(w, r)
 }
 
-// Used when the oracle ends the game with a correct verdict
-func (data *GameData) oracleVerdictCorrect(w http.ResponseWriter, r *http.Request) {
+// Pick a random secret from the word packs for the oracle -- only the oracle may do this. Responds with the updated controls.
+func (data *GameData) handlePickSecret(w http.ResponseWriter, r *http.Request) {
 	isOracle := r.Context().Value("IsOracle").(bool)
 	if !isOracle {
 		w.WriteHeader(http.StatusUnauthorized)
 		return
 	}
 
-	if data.gameState == gameState_GameOver {
-		w.WriteHeader(http.StatusBadRequest)
+	secret, ok := data.session.master.pickSecret(data.session.config.category, data.session.config.secretDifficulty)
+	if !ok {
+		log.Debug().Str("GameID", data.gameID).Msg("No Secrets To Pick From!")
+		w.WriteHeader(http.StatusNotFound)
 		return
 	}
 
-	data.gameStateMutex.Lock()
-	data.gameState = gameState_GameOver
-	defer data.gameStateMutex.Unlock()
-	defer data.gameCleanup()
-
-	var updatedResponsesBytes bytes.Buffer
-	err := gameTemplate.ExecuteTemplate(&updatedResponsesBytes, "gameOver.html", true)
+	err := data.setSecret(secret)
 	if err != nil {
-		log.Error().Interface("GameData", data).Err(err).Msg("Failed to write game over template")
+		w.WriteHeader(http.StatusBadRequest)
 		return
 	}
-	data.allResponsesHTML += updatedResponsesBytes.String()
-	data.allResponsesHTML = strings.ReplaceAll(data.allResponsesHTML, "\n", "")
-	data.sendClientsResponseHTML()
+
+	data.session.renderControls(w, r)
 }
 
-// Used when the oracle ends the game with an incorrect verdict
-func (data *GameData) oracleVerdictIncorrect(w http.ResponseWriter, r *http.Request) {
+// Shared handling of the oracle ending the game with a verdict.
+func (data *GameData) handleOracleVerdict(w http.ResponseWriter, r *http.Request, correct bool) {
 	isOracle := r.Context().Value("IsOracle").(bool)
 	if !isOracle {
 		w.WriteHeader(http.StatusUnauthorized)
 		return
 	}
 
-	if data.gameState == gameState_GameOver {
+	err := data.giveVerdict(correct)
+	if err != nil {
 		w.WriteHeader(http.StatusBadRequest)
 		return
 	}
 
-	data.gameStateMutex.Lock()
-	data.gameState = gameState_GameOver
-	defer data.gameStateMutex.Unlock()
-	defer data.gameCleanup()
+	w.WriteHeader(http.StatusOK)
+}
 
-	var updatedResponsesBytes bytes.Buffer
-	err := gameTemplate.ExecuteTemplate(&updatedResponsesBytes, "gameOver.html", false)
-	if err != nil {
-		log.Error().Interface("GameData", data).Err(err).Msg("Failed to write game over template")
-		return
-	}
-	data.allResponsesHTML += updatedResponsesBytes.String()
-	data.allResponsesHTML = strings.ReplaceAll(data.allResponsesHTML, "\n", "")
-	data.sendClientsResponseHTML()
+// Used when the oracle ends the game with a correct verdict
+func (data *GameData) oracleVerdictCorrect(w http.ResponseWriter, r *http.Request) {
+	data.handleOracleVerdict(w, r, true)
+}
+
+// Used when the oracle ends the game with an incorrect verdict
+func (data *GameData) oracleVerdictIncorrect(w http.ResponseWriter, r *http.Request) {
+	data.handleOracleVerdict(w, r, false)
 }
@@ -1,16 +1,26 @@
 package game
 
 import (
+	cryptorand "crypto/rand"
 	"fmt"
 	"net/http"
+	"slices"
+	"strconv"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/go-chi/chi/v5"
-	"github.com/golang-jwt/jwt/v5"
 	"github.com/microcosm-cc/bluemonday"
 	"github.com/rs/zerolog/log"
 	"golang.org/x/exp/rand"
+
+	"github.com/hmcalister/twentyquestions/archive"
+	"github.com/hmcalister/twentyquestions/audit"
+	"github.com/hmcalister/twentyquestions/knowledge"
+	"github.com/hmcalister/twentyquestions/stats"
+	"github.com/hmcalister/twentyquestions/webhooks"
+	"github.com/hmcalister/twentyquestions/wordpacks"
 )
 
 const (
@@ -31,8 +41,23 @@ type GameMaster struct {
 	// The Router for the overall game routes, such as /game/new.
 	Router *chi.Mux
 
-	// Map of the games currently alive. Maps from GameID to a gameData.
-	gameMap map[string]*GameData
+	// The Router for the public lobby, to be mounted at /lobby.
+	LobbyRouter *chi.Mux
+
+	// The Router for the JSON API, to be mounted at /api.
+	APIRouter *chi.Mux
+
+	// The Router for the archive of completed games, to be mounted at /archive.
+	ArchiveRouter *chi.Mux
+
+	// The Router for the player leaderboards, to be mounted at /leaderboard.
+	LeaderboardRouter *chi.Mux
+
+	// The Router for the admin pages, to be mounted at /admin behind authentication.
+	AdminRouter *chi.Mux
+
+	// Map of the games currently alive. Maps from GameID to a game session.
+	gameMap map[string]*GameSession
 
 	// Mutex to handle async writing and reading from the game map.
 	gameMapMutex sync.RWMutex
@@ -40,18 +65,82 @@ type GameMaster struct {
 	// Random number generator for the game master.
 	rng *rand.Rand
 
+	// Mutex to handle async use of the random number generator, which is not safe for concurrent use.
+	rngMutex sync.Mutex
+
+	// Issues and checks the player identity shared across all games.
+	playerIdentifier *playerIdentifier
+
 	// BlueMonday HTML sanitizer -- to be initialized once and passed to games.
 	htmlSanitizer *bluemonday.Policy
+
+	// Broadcaster for all clients viewing the public lobby.
+	lobbyBroadcaster *sseBroadcaster
+
+	// Maximums on the number of games and connections.
+	limits CapacityLimits
+
+	// Archive of completed games, kept after the games are removed from the game map.
+	archive *archive.Archive
+
+	// Statistics of all players, recorded as rounds finish.
+	stats *stats.Tracker
+
+	// Packs of secrets the oracle may have picked for them.
+	wordPacks *wordpacks.Library
+
+	// Entities and their attributes, used by the computer oracle to pick a secret and answer questions.
+	knowledgeBase *knowledge.Base
+
+	// Sends game events to the webhooks subscribed to them.
+	webhooks *webhooks.Dispatcher
+
+	// Append-only record of every change to a round, and who made it.
+	auditLog *audit.Log
+
+	// Time the game master was created, reported as the uptime of the server.
+	startedAt time.Time
+
+	// Set once the server is shutting down, so it reports not ready for new players.
+	draining atomic.Bool
+
+	// The maintenance notice shown to every client, or empty if there is none -- set by an admin.
+	notice      string
+	noticeMutex sync.Mutex
 }
 
 // Create a new Game Master and return the struct, including the router to be mounted.
-func NewGameMaster() *GameMaster {
+//
+// Player identities are signed with the player key, so players keep their identity -- and their statistics -- for as long
+// as the key is kept. A nil key is replaced with a random one, so every player is new when the server restarts.
+func NewGameMaster(limits CapacityLimits, gameArchive *archive.Archive, statsTracker *stats.Tracker, playerKey []byte, wordPacks *wordpacks.Library, knowledgeBase *knowledge.Base, webhookDispatcher *webhooks.Dispatcher, auditLog *audit.Log) *GameMaster {
 	master := &GameMaster{
-		Router:        chi.NewRouter(),
-		gameMap:       make(map[string]*GameData),
-		rng:           rand.New(rand.NewSource(uint64(time.Now().UnixNano()))),
-		htmlSanitizer: bluemonday.UGCPolicy(),
+		Router:            chi.NewRouter(),
+		LobbyRouter:       chi.NewRouter(),
+		APIRouter:         chi.NewRouter(),
+		ArchiveRouter:     chi.NewRouter(),
+		LeaderboardRouter: chi.NewRouter(),
+		AdminRouter:       chi.NewRouter(),
+		gameMap:           make(map[string]*GameSession),
+		rng:               rand.New(rand.NewSource(uint64(time.Now().UnixNano()))),
+		htmlSanitizer:     bluemonday.UGCPolicy(),
+		lobbyBroadcaster:  newSSEBroadcaster(0),
+		limits:            limits,
+		archive:           gameArchive,
+		stats:             statsTracker,
+		wordPacks:         wordPacks,
+		knowledgeBase:     knowledgeBase,
+		webhooks:          webhookDispatcher,
+		auditLog:          auditLog,
+		startedAt:         time.Now(),
+	}
+	if playerKey == nil {
+		playerKey = randomKey(64)
 	}
+	master.playerIdentifier = newPlayerIdentifier(playerKey)
+
+	// Always identify the player, so games know who is asking and who is the oracle.
+	master.Router.Use(master.identifyPlayerMiddleware)
 
 	// Route to make a new game.
 	master.Router.Get("/new", master.newGame)
@@ -59,6 +148,56 @@ func NewGameMaster() *GameMaster {
 	// Route to be forward to the individual game with the respective gameID.
 	master.Router.HandleFunc("/{gameID}/*", master.handleGame)
 
+	// Routes for the public lobby, updated live over SSE.
+	master.LobbyRouter.Get("/", master.renderLobby)
+	master.LobbyRouter.Get("/events", master.lobbySourceSSE)
+	go master.refreshLobbyPeriodically()
+
+	// Routes for the JSON API.
+	master.APIRouter.Use(master.identifyPlayerMiddleware)
+	master.APIRouter.Get("/lobby", master.apiLobby)
+	master.APIRouter.Get("/games/{gameID}/state", master.apiGameState)
+	master.APIRouter.Get("/games/{gameID}/export", master.apiExport)
+	master.APIRouter.Get("/archive", master.apiArchive)
+	master.APIRouter.Get("/archive/{gameID}", master.apiArchivedGame)
+	master.APIRouter.Get("/leaderboard", master.apiLeaderboard)
+	master.APIRouter.Get("/players/{playerID}/stats", master.apiPlayerStats)
+	master.APIRouter.Get("/openapi.json", master.apiOpenAPIDocument)
+	master.APIRouter.Get("/docs", master.renderAPIDocs)
+	openAPISpec.checkRoutes(master.APIRouter)
+
+	// Routes for the archive of completed games.
+	master.ArchiveRouter.Get("/", master.renderArchive)
+	master.ArchiveRouter.Get("/{gameID}", master.renderArchivedGame)
+	master.ArchiveRouter.Get("/{gameID}/export", master.exportArchivedGame)
+	go master.archive.PrunePeriodically()
+
+	// Routes for the player leaderboards -- players are identified so they can see their own statistics.
+	master.LeaderboardRouter.Use(master.identifyPlayerMiddleware)
+	master.LeaderboardRouter.Get("/", master.renderLeaderboard)
+
+	// Routes for the admin console, to manage live games and set the maintenance notice.
+	master.AdminRouter.Get("/", master.renderAdminGames)
+	master.AdminRouter.Get("/games/{gameID}", master.renderAdminSpectate)
+	master.AdminRouter.Get("/games/{gameID}/events", master.adminSpectateSSE)
+	master.AdminRouter.Post("/games/{gameID}/end", master.adminEndGame)
+	master.AdminRouter.Post("/games/{gameID}/delete", master.adminDeleteGame)
+	master.AdminRouter.Post("/games/{gameID}/extend", master.adminExtendGame)
+	master.AdminRouter.Post("/notice", master.adminSetNotice)
+
+	// Routes for the admin pages, to review the knowledge learned from completed games.
+	master.AdminRouter.Get("/knowledge", master.renderKnowledgeReview)
+	master.AdminRouter.Post("/knowledge/review", master.handleKnowledgeReview)
+
+	// Routes for the admin to manage webhooks and view the delivery log.
+	master.AdminRouter.Get("/webhooks", master.adminListWebhooks)
+	master.AdminRouter.Post("/webhooks", master.adminAddWebhook)
+	master.AdminRouter.Delete("/webhooks/{webhookID}", master.adminRemoveWebhook)
+	master.AdminRouter.Post("/webhooks/{webhookID}/test", master.adminTestWebhook)
+
+	// Route for the admin to query the audit log of a game.
+	master.AdminRouter.Get("/audit/{gameID}", master.adminAuditLog)
+
 	return master
 }
 
@@ -68,6 +207,9 @@ func NewGameMaster() *GameMaster {
 
 // Create a random string of a specific length, with runes taken from constant array letterRunes.
 func (master *GameMaster) randomString(length int) string {
+	master.rngMutex.Lock()
+	defer master.rngMutex.Unlock()
+
 	stringRunes := make([]rune, length)
 	for i := range stringRunes {
 		stringRunes[i] = letterRunes[master.rng.Intn(len(letterRunes))]
@@ -75,16 +217,98 @@ func (master *GameMaster) randomString(length int) string {
 	return string(stringRunes)
 }
 
+// Create a random key to sign tokens with. Keys come from crypto/rand, as the time-seeded rng could be guessed.
+func randomKey(length int) []byte {
+	key := make([]byte, length)
+	cryptorand.Read(key)
+	return key
+}
+
+// Get the categories a game may be created with -- the lobby categories, then any new categories from the word packs.
+func (master *GameMaster) Categories() []string {
+	categories := slices.Clone(LobbyCategories)
+	for _, category := range master.wordPacks.Categories() {
+		if !slices.Contains(categories, category) {
+			categories = append(categories, category)
+		}
+	}
+	return categories
+}
+
+// Pick a random secret from the word packs. Returns false if no pack has a word in the category with the difficulty.
+func (master *GameMaster) pickSecret(category string, difficulty wordpacks.Difficulty) (string, bool) {
+	candidates := master.wordPacks.Candidates(category, difficulty)
+	if len(candidates) == 0 {
+		return "", false
+	}
+
+	master.rngMutex.Lock()
+	defer master.rngMutex.Unlock()
+	return candidates[master.rng.Intn(len(candidates))], true
+}
+
+// Truncate a string to at most length runes.
+func truncateRunes(value string, length int) string {
+	valueRunes := []rune(value)
+	if len(valueRunes) > length {
+		valueRunes = valueRunes[:length]
+	}
+	return string(valueRunes)
+}
+
+// Parse an integer from a form value, falling back to the default if it is missing or outside the bounds.
+func parseBoundedInt(value string, defaultValue int, minValue int, maxValue int) int {
+	parsed, err := strconv.Atoi(value)
+	if err != nil || parsed < minValue || parsed > maxValue {
+		return defaultValue
+	}
+	return parsed
+}
+
+// Get the game session with the given ID, if it is still alive.
+func (master *GameMaster) sessionByID(gameID string) (*GameSession, bool) {
+	master.gameMapMutex.RLock()
+	defer master.gameMapMutex.RUnlock()
+	session, ok := master.gameMap[gameID]
+	return session, ok
+}
+
+// Count the games created from an IP address.
+//
+// The caller must hold the gameMapMutex.
+func (master *GameMaster) countGamesFromIP(ip string) int {
+	count := 0
+	for _, session := range master.gameMap {
+		if session.creatorIP == ip {
+			count += 1
+		}
+	}
+	return count
+}
+
 // --------------------------------------------------------------------------------
 // Routing Functions
 // --------------------------------------------------------------------------------
 
-// http handler to create a new game. Game is added atomically to the game map, and hence is accessible from /{gameID}/*.
-func (master *GameMaster) newGame(w http.ResponseWriter, r *http.Request) {
+// Create a new game session with the creator as the oracle of the first round, unless they chose to play against the computer oracle.
+// The session is added atomically to the game map, and hence is accessible from /{gameID}/*.
+//
+// Returns the capacity limit that was reached instead if the session could not be created.
+func (master *GameMaster) createSession(creator playerIdentity, creatorIP string, config gameSessionConfig) (*GameSession, *capacityRejection) {
 	var gameID string
 
 	// Lock the entire gameMapMutex until we are finished making the game, to avoid the (slim) chance we generate the same ID twice.
+	// This also ensures the capacity limits are checked atomically.
 	master.gameMapMutex.Lock()
+	if limitReached(master.limits.MaxGames, len(master.gameMap)) {
+		master.gameMapMutex.Unlock()
+		return nil, &rejection_ServerFull
+	}
+	if limitReached(master.limits.MaxGamesPerIP, master.countGamesFromIP(creatorIP)) {
+		master.gameMapMutex.Unlock()
+		return nil, &rejection_TooManyGamesFromIP
+	}
+
 	for {
 
 		gameID = master.randomString(gameIDLength)
@@ -95,48 +319,71 @@ func (master *GameMaster) newGame(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	// Make a placeholder item so we can overwrite it later, but unlock the mutex ASAP.
-	master.gameMap[gameID] = &GameData{}
+	guesserJWTKey := randomKey(64)
+	session := newGameSession(master, gameID, creator, config, guesserJWTKey)
+	session.creatorIP = creatorIP
+
+	// Delete the game after a set duration, unless an admin extends it.
+	session.expiresAt = session.createdAt.Add(gameDuration)
+	session.expiryTimer = time.AfterFunc(gameDuration, func() {
+		master.deleteSession(session)
+	})
+	master.gameMap[gameID] = session
 	master.gameMapMutex.Unlock()
 
-	oracleJWTKey := []byte(master.randomString(64))
-	oracleJWTExpiry := time.Now().Add(gameDuration)
-	oracleJWTClaims := &jwt.RegisteredClaims{
-		Issuer:    gameID,
-		Subject:   "oracle",
-		ExpiresAt: jwt.NewNumericDate(oracleJWTExpiry),
-	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS512, oracleJWTClaims)
-	oracleJWTTokenString, _ := token.SignedString(oracleJWTKey)
-
-	data := newGameData(gameID, oracleJWTKey, master.htmlSanitizer)
-	master.gameMap[gameID] = data
-
-	// Start a goroutine to delete the game after a set duration.
-	go func() {
-		<-time.After(gameDuration)
-		log.Info().Str("GameID", gameID).Msg("Deleting Game")
-		master.gameMapMutex.Lock()
-		delete(master.gameMap, gameID)
+	log.Info().Str("NewGameID", gameID).Bool("Public", session.config.isPublic).Msg("New Game Created")
+	session.notifyListingChange()
+	session.publishGameCreated()
+	return session, nil
+}
+
+// Remove a session from the game map, archiving it and disconnecting all clients.
+// Deleting a session that was already deleted has no effect.
+func (master *GameMaster) deleteSession(session *GameSession) {
+	master.gameMapMutex.Lock()
+	if master.gameMap[session.gameID] != session {
 		master.gameMapMutex.Unlock()
-	}()
-
-	log.Info().Str("NewGameID", gameID).Msg("New Game Created")
-	http.SetCookie(w, &http.Cookie{
-		Name:     gameID,
-		Value:    oracleJWTTokenString,
-		Expires:  oracleJWTExpiry,
-		HttpOnly: true,
-	})
-	http.Redirect(w, r, fmt.Sprintf("/game/%s/", data.gameID), http.StatusPermanentRedirect)
+		return
+	}
+	delete(master.gameMap, session.gameID)
+	master.gameMapMutex.Unlock()
+
+	log.Info().Str("GameID", session.gameID).Msg("Deleting Game")
+	session.expiryTimer.Stop()
+	session.saveToArchive()
+	session.sessionCleanup()
+	session.notifyListingChange()
+	master.webhooks.RemoveGame(session.gameID)
+}
+
+// http handler to create a new game, redirecting the creator to it.
+//
+// The creating player is the oracle of the first round, unless they chose to play against the computer oracle.
+func (master *GameMaster) newGame(w http.ResponseWriter, r *http.Request) {
+	r.ParseForm()
+	config, err := parseGameSessionConfig(r.Form, master.Categories())
+	if err != nil {
+		log.Debug().Err(err).Msg("Invalid New Game Options")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	session, rejection := master.createSession(playerFromRequest(r), requestIP(r), config)
+	if rejection != nil {
+		writeCapacityRejection(w, r, *rejection)
+		return
+	}
+
+	if session.hasPassphrase() {
+		// The creator does not need to enter their own passphrase.
+		session.issueGuesserSession(w, playerFromRequest(r))
+	}
+	http.Redirect(w, r, fmt.Sprintf("/game/%s/", session.gameID), http.StatusPermanentRedirect)
 }
 
 // http handler to forward requests to a specific game -- or 404 if the gameID is not in the map.
 func (master *GameMaster) handleGame(w http.ResponseWriter, r *http.Request) {
-	gameIDParam := chi.URLParam(r, "gameID")
-	master.gameMapMutex.RLock()
-	targetGameData, ok := master.gameMap[gameIDParam]
-	master.gameMapMutex.RUnlock()
+	targetSession, ok := master.sessionByID(chi.URLParam(r, "gameID"))
 
 	// If the requested GameID does not exist, return a 404
 	if !ok {
@@ -144,5 +391,5 @@ func (master *GameMaster) handleGame(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	targetGameData.router.ServeHTTP(w, r)
+	targetSession.router.ServeHTTP(w, r)
 }
@@ -0,0 +1,163 @@
+package game
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestLongResponsesAreCut(t *testing.T) {
+	server := newTestServer(t)
+	oracle := server.newPlayer(t)
+	guesser := server.newPlayer(t)
+	gameID := oracle.createGame(url.Values{})
+	guesser.join(gameID)
+
+	long := func(character string) string { return strings.Repeat(character, responseMaxLength+50) }
+	steps := []struct {
+		player *testPlayer
+		route  string
+		value  string
+	}{
+		{oracle, "giveHint", long("h")},
+		{guesser, "submitResponse", long("q")},
+		{oracle, "submitResponse", long("a")},
+	}
+	for _, step := range steps {
+		if status, body := step.player.post("/game/"+gameID+"/"+step.route, url.Values{"response": {step.value}}); status != http.StatusOK {
+			t.Fatalf("%s responded %d: %s", step.route, status, body)
+		}
+	}
+
+	entries := guesser.state(gameID).Round.Entries
+	if len(entries) != 2 {
+		t.Fatalf("entries = %+v, want the hint and the question", entries)
+	}
+	want := map[string]string{
+		"hint":     strings.Repeat("h", responseMaxLength),
+		"question": strings.Repeat("q", responseMaxLength),
+		"answer":   strings.Repeat("a", responseMaxLength),
+	}
+	got := map[string]string{"hint": entries[0].Hint, "question": entries[1].Question, "answer": entries[1].Answer}
+	for name, value := range want {
+		if got[name] != value {
+			t.Errorf("%s has %d runes, want %d", name, len([]rune(got[name])), responseMaxLength)
+		}
+	}
+}
+
+func TestNotificationsAreSentAfterUnlock(t *testing.T) {
+	server := newTestServer(t)
+	oracle := server.newPlayer(t)
+	gameID := oracle.createGame(url.Values{})
+	session, _ := server.master.sessionByID(gameID)
+	data := session.currentRound()
+
+	// Notifications are only sent once the lock is free for other requests.
+	sent := 0
+	data.gameStateMutex.Lock()
+	for range 2 {
+		data.queueNotification(func() {
+			if !data.gameStateMutex.TryLock() {
+				t.Error("notification sent while the gameStateMutex was held")
+				return
+			}
+			data.gameStateMutex.Unlock()
+			sent += 1
+		})
+	}
+	data.unlockGameState()
+	if sent != 2 {
+		t.Errorf("%d notifications sent, want 2", sent)
+	}
+	if len(data.pendingNotifications) != 0 {
+		t.Errorf("%d notifications still pending", len(data.pendingNotifications))
+	}
+}
+
+func TestHintCost(t *testing.T) {
+	testCases := []struct {
+		name            string
+		questionBudget  string
+		hintCost        string
+		questionsBefore int
+		wantCost        int
+		wantRemaining   int
+	}{
+		{"default cost", "", "", 0, hintCostDefault, questionBudgetDefault - hintCostDefault},
+		{"free hint", "5", "0", 1, 0, 4},
+		{"full cost", "5", "3", 1, 3, 1},
+		{"capped at questions remaining", "5", "3", 3, 2, 0},
+		{"no questions remaining", "2", "3", 2, 0, 0},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			server := newTestServer(t)
+			oracle := server.newPlayer(t)
+			guesser := server.newPlayer(t)
+			gameID := oracle.createGame(url.Values{"questionBudget": {testCase.questionBudget}, "hintCost": {testCase.hintCost}})
+			guesser.join(gameID)
+			for range testCase.questionsBefore {
+				guesser.mustPost(gameID, "submitResponse", url.Values{"response": {"Is it an animal?"}})
+				oracle.mustPost(gameID, "submitResponse", url.Values{"response": {"No"}})
+			}
+
+			oracle.mustPost(gameID, "giveHint", url.Values{"response": {"It is bigger than a breadbox"}})
+
+			state := guesser.state(gameID)
+			hint := state.Round.Entries[len(state.Round.Entries)-1]
+			if hint.Hint != "It is bigger than a breadbox" || hint.Cost != testCase.wantCost {
+				t.Errorf("hint %q cost %d, want %d", hint.Hint, hint.Cost, testCase.wantCost)
+			}
+			if state.QuestionsRemaining != testCase.wantRemaining {
+				t.Errorf("%d questions remaining, want %d", state.QuestionsRemaining, testCase.wantRemaining)
+			}
+
+			// A hint counts against the budget like a question, so the guessers may not ask once it is used up.
+			status, _ := guesser.post("/game/"+gameID+"/submitResponse", url.Values{"response": {"Is it blue?"}})
+			if asked := status == http.StatusOK; asked != (testCase.wantRemaining > 0) {
+				t.Errorf("question asked = %v with %d questions remaining", asked, testCase.wantRemaining)
+			}
+		})
+	}
+}
+
+func TestGiveHint(t *testing.T) {
+	server := newTestServer(t)
+	oracle := server.newPlayer(t)
+	guesser := server.newPlayer(t)
+	gameID := oracle.createGame(url.Values{})
+	guesser.join(gameID)
+
+	hint := url.Values{"response": {"It is bigger than a breadbox"}}
+	steps := []struct {
+		name       string
+		player     *testPlayer
+		route      string
+		form       url.Values
+		wantStatus int
+	}{
+		{"guesser gives hint", guesser, "giveHint", hint, http.StatusUnauthorized},
+		{"empty hint", oracle, "giveHint", url.Values{"response": {""}}, http.StatusBadRequest},
+		{"question asked", guesser, "submitResponse", url.Values{"response": {"Is it an animal?"}}, http.StatusOK},
+		{"hint while awaiting answer", oracle, "giveHint", hint, http.StatusBadRequest},
+		{"question answered", oracle, "submitResponse", url.Values{"response": {"No"}}, http.StatusOK},
+		{"hint", oracle, "giveHint", hint, http.StatusOK},
+	}
+
+	for _, step := range steps {
+		status, body := step.player.post("/game/"+gameID+"/"+step.route, step.form)
+		if status != step.wantStatus {
+			t.Fatalf("%s: responded %d, want %d: %s", step.name, status, step.wantStatus, body)
+		}
+	}
+
+	// Hints are not numbered, so the next question keeps the numbering of the questions.
+	guesser.mustPost(gameID, "submitResponse", url.Values{"response": {"Is it a plant?"}})
+	entries := guesser.state(gameID).Round.Entries
+	if len(entries) != 3 || entries[1].Index != 0 || entries[2].Index != 2 {
+		t.Errorf("entries = %+v, want an unnumbered hint between questions 1 and 2", entries)
+	}
+}
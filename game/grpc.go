@@ -0,0 +1,418 @@
+package game
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/hmcalister/twentyquestions/rpc"
+	"github.com/hmcalister/twentyquestions/transcript"
+)
+
+const (
+	// Metadata key holding the player identity JWT -- the same token as the player cookie. Also sent back as a header when issued.
+	grpcPlayerMetadataKey string = "player"
+
+	// Metadata key holding the guesser session JWT, proving the player has entered the passphrase of a protected game.
+	grpcGameTokenMetadataKey string = "game-token"
+)
+
+// Server for the TwentyQuestions gRPC service. Plays the same games as the web routes, with the same player identities.
+type grpcServer struct {
+	rpc.UnimplementedTwentyQuestionsServer
+
+	master *GameMaster
+}
+
+// Register the TwentyQuestions gRPC service on the server, backed by the games of this game master.
+func (master *GameMaster) RegisterGRPC(server *grpc.Server) {
+	rpc.RegisterTwentyQuestionsServer(server, &grpcServer{master: master})
+}
+
+// --------------------------------------------------------------------------------
+// Authentication
+// --------------------------------------------------------------------------------
+
+// Identify the calling player from the metadata, creating a new identity if they do not have a valid one.
+// Returns true if the identity is new, in which case it must be sent back with sendIdentity.
+func (server *grpcServer) identify(ctx context.Context) (playerIdentity, bool) {
+	for _, tokenString := range metadata.ValueFromIncomingContext(ctx, grpcPlayerMetadataKey) {
+		if player, ok := server.master.playerIdentifier.identifyToken(tokenString); ok {
+			return player, false
+		}
+	}
+
+	player := server.master.newPlayerIdentity()
+	log.Debug().Str("PlayerID", player.ID).Msg("New Player Identity Issued Over gRPC")
+	return player, true
+}
+
+// Send a new player JWT for the identity back to the caller in the response header.
+func (server *grpcServer) sendIdentity(ctx context.Context, player playerIdentity) error {
+	tokenString, _, err := server.master.playerIdentifier.sign(player)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to sign player JWT")
+		return status.Error(codes.Internal, "failed to issue player identity")
+	}
+	return grpc.SetHeader(ctx, metadata.Pairs(grpcPlayerMetadataKey, tokenString))
+}
+
+// Identify the calling player, renaming them if a name is given, and send the identity back if it changed.
+func (server *grpcServer) identifyAndRename(ctx context.Context, name string) (playerIdentity, error) {
+	player, issued := server.identify(ctx)
+	if name != "" {
+		player.Name = normalizePlayerName(name, player.ID)
+		issued = true
+	}
+	if issued {
+		return player, server.sendIdentity(ctx, player)
+	}
+	return player, nil
+}
+
+// Check the metadata for a guesser session JWT of the session, proving the player has entered the passphrase.
+func (server *grpcServer) admitted(ctx context.Context, session *GameSession, player playerIdentity) bool {
+	for _, tokenString := range metadata.ValueFromIncomingContext(ctx, grpcGameTokenMetadataKey) {
+		if session.checkGuesserToken(tokenString, player) {
+			return true
+		}
+	}
+	return false
+}
+
+// Find the session for a call and add the calling player to it -- the same as any request to the web routes of a game.
+// Returns a status error if the game does not exist, or the player has not entered the passphrase of a protected game.
+func (server *grpcServer) joinedSession(ctx context.Context, gameID string) (*GameSession, playerIdentity, error) {
+	session, ok := server.master.sessionByID(gameID)
+	if !ok {
+		return nil, playerIdentity{}, status.Error(codes.NotFound, "game does not exist")
+	}
+
+	player, err := server.identifyAndRename(ctx, "")
+	if err != nil {
+		return nil, playerIdentity{}, err
+	}
+	if session.hasPassphrase() && !server.admitted(ctx, session, player) {
+		return nil, playerIdentity{}, status.Error(codes.Unauthenticated, "passphrase required -- join the game first")
+	}
+
+	session.join(player)
+	return session, player, nil
+}
+
+// Get the IP address of the caller, without the port.
+func grpcPeerIP(ctx context.Context) string {
+	callerPeer, ok := peer.FromContext(ctx)
+	if !ok {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(callerPeer.Addr.String())
+	if err != nil {
+		return callerPeer.Addr.String()
+	}
+	return host
+}
+
+// Turn away a call due to a capacity limit.
+func grpcCapacityRejection(ctx context.Context, rejection capacityRejection) error {
+	capacityRejections.Add(rejection.Code, 1)
+	log.Info().Str("Rejection", rejection.Code).Str("RemoteIP", grpcPeerIP(ctx)).Msg("gRPC Call Rejected Due To Capacity")
+	return status.Error(codes.ResourceExhausted, rejection.Message)
+}
+
+// --------------------------------------------------------------------------------
+// Game State
+// --------------------------------------------------------------------------------
+
+// Encode the options of a new game as the new game form, so games created over gRPC are checked the same as the web.
+func createGameForm(request *rpc.CreateGameRequest) url.Values {
+	form := url.Values{}
+	setIf := func(condition bool, key string, value string) {
+		if condition {
+			form.Set(key, value)
+		}
+	}
+	setIf(request.Title != "", "title", request.Title)
+	setIf(request.Category != "", "category", request.Category)
+	setIf(request.Public, "public", "on")
+	setIf(request.Rotation != "", "rotation", request.Rotation)
+	setIf(request.QuestionBudget != 0, "questionBudget", strconv.Itoa(int(request.QuestionBudget)))
+	setIf(request.HintCost != 0, "hintCost", strconv.Itoa(int(request.HintCost)))
+	setIf(request.Voting, "voting", "on")
+	setIf(request.VotingWindowSeconds != 0, "votingWindow", strconv.Itoa(int(request.VotingWindowSeconds)))
+	setIf(request.Passphrase != "", "passphrase", request.Passphrase)
+	setIf(request.PickSecret, "pickSecret", "on")
+	setIf(request.Difficulty != "", "difficulty", request.Difficulty)
+	setIf(request.ComputerOracle, "computerOracle", "on")
+	return form
+}
+
+// Convert a time to a timestamp, leaving zero times unset.
+func grpcTimestamp(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
+
+// Snapshot the current round of the session as the player may see it -- the secret is only included for the oracle
+// until the round is over, the same as on the web.
+func (session *GameSession) grpcState(player playerIdentity) *rpc.GameState {
+	state := session.state(player)
+	grpcState := &rpc.GameState{
+		GameId:   state.GameID,
+		Title:    state.Title,
+		Category: state.Category,
+		Players:  make([]*rpc.Player, 0, len(state.Players)),
+		Round: &rpc.Round{
+			Number:             int32(state.Round.Number),
+			OracleName:         state.Round.OracleName,
+			Status:             rpc.RoundStatus_ROUND_STATUS_AWAITING_QUESTION,
+			QuestionsRemaining: int32(state.QuestionsRemaining),
+			Secret:             state.Round.Secret,
+			WinnerName:         state.Round.WinnerName,
+			StartedAt:          grpcTimestamp(state.Round.StartedAt),
+			Entries:            make([]*rpc.Entry, 0, len(state.Round.Entries)),
+		},
+	}
+
+	for _, statePlayer := range state.Players {
+		grpcState.Players = append(grpcState.Players, &rpc.Player{
+			Name:     statePlayer.Name,
+			Score:    int32(statePlayer.Score),
+			IsOracle: statePlayer.IsOracle,
+			IsYou:    statePlayer.IsYou,
+		})
+	}
+
+	for _, entry := range state.Round.Entries {
+		grpcEntry := &rpc.Entry{
+			Kind:      rpc.Entry_KIND_QUESTION,
+			Index:     int32(entry.Index),
+			Cost:      int32(entry.Cost),
+			AskerName: entry.AskerName,
+			Question:  entry.Question,
+			Answer:    entry.Answer,
+			Hint:      entry.Hint,
+			Retracted: entry.Retracted,
+			AskedAt:   grpcTimestamp(entry.AskedAt),
+		}
+		if entry.Kind == transcript.EntryKind_Hint {
+			grpcEntry.Kind = rpc.Entry_KIND_HINT
+		}
+		if entry.AnsweredAt != nil {
+			grpcEntry.AnsweredAt = grpcTimestamp(*entry.AnsweredAt)
+		}
+		grpcState.Round.Entries = append(grpcState.Round.Entries, grpcEntry)
+	}
+
+	switch state.Status {
+	case roundStatus_AwaitingAnswer:
+		grpcState.Round.Status = rpc.RoundStatus_ROUND_STATUS_AWAITING_ANSWER
+	case roundStatus_Over:
+		grpcState.Round.Status = rpc.RoundStatus_ROUND_STATUS_OVER
+	}
+	switch state.Round.Outcome {
+	case transcript.Outcome_Correct:
+		grpcState.Round.Outcome = rpc.Outcome_OUTCOME_CORRECT
+	case transcript.Outcome_Incorrect:
+		grpcState.Round.Outcome = rpc.Outcome_OUTCOME_INCORRECT
+	case transcript.Outcome_Ended:
+		grpcState.Round.Outcome = rpc.Outcome_OUTCOME_ENDED
+	}
+	if state.Round.EndedAt != nil {
+		grpcState.Round.EndedAt = grpcTimestamp(*state.Round.EndedAt)
+	}
+	return grpcState
+}
+
+// --------------------------------------------------------------------------------
+// RPCs
+// --------------------------------------------------------------------------------
+
+// Create a new game with the calling player as the oracle of the first round, unless they chose to play against the computer oracle.
+func (server *grpcServer) CreateGame(ctx context.Context, request *rpc.CreateGameRequest) (*rpc.CreateGameResponse, error) {
+	config, err := parseGameSessionConfig(createGameForm(request), server.master.Categories())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	player, err := server.identifyAndRename(ctx, request.PlayerName)
+	if err != nil {
+		return nil, err
+	}
+
+	session, rejection := server.master.createSession(player, grpcPeerIP(ctx), config)
+	if rejection != nil {
+		return nil, grpcCapacityRejection(ctx, *rejection)
+	}
+
+	response := &rpc.CreateGameResponse{
+		GameId: session.gameID,
+		Path:   fmt.Sprintf("/game/%s/", session.gameID),
+		State:  session.grpcState(player),
+	}
+	if session.hasPassphrase() {
+		// The creator does not need to enter their own passphrase.
+		response.GameToken, _, err = session.signGuesserToken(player)
+		if err != nil {
+			return nil, status.Error(codes.Internal, "failed to issue game token")
+		}
+	}
+	return response, nil
+}
+
+// Join a game, checking the passphrase if the game is protected and the player has not already entered it.
+func (server *grpcServer) JoinGame(ctx context.Context, request *rpc.JoinGameRequest) (*rpc.JoinGameResponse, error) {
+	session, ok := server.master.sessionByID(request.GameId)
+	if !ok {
+		return nil, status.Error(codes.NotFound, "game does not exist")
+	}
+
+	player, err := server.identifyAndRename(ctx, request.PlayerName)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &rpc.JoinGameResponse{}
+	if session.hasPassphrase() {
+		if !server.admitted(ctx, session, player) && !session.checkPassphrase(request.Passphrase) {
+			log.Debug().Str("GameID", session.gameID).Msg("Incorrect Passphrase")
+			return nil, status.Error(codes.Unauthenticated, "incorrect passphrase")
+		}
+		response.GameToken, _, err = session.signGuesserToken(player)
+		if err != nil {
+			return nil, status.Error(codes.Internal, "failed to issue game token")
+		}
+	}
+
+	session.join(player)
+	response.State = session.grpcState(player)
+	return response, nil
+}
+
+// Ask a question as a guesser.
+func (server *grpcServer) Ask(ctx context.Context, request *rpc.AskRequest) (*rpc.GameState, error) {
+	session, player, err := server.joinedSession(ctx, request.GameId)
+	if err != nil {
+		return nil, err
+	}
+	if request.Question == "" {
+		return nil, status.Error(codes.InvalidArgument, "question must not be empty")
+	}
+
+	round := session.currentRound()
+	if round.oracleID == player.ID {
+		return nil, status.Error(codes.PermissionDenied, "the oracle may not ask questions")
+	}
+	err = round.ask(player, truncateRunes(request.Question, responseMaxLength))
+	if err != nil {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+	return session.grpcState(player), nil
+}
+
+// Answer the pending question as the oracle.
+func (server *grpcServer) Answer(ctx context.Context, request *rpc.AnswerRequest) (*rpc.GameState, error) {
+	session, player, err := server.joinedSession(ctx, request.GameId)
+	if err != nil {
+		return nil, err
+	}
+	if request.Answer == "" {
+		return nil, status.Error(codes.InvalidArgument, "answer must not be empty")
+	}
+
+	round := session.currentRound()
+	if round.oracleID != player.ID {
+		return nil, status.Error(codes.PermissionDenied, "only the oracle may answer")
+	}
+	err = round.answer(truncateRunes(request.Answer, responseMaxLength))
+	if err != nil {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+	return session.grpcState(player), nil
+}
+
+// End the round with a verdict as the oracle.
+func (server *grpcServer) Verdict(ctx context.Context, request *rpc.VerdictRequest) (*rpc.GameState, error) {
+	session, player, err := server.joinedSession(ctx, request.GameId)
+	if err != nil {
+		return nil, err
+	}
+
+	round := session.currentRound()
+	if round.oracleID != player.ID {
+		return nil, status.Error(codes.PermissionDenied, "only the oracle may give a verdict")
+	}
+	err = round.giveVerdict(request.Correct)
+	if err != nil {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+	return session.grpcState(player), nil
+}
+
+// Send the state of the game on connection, then again whenever it changes, until the caller leaves or the game is deleted.
+//
+// The stream is registered with the same broadcaster as the SSE clients of the game, and so counts towards its connection limit.
+func (server *grpcServer) WatchGame(request *rpc.WatchGameRequest, stream rpc.TwentyQuestions_WatchGameServer) error {
+	session, player, err := server.joinedSession(stream.Context(), request.GameId)
+	if err != nil {
+		return err
+	}
+
+	watcher, err := session.broadcaster.subscribe(stream.Context())
+	if err == errBroadcasterFull {
+		return grpcCapacityRejection(stream.Context(), rejection_GameFull)
+	}
+	if err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	defer watcher.cancelFunc()
+	disconnect := session.connect(player.ID)
+	defer disconnect()
+
+	err = stream.Send(session.grpcState(player))
+	if err != nil {
+		return err
+	}
+	for {
+		select {
+		case <-watcher.context.Done():
+			if stream.Context().Err() != nil {
+				return nil
+			}
+			// The broadcaster was closed without the caller leaving, so the game has been deleted.
+			return status.Error(codes.NotFound, "game has ended")
+		case event := <-watcher.eventsChannel:
+			// Chat and the maintenance notice are not part of the game state.
+			if event.name == "chat" || event.name == "notice" {
+				continue
+			}
+
+			// A single change is often several events, e.g. the responses, scoreboard and controls at the end of a round,
+			// so any events already waiting are taken before sending the state once.
+			for pending := true; pending; {
+				select {
+				case <-watcher.eventsChannel:
+				default:
+					pending = false
+				}
+			}
+			err = stream.Send(session.grpcState(player))
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
@@ -0,0 +1,393 @@
+package game
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/hmcalister/twentyquestions/rpc"
+)
+
+// Serve the gRPC service of the test server over an in-memory connection, returning a client of it.
+func (server *testServer) newGRPCClient(t *testing.T) rpc.TwentyQuestionsClient {
+	t.Helper()
+	listener := bufconn.Listen(1024 * 1024)
+	grpcServer := grpc.NewServer()
+	server.master.RegisterGRPC(grpcServer)
+	go grpcServer.Serve(listener)
+	t.Cleanup(grpcServer.Stop)
+
+	connection, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("failed to create gRPC client: %v", err)
+	}
+	t.Cleanup(func() { connection.Close() })
+	return rpc.NewTwentyQuestionsClient(connection)
+}
+
+// A player of the gRPC service, keeping the identity the server issues them and the game token of the last game they joined.
+type grpcTestPlayer struct {
+	t           *testing.T
+	client      rpc.TwentyQuestionsClient
+	playerToken string
+	gameToken   string
+}
+
+func newGRPCTestPlayer(t *testing.T, client rpc.TwentyQuestionsClient) *grpcTestPlayer {
+	return &grpcTestPlayer{t: t, client: client}
+}
+
+// Create a context carrying the identity and game token of the player as metadata.
+func (player *grpcTestPlayer) context() context.Context {
+	md := metadata.MD{}
+	if player.playerToken != "" {
+		md.Set(grpcPlayerMetadataKey, player.playerToken)
+	}
+	if player.gameToken != "" {
+		md.Set(grpcGameTokenMetadataKey, player.gameToken)
+	}
+	return metadata.NewOutgoingContext(context.Background(), md)
+}
+
+// Keep the identity sent back in a response header, if any.
+func (player *grpcTestPlayer) keepIdentity(header metadata.MD) {
+	if tokens := header.Get(grpcPlayerMetadataKey); len(tokens) > 0 {
+		player.playerToken = tokens[0]
+	}
+}
+
+func (player *grpcTestPlayer) createGame(request *rpc.CreateGameRequest) (*rpc.CreateGameResponse, error) {
+	var header metadata.MD
+	response, err := player.client.CreateGame(player.context(), request, grpc.Header(&header))
+	player.keepIdentity(header)
+	if err == nil {
+		player.gameToken = response.GameToken
+	}
+	return response, err
+}
+
+func (player *grpcTestPlayer) joinGame(request *rpc.JoinGameRequest) (*rpc.JoinGameResponse, error) {
+	var header metadata.MD
+	response, err := player.client.JoinGame(player.context(), request, grpc.Header(&header))
+	player.keepIdentity(header)
+	if err == nil {
+		player.gameToken = response.GameToken
+	}
+	return response, err
+}
+
+// Create a game, failing the test if it cannot be created.
+func (player *grpcTestPlayer) mustCreateGame(request *rpc.CreateGameRequest) string {
+	player.t.Helper()
+	response, err := player.createGame(request)
+	if err != nil {
+		player.t.Fatalf("failed to create game: %v", err)
+	}
+	return response.GameId
+}
+
+// Join a game, failing the test if it cannot be joined.
+func (player *grpcTestPlayer) mustJoinGame(gameID string, passphrase string) {
+	player.t.Helper()
+	if _, err := player.joinGame(&rpc.JoinGameRequest{GameId: gameID, Passphrase: passphrase}); err != nil {
+		player.t.Fatalf("failed to join game: %v", err)
+	}
+}
+
+func TestGRPCCreateGame(t *testing.T) {
+	server := newTestServer(t)
+	client := server.newGRPCClient(t)
+
+	testCases := []struct {
+		name          string
+		request       *rpc.CreateGameRequest
+		wantCode      codes.Code
+		wantGameToken bool
+		wantName      string
+	}{
+		{"default options", &rpc.CreateGameRequest{}, codes.OK, false, ""},
+		{"title and name", &rpc.CreateGameRequest{Title: "Friday Game", PlayerName: "Olive"}, codes.OK, false, "Olive"},
+		{"passphrase", &rpc.CreateGameRequest{Passphrase: "open sesame"}, codes.OK, true, ""},
+		{"invalid difficulty", &rpc.CreateGameRequest{PickSecret: true, Difficulty: "impossible"}, codes.InvalidArgument, false, ""},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			player := newGRPCTestPlayer(t, client)
+			response, err := player.createGame(testCase.request)
+			if status.Code(err) != testCase.wantCode {
+				t.Fatalf("error = %v, want code %s", err, testCase.wantCode)
+			}
+			if err != nil {
+				return
+			}
+
+			if player.playerToken == "" {
+				t.Error("no player identity sent back to a new player")
+			}
+			if (response.GameToken != "") != testCase.wantGameToken {
+				t.Errorf("game token = %q, want token %v", response.GameToken, testCase.wantGameToken)
+			}
+			if response.Path != "/game/"+response.GameId+"/" {
+				t.Errorf("path = %q, want the game page", response.Path)
+			}
+
+			state := response.State
+			if len(state.Players) != 1 || !state.Players[0].IsYou || !state.Players[0].IsOracle {
+				t.Fatalf("players = %+v, want the creator as oracle", state.Players)
+			}
+			if testCase.wantName != "" && state.Players[0].Name != testCase.wantName {
+				t.Errorf("creator named %q, want %q", state.Players[0].Name, testCase.wantName)
+			}
+			if state.Round.Status != rpc.RoundStatus_ROUND_STATUS_AWAITING_QUESTION || state.Round.QuestionsRemaining != int32(questionBudgetDefault) {
+				t.Errorf("round %+v, want a new round awaiting a question", state.Round)
+			}
+			if state.Title != testCase.request.Title && testCase.request.Title != "" {
+				t.Errorf("title = %q, want %q", state.Title, testCase.request.Title)
+			}
+
+			// The creator may play their own protected game with the token they were given.
+			session, _ := server.master.sessionByID(response.GameId)
+			identity, ok := server.master.playerIdentifier.identifyToken(player.playerToken)
+			if !ok || session.currentRound().oracleID != identity.ID {
+				t.Errorf("player token identifies %+v, want the oracle", identity)
+			}
+			if testCase.wantGameToken && !session.checkGuesserToken(response.GameToken, identity) {
+				t.Error("game token not valid for the creator")
+			}
+		})
+	}
+}
+
+func TestGRPCJoinGame(t *testing.T) {
+	server := newTestServer(t)
+	client := server.newGRPCClient(t)
+	oracle := newGRPCTestPlayer(t, client)
+	gameID := oracle.mustCreateGame(&rpc.CreateGameRequest{Passphrase: "open sesame"})
+	openGameID := oracle.mustCreateGame(&rpc.CreateGameRequest{})
+
+	// A player who entered the passphrase earlier, and so holds a game token.
+	admitted := newGRPCTestPlayer(t, client)
+	admitted.mustJoinGame(gameID, "open sesame")
+
+	testCases := []struct {
+		name string
+
+		// Sets up the player making the call.
+		player        func() *grpcTestPlayer
+		request       *rpc.JoinGameRequest
+		wantCode      codes.Code
+		wantGameToken bool
+	}{
+		{"missing game", func() *grpcTestPlayer { return newGRPCTestPlayer(t, client) },
+			&rpc.JoinGameRequest{GameId: "missing"}, codes.NotFound, false},
+		{"open game", func() *grpcTestPlayer { return newGRPCTestPlayer(t, client) },
+			&rpc.JoinGameRequest{GameId: openGameID, PlayerName: "Alice"}, codes.OK, false},
+		{"no passphrase", func() *grpcTestPlayer { return newGRPCTestPlayer(t, client) },
+			&rpc.JoinGameRequest{GameId: gameID}, codes.Unauthenticated, false},
+		{"wrong passphrase", func() *grpcTestPlayer { return newGRPCTestPlayer(t, client) },
+			&rpc.JoinGameRequest{GameId: gameID, Passphrase: "wrong"}, codes.Unauthenticated, false},
+		{"correct passphrase", func() *grpcTestPlayer { return newGRPCTestPlayer(t, client) },
+			&rpc.JoinGameRequest{GameId: gameID, Passphrase: "open sesame"}, codes.OK, true},
+		{"game token", func() *grpcTestPlayer { return admitted },
+			&rpc.JoinGameRequest{GameId: gameID}, codes.OK, true},
+		{"game token of another player", func() *grpcTestPlayer {
+			thief := newGRPCTestPlayer(t, client)
+			thief.gameToken = admitted.gameToken
+			return thief
+		}, &rpc.JoinGameRequest{GameId: gameID}, codes.Unauthenticated, false},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			player := testCase.player()
+			response, err := player.joinGame(testCase.request)
+			if status.Code(err) != testCase.wantCode {
+				t.Fatalf("error = %v, want code %s", err, testCase.wantCode)
+			}
+			if err != nil {
+				return
+			}
+			if (response.GameToken != "") != testCase.wantGameToken {
+				t.Errorf("game token = %q, want token %v", response.GameToken, testCase.wantGameToken)
+			}
+
+			var you *rpc.Player
+			for _, statePlayer := range response.State.Players {
+				if statePlayer.IsYou {
+					you = statePlayer
+				}
+			}
+			if you == nil || you.IsOracle {
+				t.Fatalf("players = %+v, want the caller as a guesser", response.State.Players)
+			}
+			if testCase.request.PlayerName != "" && you.Name != testCase.request.PlayerName {
+				t.Errorf("caller named %q, want %q", you.Name, testCase.request.PlayerName)
+			}
+		})
+	}
+
+	// Other calls to a protected game need the game token too.
+	outsider := newGRPCTestPlayer(t, client)
+	if _, err := client.Ask(outsider.context(), &rpc.AskRequest{GameId: gameID, Question: "Is it blue?"}); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("asking without a game token gave %v, want %s", err, codes.Unauthenticated)
+	}
+	if _, err := client.Ask(admitted.context(), &rpc.AskRequest{GameId: gameID, Question: "Is it blue?"}); err != nil {
+		t.Errorf("asking with a game token failed: %v", err)
+	}
+}
+
+func TestGRPCRoleChecks(t *testing.T) {
+	server := newTestServer(t)
+	client := server.newGRPCClient(t)
+	oracle := newGRPCTestPlayer(t, client)
+	guesser := newGRPCTestPlayer(t, client)
+	gameID := oracle.mustCreateGame(&rpc.CreateGameRequest{})
+	guesser.mustJoinGame(gameID, "")
+
+	ask := func(player *grpcTestPlayer, question string) func() (*rpc.GameState, error) {
+		return func() (*rpc.GameState, error) {
+			return client.Ask(player.context(), &rpc.AskRequest{GameId: gameID, Question: question})
+		}
+	}
+	answer := func(player *grpcTestPlayer, answer string) func() (*rpc.GameState, error) {
+		return func() (*rpc.GameState, error) {
+			return client.Answer(player.context(), &rpc.AnswerRequest{GameId: gameID, Answer: answer})
+		}
+	}
+	verdict := func(player *grpcTestPlayer, correct bool) func() (*rpc.GameState, error) {
+		return func() (*rpc.GameState, error) {
+			return client.Verdict(player.context(), &rpc.VerdictRequest{GameId: gameID, Correct: correct})
+		}
+	}
+
+	steps := []struct {
+		name       string
+		call       func() (*rpc.GameState, error)
+		wantCode   codes.Code
+		wantStatus rpc.RoundStatus
+	}{
+		{"oracle asks", ask(oracle, "Is it blue?"), codes.PermissionDenied, 0},
+		{"guesser answers", answer(guesser, "Yes"), codes.PermissionDenied, 0},
+		{"guesser gives verdict", verdict(guesser, true), codes.PermissionDenied, 0},
+		{"empty question", ask(guesser, ""), codes.InvalidArgument, 0},
+		{"answer before question", answer(oracle, "Yes"), codes.FailedPrecondition, 0},
+		{"guesser asks", ask(guesser, "Is it an animal?"), codes.OK, rpc.RoundStatus_ROUND_STATUS_AWAITING_ANSWER},
+		{"question while awaiting answer", ask(guesser, "Is it blue?"), codes.FailedPrecondition, 0},
+		{"empty answer", answer(oracle, ""), codes.InvalidArgument, 0},
+		{"oracle answers", answer(oracle, "Yes"), codes.OK, rpc.RoundStatus_ROUND_STATUS_AWAITING_QUESTION},
+		{"oracle gives verdict", verdict(oracle, true), codes.OK, rpc.RoundStatus_ROUND_STATUS_OVER},
+		{"verdict after round", verdict(oracle, false), codes.FailedPrecondition, 0},
+		{"question after round", ask(guesser, "Is it blue?"), codes.FailedPrecondition, 0},
+	}
+
+	for _, step := range steps {
+		state, err := step.call()
+		if status.Code(err) != step.wantCode {
+			t.Fatalf("%s: error = %v, want code %s", step.name, err, step.wantCode)
+		}
+		if err == nil && state.Round.Status != step.wantStatus {
+			t.Errorf("%s: status %s, want %s", step.name, state.Round.Status, step.wantStatus)
+		}
+	}
+
+	state, err := client.Ask(guesser.context(), &rpc.AskRequest{GameId: "missing", Question: "Is it blue?"})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("asking in a missing game gave %+v, %v, want %s", state, err, codes.NotFound)
+	}
+}
+
+func TestGRPCWatchGame(t *testing.T) {
+	server := newTestServer(t)
+	client := server.newGRPCClient(t)
+	oracle := newGRPCTestPlayer(t, client)
+	guesser := newGRPCTestPlayer(t, client)
+	gameID := oracle.mustCreateGame(&rpc.CreateGameRequest{})
+	guesser.mustJoinGame(gameID, "")
+	session, _ := server.master.sessionByID(gameID)
+
+	ctx, cancel := context.WithTimeout(oracle.context(), 10*time.Second)
+	defer cancel()
+	stream, err := client.WatchGame(ctx, &rpc.WatchGameRequest{GameId: gameID})
+	if err != nil {
+		t.Fatalf("failed to watch game: %v", err)
+	}
+	state, err := stream.Recv()
+	if err != nil {
+		t.Fatalf("failed to receive the state on connection: %v", err)
+	}
+	if state.Round.Status != rpc.RoundStatus_ROUND_STATUS_AWAITING_QUESTION || len(state.Players) != 2 {
+		t.Fatalf("state on connection %+v, want both players awaiting a question", state)
+	}
+
+	// Hold the session while a burst of events is broadcast, so the watcher cannot send the state until the burst is queued.
+	// Chat is not part of the state, and is never sent.
+	const burstSize = 5
+	session.sessionMutex.Lock()
+	session.broadcaster.broadcast(sseEvent{name: "chat", data: "Hello"})
+	for range burstSize {
+		session.broadcaster.broadcast(sseEvent{name: "scoreboard", data: "update"})
+	}
+	session.sessionMutex.Unlock()
+
+	if _, err := client.Ask(guesser.context(), &rpc.AskRequest{GameId: gameID, Question: "Is it an animal?"}); err != nil {
+		t.Fatalf("failed to ask: %v", err)
+	}
+
+	// One state for the first event of the burst, one for the rest, and one for the question -- without coalescing, every event is a state.
+	sent := 0
+	for {
+		state, err := stream.Recv()
+		if err != nil {
+			t.Fatalf("stream failed before the question arrived: %v", err)
+		}
+		sent += 1
+		if state.Round.Status == rpc.RoundStatus_ROUND_STATUS_AWAITING_ANSWER {
+			if entries := state.Round.Entries; len(entries) != 1 || entries[0].Question != "Is it an animal?" {
+				t.Errorf("entries = %+v, want the question", entries)
+			}
+			break
+		}
+	}
+	if sent > 3 {
+		t.Errorf("sent %d states for a burst of %d events and a question, want the burst coalesced", sent, burstSize)
+	}
+
+	// The stream ends once the game is deleted.
+	server.master.deleteSession(session)
+	for {
+		_, err := stream.Recv()
+		if err == nil {
+			continue
+		}
+		if status.Code(err) != codes.NotFound {
+			t.Errorf("stream of a deleted game ended with %v, want %s", err, codes.NotFound)
+		}
+		break
+	}
+}
+
+func TestGRPCWatchMissingGame(t *testing.T) {
+	server := newTestServer(t)
+	client := server.newGRPCClient(t)
+	stream, err := client.WatchGame(context.Background(), &rpc.WatchGameRequest{GameId: "missing"})
+	if err == nil {
+		_, err = stream.Recv()
+	}
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("watching a missing game gave %v, want %s", err, codes.NotFound)
+	}
+}
@@ -0,0 +1,132 @@
+package game
+
+import (
+	"net/http"
+	"runtime/debug"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// --------------------------------------------------------------------------------
+// Readiness Checks
+// --------------------------------------------------------------------------------
+
+// Mark the server as draining, so it reports not ready and load balancers stop sending it new players.
+// Games in progress carry on until the server shuts down.
+func (master *GameMaster) Drain() {
+	master.draining.Store(true)
+	log.Info().Msg("Draining")
+}
+
+// Get the number of games currently alive.
+func (master *GameMaster) ActiveGames() int {
+	master.gameMapMutex.RLock()
+	defer master.gameMapMutex.RUnlock()
+	return len(master.gameMap)
+}
+
+// Run every readiness check, returning the result of each -- "ok", or why the check failed.
+func (master *GameMaster) readinessChecks() (map[string]string, bool) {
+	checks := map[string]string{
+		"wordPacks": "ok",
+		"archive":   "ok",
+		"stats":     "ok",
+		"auditLog":  "ok",
+		"draining":  "ok",
+	}
+	ready := true
+
+	// New games pick their category, and the secret if asked to, from the word packs.
+	if len(master.wordPacks.Categories()) == 0 {
+		checks["wordPacks"] = "no word packs are loaded"
+		ready = false
+	}
+	if err := master.archive.Ping(); err != nil {
+		checks["archive"] = err.Error()
+		ready = false
+	}
+	if err := master.stats.Ping(); err != nil {
+		checks["stats"] = err.Error()
+		ready = false
+	}
+	if err := master.auditLog.Ping(); err != nil {
+		checks["auditLog"] = err.Error()
+		ready = false
+	}
+	if master.draining.Load() {
+		checks["draining"] = "server is draining"
+		ready = false
+	}
+	return checks, ready
+}
+
+// --------------------------------------------------------------------------------
+// Routing Functions
+// --------------------------------------------------------------------------------
+
+// Data to be returned from /readyz
+type readinessResponse struct {
+	Ready  bool              `json:"ready"`
+	Checks map[string]string `json:"checks"`
+}
+
+// Data to be returned from /version
+type versionResponse struct {
+	Path      string `json:"path"`
+	Version   string `json:"version"`
+	GoVersion string `json:"goVersion"`
+
+	// The commit the server was built from, and if there were uncommitted changes -- empty if built outside version control.
+	Revision     string `json:"revision,omitempty"`
+	RevisionTime string `json:"revisionTime,omitempty"`
+	Modified     bool   `json:"modified,omitempty"`
+
+	StartedAt     time.Time `json:"startedAt"`
+	UptimeSeconds int64     `json:"uptimeSeconds"`
+	ActiveGames   int       `json:"activeGames"`
+}
+
+// Report the process is alive -- always succeeds if the server can respond at all.
+func (master *GameMaster) ServeHealth(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// Report if the server is ready for new players, with the result of each check. Responds 503 if any check fails.
+func (master *GameMaster) ServeReadiness(w http.ResponseWriter, r *http.Request) {
+	checks, ready := master.readinessChecks()
+	status := http.StatusOK
+	if !ready {
+		status = http.StatusServiceUnavailable
+	}
+	writeJSON(w, status, readinessResponse{
+		Ready:  ready,
+		Checks: checks,
+	})
+}
+
+// Report the build of the server, how long it has been running, and the number of games alive.
+func (master *GameMaster) ServeVersion(w http.ResponseWriter, r *http.Request) {
+	response := versionResponse{
+		StartedAt:     master.startedAt,
+		UptimeSeconds: int64(time.Since(master.startedAt).Seconds()),
+		ActiveGames:   master.ActiveGames(),
+	}
+
+	if buildInfo, ok := debug.ReadBuildInfo(); ok {
+		response.Path = buildInfo.Main.Path
+		response.Version = buildInfo.Main.Version
+		response.GoVersion = buildInfo.GoVersion
+		for _, setting := range buildInfo.Settings {
+			switch setting.Key {
+			case "vcs.revision":
+				response.Revision = setting.Value
+			case "vcs.time":
+				response.RevisionTime = setting.Value
+			case "vcs.modified":
+				response.Modified = setting.Value == "true"
+			}
+		}
+	}
+	writeJSON(w, http.StatusOK, response)
+}
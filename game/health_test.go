@@ -0,0 +1,81 @@
+package game
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hmcalister/twentyquestions/audit"
+	"github.com/hmcalister/twentyquestions/stats"
+	"github.com/hmcalister/twentyquestions/wordpacks"
+)
+
+func TestReadinessChecks(t *testing.T) {
+	emptyWordPacks, err := wordpacks.Load(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to load empty word packs: %v", err)
+	}
+
+	testCases := []struct {
+		name        string
+		breakServer func(master *GameMaster)
+		failedCheck string
+	}{
+		{"ready", func(master *GameMaster) {}, ""},
+		{"no word packs", func(master *GameMaster) { master.wordPacks = emptyWordPacks }, "wordPacks"},
+		{"audit log not writable", func(master *GameMaster) {
+			// Replace the audit log directory with a file, so the audit log can never be written.
+			auditDir := t.TempDir()
+			master.auditLog = openTestAuditLog(t, filepath.Join(auditDir, "audit.log"))
+			if err := os.RemoveAll(auditDir); err != nil {
+				t.Fatalf("failed to remove audit log directory: %v", err)
+			}
+			if err := os.WriteFile(auditDir, nil, 0644); err != nil {
+				t.Fatalf("failed to create file: %v", err)
+			}
+		}, "auditLog"},
+		{"stats not writable", func(master *GameMaster) {
+			statsDir := t.TempDir()
+			statsTracker, err := stats.OpenTracker(statsDir)
+			if err != nil {
+				t.Fatalf("failed to open stats: %v", err)
+			}
+			master.stats = statsTracker
+			if err := os.RemoveAll(statsDir); err != nil {
+				t.Fatalf("failed to remove stats directory: %v", err)
+			}
+		}, "stats"},
+		{"draining", func(master *GameMaster) { master.Drain() }, "draining"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			master := newTestServer(t).master
+			master.auditLog = openTestAuditLog(t, filepath.Join(t.TempDir(), "audit.log"))
+			testCase.breakServer(master)
+
+			checks, ready := master.readinessChecks()
+			if ready != (testCase.failedCheck == "") {
+				t.Errorf("ready = %v with checks %v", ready, checks)
+			}
+			for name, result := range checks {
+				if name == testCase.failedCheck && result == "ok" {
+					t.Errorf("check %s passed, want it to fail", name)
+				}
+				if name != testCase.failedCheck && result != "ok" {
+					t.Errorf("check %s failed: %s", name, result)
+				}
+			}
+		})
+	}
+}
+
+// Open an audit log, failing the test if it cannot be opened.
+func openTestAuditLog(t *testing.T, filename string) *audit.Log {
+	t.Helper()
+	auditLog, err := audit.Open(filename)
+	if err != nil {
+		t.Fatalf("failed to open audit log: %v", err)
+	}
+	return auditLog
+}
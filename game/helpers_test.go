@@ -0,0 +1,231 @@
+package game
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog"
+
+	"github.com/hmcalister/twentyquestions/archive"
+	"github.com/hmcalister/twentyquestions/knowledge"
+	"github.com/hmcalister/twentyquestions/stats"
+	"github.com/hmcalister/twentyquestions/webhooks"
+	"github.com/hmcalister/twentyquestions/wordpacks"
+)
+
+// Silence the server log, so test failures are not buried in it.
+func TestMain(m *testing.M) {
+	zerolog.SetGlobalLevel(zerolog.Disabled)
+	os.Exit(m.Run())
+}
+
+// A game master served over HTTP, routed the same as the server.
+type testServer struct {
+	*httptest.Server
+	master *GameMaster
+}
+
+// Start a game master with the built in word packs and knowledge base, keeping the archive in memory.
+func newTestServer(t *testing.T) *testServer {
+	t.Helper()
+	return newTestServerWithLimits(t, CapacityLimits{})
+}
+
+// Start a game master as in newTestServer, with the given capacity limits.
+func newTestServerWithLimits(t *testing.T, limits CapacityLimits) *testServer {
+	t.Helper()
+
+	wordPacks, err := wordpacks.Load("../data/wordpacks")
+	if err != nil {
+		t.Fatalf("failed to load word packs: %v", err)
+	}
+	knowledgeBase, err := knowledge.Load("../data/knowledge/base.json")
+	if err != nil {
+		t.Fatalf("failed to load knowledge base: %v", err)
+	}
+
+	master := NewGameMaster(limits, archive.New(archive.NewMemoryStore(), 0), stats.NewTracker(), nil, wordPacks, knowledgeBase, webhooks.NewDispatcher(false), nil)
+	router := chi.NewRouter()
+	router.Mount("/game", master.Router)
+	router.Mount("/lobby", master.LobbyRouter)
+	router.Mount("/api", master.APIRouter)
+	router.Mount("/archive", master.ArchiveRouter)
+	router.Mount("/leaderboard", master.LeaderboardRouter)
+	router.Mount("/admin", master.AdminRouter)
+
+	server := &testServer{
+		Server: httptest.NewServer(router),
+		master: master,
+	}
+	t.Cleanup(server.Close)
+	return server
+}
+
+// A player of the test server, with their own identity cookie.
+type testPlayer struct {
+	t      *testing.T
+	server *testServer
+	client *http.Client
+}
+
+// Create a new player, with an identity issued on their first request.
+func (server *testServer) newPlayer(t *testing.T) *testPlayer {
+	t.Helper()
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		t.Fatalf("failed to create cookie jar: %v", err)
+	}
+	return &testPlayer{
+		t:      t,
+		server: server,
+		client: &http.Client{Jar: jar},
+	}
+}
+
+// Create a new game with the given options, returning its ID.
+func (player *testPlayer) createGame(options url.Values) string {
+	player.t.Helper()
+	response, err := player.client.Get(player.server.URL + "/game/new?" + options.Encode())
+	if err != nil {
+		player.t.Fatalf("failed to create game: %v", err)
+	}
+	response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		player.t.Fatalf("creating game responded %d", response.StatusCode)
+	}
+	return strings.Split(strings.TrimPrefix(response.Request.URL.Path, "/game/"), "/")[0]
+}
+
+// Send a GET request to a path of the server, returning the response status and body.
+func (player *testPlayer) get(path string) (int, string) {
+	player.t.Helper()
+	response, err := player.client.Get(player.server.URL + path)
+	if err != nil {
+		player.t.Fatalf("GET %s failed: %v", path, err)
+	}
+	return readTestResponse(player.t, response)
+}
+
+// Send a GET request to a path of the server accepting only JSON, returning the response status and body.
+func (player *testPlayer) getJSON(path string) (int, string) {
+	player.t.Helper()
+	request, err := http.NewRequest(http.MethodGet, player.server.URL+path, nil)
+	if err != nil {
+		player.t.Fatalf("failed to create GET %s: %v", path, err)
+	}
+	request.Header.Set("Accept", "application/json")
+	response, err := player.client.Do(request)
+	if err != nil {
+		player.t.Fatalf("GET %s failed: %v", path, err)
+	}
+	return readTestResponse(player.t, response)
+}
+
+// Send a POST request with form values to a path of the server, returning the response status and body.
+func (player *testPlayer) post(path string, form url.Values) (int, string) {
+	player.t.Helper()
+	response, err := player.client.PostForm(player.server.URL+path, form)
+	if err != nil {
+		player.t.Fatalf("POST %s failed: %v", path, err)
+	}
+	return readTestResponse(player.t, response)
+}
+
+// Send a DELETE request to a path of the server, returning the response status and body.
+func (player *testPlayer) delete(path string) (int, string) {
+	player.t.Helper()
+	request, err := http.NewRequest(http.MethodDelete, player.server.URL+path, nil)
+	if err != nil {
+		player.t.Fatalf("failed to create DELETE %s: %v", path, err)
+	}
+	response, err := player.client.Do(request)
+	if err != nil {
+		player.t.Fatalf("DELETE %s failed: %v", path, err)
+	}
+	return readTestResponse(player.t, response)
+}
+
+// Send a POST to a route of a game, failing the test unless it succeeds.
+func (player *testPlayer) mustPost(gameID string, route string, form url.Values) {
+	player.t.Helper()
+	status, body := player.post("/game/"+gameID+"/"+route, form)
+	if status != http.StatusOK {
+		player.t.Fatalf("POST %s responded %d: %s", route, status, body)
+	}
+}
+
+// Open a game page, joining the game.
+func (player *testPlayer) join(gameID string) {
+	player.t.Helper()
+	status, body := player.get("/game/" + gameID + "/")
+	if status != http.StatusOK {
+		player.t.Fatalf("joining game responded %d: %s", status, body)
+	}
+}
+
+// Get the state of a game as the player sees it, failing the test if it cannot be fetched.
+func (player *testPlayer) state(gameID string) gameState {
+	player.t.Helper()
+	status, body := player.get("/game/" + gameID + "/state")
+	if status != http.StatusOK {
+		player.t.Fatalf("getting state responded %d: %s", status, body)
+	}
+	var state gameState
+	if err := json.Unmarshal([]byte(body), &state); err != nil {
+		player.t.Fatalf("failed to decode state: %v", err)
+	}
+	return state
+}
+
+// Get the ID the server issued to the player, from their identity cookie.
+func (player *testPlayer) id() string {
+	player.t.Helper()
+	serverURL, err := url.Parse(player.server.URL)
+	if err != nil {
+		player.t.Fatalf("invalid server URL: %v", err)
+	}
+	request := &http.Request{Header: http.Header{}}
+	for _, cookie := range player.client.Jar.Cookies(serverURL) {
+		request.AddCookie(cookie)
+	}
+	identity, ok := player.server.master.playerIdentifier.identify(request)
+	if !ok {
+		player.t.Fatal("player has no identity cookie")
+	}
+	return identity.ID
+}
+
+// Read the status and body of a response.
+func readTestResponse(t *testing.T, response *http.Response) (int, string) {
+	t.Helper()
+	defer response.Body.Close()
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		t.Fatalf("failed to read response: %v", err)
+	}
+	return response.StatusCode, string(body)
+}
+
+// Play a round of a game to its end: the guesser asks a question, the oracle answers, and the oracle gives the verdict.
+func playRound(oracle *testPlayer, guesser *testPlayer, gameID string, correct bool) {
+	oracle.t.Helper()
+	guesser.mustPost(gameID, "submitResponse", url.Values{"response": {"Is it an animal?"}})
+	oracle.mustPost(gameID, "submitResponse", url.Values{"response": {"Yes"}})
+
+	verdict := "oracleVerdictIncorrect"
+	if correct {
+		verdict = "oracleVerdictCorrect"
+	}
+	status, body := oracle.get("/game/" + gameID + "/" + verdict)
+	if status != http.StatusOK {
+		oracle.t.Fatalf("verdict responded %d: %s", status, body)
+	}
+}
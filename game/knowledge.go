@@ -0,0 +1,74 @@
+package game
+
+import (
+	"html/template"
+	"net/http"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/hmcalister/twentyquestions/templates"
+)
+
+var (
+	// Template for the admin page to review the learned knowledge.
+	knowledgeReviewTemplate = template.Must(template.New("adminKnowledge.html").ParseFS(templates.Files, "adminKnowledge.html"))
+)
+
+// Teach the knowledge base from a finished round, if the oracle revealed their secret.
+// Rounds with the computer oracle are skipped, as its answers already come from the knowledge base.
+func (session *GameSession) learnFromRound(round *GameData) {
+	round.gameStateMutex.Lock()
+	if round.hasComputerOracle() || round.secret == "" {
+		round.gameStateMutex.Unlock()
+		return
+	}
+	secret := round.secret
+	observations := round.observations()
+	round.gameStateMutex.Unlock()
+
+	learnedCount, err := session.master.knowledgeBase.Learn(secret, session.config.category, observations)
+	if err != nil {
+		log.Error().Str("GameID", session.gameID).Err(err).Msg("Failed to save learned knowledge")
+		return
+	}
+	log.Debug().Str("GameID", session.gameID).Int("Round", round.roundNumber).Int("Learned", learnedCount).Msg("Learned From Round")
+}
+
+// --------------------------------------------------------------------------------
+// Routing Functions
+// --------------------------------------------------------------------------------
+
+// Render the learned knowledge for review -- the admin router is expected to be mounted behind authentication.
+func (master *GameMaster) renderKnowledgeReview(w http.ResponseWriter, r *http.Request) {
+	err := knowledgeReviewTemplate.Execute(w, master.knowledgeBase.Review())
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to write knowledge review template")
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
+
+// Accept a value for, or discard, a learned fact, then return to the review page.
+func (master *GameMaster) handleKnowledgeReview(w http.ResponseWriter, r *http.Request) {
+	entityName := r.FormValue("entity")
+	attributeName := r.FormValue("attribute")
+
+	var err error
+	switch r.FormValue("action") {
+	case "yes":
+		err = master.knowledgeBase.Accept(entityName, attributeName, true)
+	case "no":
+		err = master.knowledgeBase.Accept(entityName, attributeName, false)
+	case "discard":
+		err = master.knowledgeBase.Discard(entityName, attributeName)
+	default:
+		http.Error(w, "unknown review action", http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	log.Info().Str("Entity", entityName).Str("Attribute", attributeName).Str("Action", r.FormValue("action")).Msg("Learned Knowledge Reviewed")
+	http.Redirect(w, r, "/admin/knowledge", http.StatusSeeOther)
+}
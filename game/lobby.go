@@ -0,0 +1,124 @@
+package game
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/hmcalister/twentyquestions/templates"
+)
+
+const (
+	// How often the lobby is resent to clients even without changes, to keep the game ages current.
+	lobbyRefreshInterval time.Duration = 30 * time.Second
+)
+
+var (
+	// Templates for Lobby page.
+	lobbyTemplate = template.Must(template.New("lobby.html").Funcs(template.FuncMap{
+		"formatAge": formatAge,
+	}).ParseFS(templates.Files, "lobby.html", "lobbyItems.html"))
+)
+
+// A public game as shown in the lobby.
+type lobbyListing struct {
+	GameID        string    `json:"gameID"`
+	Title         string    `json:"title"`
+	Category      string    `json:"category"`
+	Locked        bool      `json:"locked"`
+	URL           string    `json:"url"`
+	Players       int       `json:"players"`
+	Round         int       `json:"round"`
+	QuestionsUsed int       `json:"questionsUsed"`
+	CreatedAt     time.Time `json:"createdAt"`
+	AgeSeconds    int64     `json:"ageSeconds"`
+}
+
+// Format a duration in seconds as a short, human readable age.
+func formatAge(ageSeconds int64) string {
+	age := time.Duration(ageSeconds) * time.Second
+	switch {
+	case age < time.Minute:
+		return "just now"
+	case age < time.Hour:
+		return fmt.Sprintf("%dm", int(age.Minutes()))
+	default:
+		return fmt.Sprintf("%dh %dm", int(age.Hours()), int(age.Minutes())%60)
+	}
+}
+
+// Gather the listings of all public games, newest first.
+func (master *GameMaster) lobbyListings() []lobbyListing {
+	master.gameMapMutex.RLock()
+	publicSessions := make([]*GameSession, 0)
+	for _, session := range master.gameMap {
+		if session.config.isPublic && !session.hasEnded() {
+			publicSessions = append(publicSessions, session)
+		}
+	}
+	master.gameMapMutex.RUnlock()
+
+	listings := make([]lobbyListing, 0, len(publicSessions))
+	for _, session := range publicSessions {
+		listings = append(listings, session.listing())
+	}
+	sort.Slice(listings, func(i, j int) bool {
+		return listings[i].CreatedAt.After(listings[j].CreatedAt)
+	})
+	return listings
+}
+
+// Render the current lobby listings as an SSE event.
+func (master *GameMaster) lobbyEvent() sseEvent {
+	var listingsBytes bytes.Buffer
+	err := lobbyTemplate.ExecuteTemplate(&listingsBytes, "lobbyItems.html", master.lobbyListings())
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to write lobby items template")
+	}
+	return sseEvent{name: "lobby", data: listingsBytes.String()}
+}
+
+// Send the lobby to all lobby clients. Called whenever a public game changes.
+func (master *GameMaster) broadcastLobby() {
+	if master.lobbyBroadcaster.clientCount() == 0 {
+		return
+	}
+	master.lobbyBroadcaster.broadcast(master.lobbyEvent())
+}
+
+// Periodically resend the lobby so the ages of games stay current. Never returns.
+func (master *GameMaster) refreshLobbyPeriodically() {
+	for range time.Tick(lobbyRefreshInterval) {
+		master.broadcastLobby()
+	}
+}
+
+// --------------------------------------------------------------------------------
+// Routing Functions
+// --------------------------------------------------------------------------------
+
+// Render the lobby page. The listings themselves are sent when the SSE connection opens.
+func (master *GameMaster) renderLobby(w http.ResponseWriter, r *http.Request) {
+	err := lobbyTemplate.ExecuteTemplate(w, "lobby.html", nil)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to write lobby template")
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
+
+// SSE endpoint for the lobby listings.
+func (master *GameMaster) lobbySourceSSE(w http.ResponseWriter, r *http.Request) {
+	master.lobbyBroadcaster.serve(w, r, func() []sseEvent {
+		return []sseEvent{master.lobbyEvent(), master.noticeEvent()}
+	})
+}
+
+// JSON endpoint for the lobby listings.
+func (master *GameMaster) apiLobby(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, master.lobbyListings())
+}
@@ -0,0 +1,128 @@
+package game
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFormatAge(t *testing.T) {
+	testCases := []struct {
+		ageSeconds int64
+		want       string
+	}{
+		{0, "just now"},
+		{59, "just now"},
+		{60, "1m"},
+		{3599, "59m"},
+		{3600, "1h 0m"},
+		{2*3600 + 5*60 + 30, "2h 5m"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.want, func(t *testing.T) {
+			if got := formatAge(testCase.ageSeconds); got != testCase.want {
+				t.Errorf("formatAge(%d) = %q, want %q", testCase.ageSeconds, got, testCase.want)
+			}
+		})
+	}
+}
+
+// Get the lobby listings from the API, failing the test if they cannot be fetched.
+func (player *testPlayer) lobbyListings() []lobbyListing {
+	player.t.Helper()
+	status, body := player.get("/api/lobby")
+	if status != http.StatusOK {
+		player.t.Fatalf("getting lobby responded %d: %s", status, body)
+	}
+	var listings []lobbyListing
+	if err := json.Unmarshal([]byte(body), &listings); err != nil {
+		player.t.Fatalf("failed to decode lobby: %v", err)
+	}
+	return listings
+}
+
+func TestLobbyListings(t *testing.T) {
+	server := newTestServer(t)
+	oracle := server.newPlayer(t)
+	guesser := server.newPlayer(t)
+
+	if listings := guesser.lobbyListings(); len(listings) != 0 {
+		t.Fatalf("listings = %+v, want an empty lobby", listings)
+	}
+
+	publicGameID := oracle.createGame(url.Values{"public": {"on"}, "title": {"Quiz Night"}, "category": {"Animals"}})
+	lockedGameID := oracle.createGame(url.Values{"public": {"on"}, "title": {"Friends Only"}, "passphrase": {"open sesame"}})
+	oracle.createGame(url.Values{"title": {"Private Game"}})
+	endedGameID := oracle.createGame(url.Values{"public": {"on"}, "title": {"Ended Game"}})
+	deletedGameID := oracle.createGame(url.Values{"public": {"on"}, "title": {"Deleted Game"}})
+
+	endedSession, _ := server.master.sessionByID(endedGameID)
+	if err := endedSession.forceEnd(); err != nil {
+		t.Fatalf("failed to end game: %v", err)
+	}
+	deletedSession, _ := server.master.sessionByID(deletedGameID)
+	server.master.deleteSession(deletedSession)
+
+	guesser.join(publicGameID)
+	guesser.mustPost(publicGameID, "submitResponse", url.Values{"response": {"Is it an animal?"}})
+
+	// Only public games still being played are listed, newest first.
+	listings := guesser.lobbyListings()
+	testCases := []struct {
+		name string
+		want lobbyListing
+	}{
+		{"locked game", lobbyListing{GameID: lockedGameID, Title: "Friends Only", Category: LobbyCategories[0], Locked: true, Players: 1, Round: 1}},
+		{"played game", lobbyListing{GameID: publicGameID, Title: "Quiz Night", Category: "Animals", Players: 2, Round: 1, QuestionsUsed: 1}},
+	}
+	if len(listings) != len(testCases) {
+		t.Fatalf("listings = %+v, want %d public games", listings, len(testCases))
+	}
+
+	for i, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			listing, want := listings[i], testCase.want
+			if listing.GameID != want.GameID || listing.Title != want.Title || listing.Category != want.Category || listing.Locked != want.Locked ||
+				listing.Players != want.Players || listing.Round != want.Round || listing.QuestionsUsed != want.QuestionsUsed {
+				t.Errorf("got %+v, want %+v", listing, want)
+			}
+			if listing.URL != "/game/"+want.GameID+"/" || listing.CreatedAt.IsZero() {
+				t.Errorf("listing %+v has no link or creation time", listing)
+			}
+		})
+	}
+}
+
+func TestLobbyEvents(t *testing.T) {
+	server := newTestServer(t)
+	oracle := server.newPlayer(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	client, err := server.master.lobbyBroadcaster.subscribe(ctx)
+	if err != nil {
+		t.Fatalf("failed to subscribe to lobby: %v", err)
+	}
+	defer client.cancelFunc()
+
+	// Private games never change the lobby, so the first event is for the public game.
+	oracle.createGame(url.Values{"title": {"Private Game"}})
+	oracle.createGame(url.Values{"public": {"on"}, "title": {"Quiz Night"}})
+	select {
+	case event := <-client.eventsChannel:
+		if event.name != "lobby" || !strings.Contains(event.data, "Quiz Night") || strings.Contains(event.data, "Private Game") {
+			t.Errorf("got %s event %q, want the lobby with only the public game", event.name, event.data)
+		}
+	case <-ctx.Done():
+		t.Fatal("no lobby event after a public game was created")
+	}
+
+	if status, body := oracle.get("/lobby/"); status != http.StatusOK {
+		t.Errorf("lobby page responded %d: %s", status, body)
+	}
+}
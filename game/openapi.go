@@ -0,0 +1,364 @@
+package game
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog/log"
+
+	"github.com/hmcalister/twentyquestions/data"
+	"github.com/hmcalister/twentyquestions/templates"
+)
+
+var (
+	// The OpenAPI document describing the JSON API, and the docs page rendered from it.
+	openAPISpec     = mustLoadOpenAPIDocument(data.OpenAPIDocument)
+	apiDocsTemplate = template.Must(template.ParseFS(templates.Files, "apiDocs.html"))
+
+	// Routes of the API router that describe the API itself, and so are not in the document.
+	openAPIDocumentationRoutes = map[string]bool{"/openapi.json": true, "/docs": true}
+
+	// Order of methods on the docs page.
+	openAPIMethods = []string{"get", "post", "put", "patch", "delete"}
+)
+
+// The parts of an OpenAPI 3 document shown on the docs page.
+type openAPIDocument struct {
+	Info struct {
+		Title       string `json:"title"`
+		Version     string `json:"version"`
+		Description string `json:"description"`
+	} `json:"info"`
+	Servers []struct {
+		URL string `json:"url"`
+	} `json:"servers"`
+	Paths      map[string]map[string]openAPIOperation `json:"paths"`
+	Components struct {
+		Parameters map[string]openAPIParameter `json:"parameters"`
+		Responses  map[string]openAPIResponse  `json:"responses"`
+		Schemas    map[string]*openAPISchema   `json:"schemas"`
+	} `json:"components"`
+
+	// The document exactly as loaded, served at /api/openapi.json.
+	raw []byte
+}
+
+// A single method of a path.
+type openAPIOperation struct {
+	Tags        []string                   `json:"tags"`
+	Summary     string                     `json:"summary"`
+	Description string                     `json:"description"`
+	Parameters  []openAPIParameter         `json:"parameters"`
+	Responses   map[string]openAPIResponse `json:"responses"`
+}
+
+// A parameter of an operation, or a reference to a shared parameter.
+type openAPIParameter struct {
+	Ref         string         `json:"$ref"`
+	Name        string         `json:"name"`
+	In          string         `json:"in"`
+	Description string         `json:"description"`
+	Required    bool           `json:"required"`
+	Schema      *openAPISchema `json:"schema"`
+}
+
+// A response of an operation, or a reference to a shared response.
+type openAPIResponse struct {
+	Ref         string `json:"$ref"`
+	Description string `json:"description"`
+	Content     map[string]struct {
+		Schema *openAPISchema `json:"schema"`
+	} `json:"content"`
+}
+
+// A JSON schema, or a reference to a shared schema.
+type openAPISchema struct {
+	Ref         string                    `json:"$ref"`
+	Type        string                    `json:"type"`
+	Format      string                    `json:"format"`
+	Description string                    `json:"description"`
+	Enum        []string                  `json:"enum"`
+	Items       *openAPISchema            `json:"items"`
+	Properties  map[string]*openAPISchema `json:"properties"`
+	Required    []string                  `json:"required"`
+}
+
+// Load the OpenAPI document, panicking if it cannot be parsed -- the same as the templates.
+func mustLoadOpenAPIDocument(raw []byte) *openAPIDocument {
+	document := &openAPIDocument{raw: raw}
+	err := json.Unmarshal(raw, document)
+	if err != nil {
+		panic(fmt.Errorf("failed to parse OpenAPI document: %w", err))
+	}
+	return document
+}
+
+// The name of the component a reference points to, e.g. "Error" for "#/components/schemas/Error".
+func refName(ref string) string {
+	return ref[strings.LastIndex(ref, "/")+1:]
+}
+
+// A short description of the type of a schema, e.g. "array of LobbyListing" or "string (date-time)".
+func (schema *openAPISchema) typeName() string {
+	switch {
+	case schema == nil:
+		return ""
+	case schema.Ref != "":
+		return refName(schema.Ref)
+	case schema.Type == "array":
+		return "array of " + schema.Items.typeName()
+	case len(schema.Enum) > 0:
+		return fmt.Sprintf("%s: %s", schema.Type, strings.Join(schema.Enum, ", "))
+	case schema.Format != "":
+		return fmt.Sprintf("%s (%s)", schema.Type, schema.Format)
+	default:
+		return schema.Type
+	}
+}
+
+// --------------------------------------------------------------------------------
+// Route Checking
+// --------------------------------------------------------------------------------
+
+// Find every route of the API router that is missing from the OpenAPI document, and every documented
+// operation that has no route, so the document is kept in sync as routes are added. Each is described as e.g. "get /lobby".
+func (document *openAPIDocument) routeDrift(router chi.Routes) (undocumented []string, unrouted []string, err error) {
+	routed := make(map[string]bool)
+	err = chi.Walk(router, func(method string, route string, handler http.Handler, middlewares ...func(http.Handler) http.Handler) error {
+		if openAPIDocumentationRoutes[route] {
+			return nil
+		}
+		method = strings.ToLower(method)
+		routed[method+" "+route] = true
+		if _, ok := document.Paths[route][method]; !ok {
+			undocumented = append(undocumented, method+" "+route)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for path, operations := range document.Paths {
+		for method := range operations {
+			if !routed[method+" "+path] {
+				unrouted = append(unrouted, method+" "+path)
+			}
+		}
+	}
+	sort.Strings(undocumented)
+	sort.Strings(unrouted)
+	return undocumented, unrouted, nil
+}
+
+// Log any drift between the routes of the API router and the OpenAPI document.
+func (document *openAPIDocument) checkRoutes(router chi.Routes) {
+	undocumented, unrouted, err := document.routeDrift(router)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to walk API routes")
+		return
+	}
+	for _, route := range undocumented {
+		log.Warn().Str("Route", route).Msg("API route is missing from the OpenAPI document")
+	}
+	for _, route := range unrouted {
+		log.Warn().Str("Route", route).Msg("OpenAPI document describes a route that does not exist")
+	}
+}
+
+// --------------------------------------------------------------------------------
+// Docs Page
+// --------------------------------------------------------------------------------
+
+// Data to be passed to apiDocs.html template
+type apiDocsTemplateData struct {
+	Title       string
+	Version     string
+	Description string
+	BasePath    string
+	Operations  []apiDocsOperation
+	Schemas     []apiDocsSchema
+}
+
+// An operation as shown on the docs page, with references resolved.
+type apiDocsOperation struct {
+	Method      string
+	Path        string
+	Tag         string
+	Summary     string
+	Description string
+	Parameters  []apiDocsField
+	Responses   []apiDocsResponse
+}
+
+// A response as shown on the docs page.
+type apiDocsResponse struct {
+	Status      string
+	Description string
+
+	// Each content type, with the type of its schema, e.g. "application/json: Transcript".
+	Content []string
+}
+
+// A parameter of an operation, or a property of a schema.
+type apiDocsField struct {
+	Name        string
+	In          string
+	Type        string
+	Description string
+	Required    bool
+}
+
+// A shared schema as shown on the docs page.
+type apiDocsSchema struct {
+	Name        string
+	Description string
+	Properties  []apiDocsField
+}
+
+// Gather the operations and schemas of the document for the docs page, resolving references to shared components.
+func (document *openAPIDocument) templateData() apiDocsTemplateData {
+	templateData := apiDocsTemplateData{
+		Title:       document.Info.Title,
+		Version:     document.Info.Version,
+		Description: document.Info.Description,
+		Operations:  make([]apiDocsOperation, 0),
+		Schemas:     make([]apiDocsSchema, 0),
+	}
+	if len(document.Servers) > 0 {
+		templateData.BasePath = document.Servers[0].URL
+	}
+
+	paths := make([]string, 0, len(document.Paths))
+	for path := range document.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		for _, method := range openAPIMethods {
+			operation, ok := document.Paths[path][method]
+			if ok {
+				templateData.Operations = append(templateData.Operations, document.operationTemplateData(method, path, operation))
+			}
+		}
+	}
+	sort.SliceStable(templateData.Operations, func(i, j int) bool {
+		return templateData.Operations[i].Tag < templateData.Operations[j].Tag
+	})
+
+	schemaNames := make([]string, 0, len(document.Components.Schemas))
+	for name := range document.Components.Schemas {
+		schemaNames = append(schemaNames, name)
+	}
+	sort.Strings(schemaNames)
+	for _, name := range schemaNames {
+		schema := document.Components.Schemas[name]
+		templateData.Schemas = append(templateData.Schemas, apiDocsSchema{
+			Name:        name,
+			Description: schema.Description,
+			Properties:  schema.propertyFields(),
+		})
+	}
+	return templateData
+}
+
+// Gather a single operation for the docs page.
+func (document *openAPIDocument) operationTemplateData(method string, path string, operation openAPIOperation) apiDocsOperation {
+	operationData := apiDocsOperation{
+		Method:      strings.ToUpper(method),
+		Path:        path,
+		Summary:     operation.Summary,
+		Description: operation.Description,
+		Parameters:  make([]apiDocsField, 0, len(operation.Parameters)),
+		Responses:   make([]apiDocsResponse, 0, len(operation.Responses)),
+	}
+	if len(operation.Tags) > 0 {
+		operationData.Tag = operation.Tags[0]
+	}
+
+	for _, parameter := range operation.Parameters {
+		if parameter.Ref != "" {
+			parameter = document.Components.Parameters[refName(parameter.Ref)]
+		}
+		operationData.Parameters = append(operationData.Parameters, apiDocsField{
+			Name:        parameter.Name,
+			In:          parameter.In,
+			Type:        parameter.Schema.typeName(),
+			Description: parameter.Description,
+			Required:    parameter.Required,
+		})
+	}
+
+	statuses := make([]string, 0, len(operation.Responses))
+	for status := range operation.Responses {
+		statuses = append(statuses, status)
+	}
+	sort.Strings(statuses)
+	for _, status := range statuses {
+		response := operation.Responses[status]
+		if response.Ref != "" {
+			response = document.Components.Responses[refName(response.Ref)]
+		}
+		responseData := apiDocsResponse{
+			Status:      status,
+			Description: response.Description,
+			Content:     make([]string, 0, len(response.Content)),
+		}
+		for contentType, content := range response.Content {
+			responseData.Content = append(responseData.Content, fmt.Sprintf("%s: %s", contentType, content.Schema.typeName()))
+		}
+		sort.Strings(responseData.Content)
+		operationData.Responses = append(operationData.Responses, responseData)
+	}
+	return operationData
+}
+
+// The properties of an object schema, in alphabetical order.
+func (schema *openAPISchema) propertyFields() []apiDocsField {
+	required := make(map[string]bool)
+	for _, name := range schema.Required {
+		required[name] = true
+	}
+
+	names := make([]string, 0, len(schema.Properties))
+	for name := range schema.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fields := make([]apiDocsField, 0, len(names))
+	for _, name := range names {
+		property := schema.Properties[name]
+		fields = append(fields, apiDocsField{
+			Name:        name,
+			Type:        property.typeName(),
+			Description: property.Description,
+			Required:    required[name],
+		})
+	}
+	return fields
+}
+
+// --------------------------------------------------------------------------------
+// Routing Functions
+// --------------------------------------------------------------------------------
+
+// Serve the OpenAPI document describing the JSON API.
+func (master *GameMaster) apiOpenAPIDocument(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(openAPISpec.raw)
+}
+
+// Render the docs page for the JSON API.
+func (master *GameMaster) renderAPIDocs(w http.ResponseWriter, r *http.Request) {
+	err := apiDocsTemplate.Execute(w, openAPISpec.templateData())
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to write API docs template")
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,254 @@
+package game
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"slices"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Check every response of the API against the OpenAPI document -- its status, content type and body.
+// Every documented response of every operation must be exercised, so a response added to the document is also tested.
+func TestOpenAPIResponsesMatchDocument(t *testing.T) {
+	server := newTestServer(t)
+	oracle := server.newPlayer(t)
+	guesser := server.newPlayer(t)
+	stranger := server.newPlayer(t)
+
+	// A finished public game, so the lobby, archive and leaderboards have something to list.
+	finishedGameID := oracle.createGame(url.Values{"title": {"Finished"}, "public": {"on"}})
+	guesser.join(finishedGameID)
+	playRound(oracle, guesser, finishedGameID, true)
+	guesser.mustPost(finishedGameID, "chat", url.Values{"message": {"Good game"}})
+
+	// A game ended by an admin partway through its first round.
+	endedGameID := oracle.createGame(url.Values{"title": {"Ended"}})
+	guesser.join(endedGameID)
+	guesser.mustPost(endedGameID, "submitResponse", url.Values{"response": {"Is it a plant?"}})
+	endedSession, _ := server.master.sessionByID(endedGameID)
+	if err := endedSession.forceEnd(); err != nil {
+		t.Fatalf("failed to end game: %v", err)
+	}
+
+	// A game in its first round, and a game with a passphrase the stranger has not entered.
+	activeGameID := oracle.createGame(url.Values{"title": {"Active"}, "public": {"on"}})
+	lockedGameID := oracle.createGame(url.Values{"passphrase": {"open sesame"}})
+
+	// A maintenance notice, so it is included in the state of each game.
+	server.master.setNotice("Restarting at noon")
+
+	guesserID := guesser.id()
+
+	testCases := []struct {
+		player *testPlayer
+		path   string
+		url    string
+		status int
+	}{
+		{guesser, "/lobby", "/api/lobby", http.StatusOK},
+
+		{guesser, "/games/{gameID}/state", "/api/games/" + finishedGameID + "/state", http.StatusOK},
+		{oracle, "/games/{gameID}/state", "/api/games/" + activeGameID + "/state", http.StatusOK},
+		{guesser, "/games/{gameID}/state", "/api/games/" + endedGameID + "/state", http.StatusOK},
+		{stranger, "/games/{gameID}/state", "/api/games/" + lockedGameID + "/state", http.StatusUnauthorized},
+		{guesser, "/games/{gameID}/state", "/api/games/missing/state", http.StatusNotFound},
+
+		{guesser, "/games/{gameID}/export", "/api/games/" + finishedGameID + "/export", http.StatusOK},
+		{guesser, "/games/{gameID}/export", "/api/games/" + finishedGameID + "/export?format=csv", http.StatusOK},
+		{guesser, "/games/{gameID}/export", "/api/games/" + finishedGameID + "/export?format=md", http.StatusOK},
+		{oracle, "/games/{gameID}/export", "/api/games/" + activeGameID + "/export", http.StatusOK},
+		{guesser, "/games/{gameID}/export", "/api/games/" + finishedGameID + "/export?format=pdf", http.StatusBadRequest},
+		{stranger, "/games/{gameID}/export", "/api/games/" + lockedGameID + "/export", http.StatusUnauthorized},
+		{stranger, "/games/{gameID}/export", "/api/games/" + activeGameID + "/export", http.StatusForbidden},
+		{guesser, "/games/{gameID}/export", "/api/games/missing/export", http.StatusNotFound},
+
+		{guesser, "/archive", "/api/archive", http.StatusOK},
+		{guesser, "/archive", "/api/archive?q=Finished", http.StatusOK},
+		{guesser, "/archive/{gameID}", "/api/archive/" + finishedGameID, http.StatusOK},
+		{guesser, "/archive/{gameID}", "/api/archive/" + endedGameID, http.StatusOK},
+		{guesser, "/games/{gameID}/export", "/api/games/" + endedGameID + "/export", http.StatusOK},
+		{guesser, "/archive/{gameID}", "/api/archive/missing", http.StatusNotFound},
+
+		{guesser, "/leaderboard", "/api/leaderboard", http.StatusOK},
+		{guesser, "/leaderboard", "/api/leaderboard?window=week&sort=wins", http.StatusOK},
+		{guesser, "/leaderboard", "/api/leaderboard?window=decade", http.StatusBadRequest},
+
+		{guesser, "/players/{playerID}/stats", "/api/players/" + guesserID + "/stats", http.StatusOK},
+		{guesser, "/players/{playerID}/stats", "/api/players/" + guesserID + "/stats?window=decade", http.StatusBadRequest},
+		{guesser, "/players/{playerID}/stats", "/api/players/nobody/stats", http.StatusNotFound},
+	}
+
+	exercised := make(map[string]bool)
+	for _, testCase := range testCases {
+		t.Run(testCase.url, func(t *testing.T) {
+			response, err := testCase.player.client.Get(server.URL + testCase.url)
+			if err != nil {
+				t.Fatalf("request failed: %v", err)
+			}
+			defer response.Body.Close()
+			body, err := io.ReadAll(response.Body)
+			if err != nil {
+				t.Fatalf("failed to read response: %v", err)
+			}
+			if response.StatusCode != testCase.status {
+				t.Fatalf("status = %d, want %d: %s", response.StatusCode, testCase.status, body)
+			}
+
+			status := fmt.Sprint(response.StatusCode)
+			exercised["get "+testCase.path+" "+status] = true
+			for _, problem := range openAPISpec.validateResponse("get", testCase.path, status, response.Header.Get("Content-Type"), body) {
+				t.Error(problem)
+			}
+		})
+	}
+
+	for path, operations := range openAPISpec.Paths {
+		for method, operation := range operations {
+			for status := range operation.Responses {
+				if !exercised[method+" "+path+" "+status] {
+					t.Errorf("documented response %s %s %s is not tested", method, path, status)
+				}
+			}
+		}
+	}
+}
+
+// Fail if the routes of the API router and the OpenAPI document have drifted apart.
+func TestOpenAPIRoutesMatchDocument(t *testing.T) {
+	server := newTestServer(t)
+	undocumented, unrouted, err := openAPISpec.routeDrift(server.master.APIRouter)
+	if err != nil {
+		t.Fatalf("failed to walk API routes: %v", err)
+	}
+	for _, route := range undocumented {
+		t.Errorf("API route %s is missing from the OpenAPI document", route)
+	}
+	for _, route := range unrouted {
+		t.Errorf("OpenAPI document describes %s, which has no route", route)
+	}
+}
+
+// --------------------------------------------------------------------------------
+// Schema Validation
+// --------------------------------------------------------------------------------
+
+// Check a response against the document, returning a description of each way it does not match.
+func (document *openAPIDocument) validateResponse(method string, path string, status string, contentType string, body []byte) []string {
+	operation, ok := document.Paths[path][method]
+	if !ok {
+		return []string{fmt.Sprintf("%s %s is not documented", method, path)}
+	}
+	response, ok := operation.Responses[status]
+	if !ok {
+		return []string{fmt.Sprintf("%s %s does not document status %s", method, path, status)}
+	}
+	if response.Ref != "" {
+		response = document.Components.Responses[refName(response.Ref)]
+	}
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return []string{fmt.Sprintf("invalid content type %q", contentType)}
+	}
+	content, ok := response.Content[mediaType]
+	if !ok {
+		documented := make([]string, 0, len(response.Content))
+		for documentedType := range response.Content {
+			documented = append(documented, documentedType)
+		}
+		sort.Strings(documented)
+		return []string{fmt.Sprintf("content type %s is not documented for status %s, want one of %v", mediaType, status, documented)}
+	}
+
+	if mediaType != "application/json" {
+		return document.validate(content.Schema, string(body), "body")
+	}
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return []string{fmt.Sprintf("body is not JSON: %v", err)}
+	}
+	return document.validate(content.Schema, value, "body")
+}
+
+// Check a value decoded from JSON against a schema, returning a description of each way it does not match.
+// Objects may not have properties the schema does not describe, so undocumented fields are caught.
+func (document *openAPIDocument) validate(schema *openAPISchema, value interface{}, location string) []string {
+	if schema.Ref != "" {
+		return document.validate(document.Components.Schemas[refName(schema.Ref)], value, location)
+	}
+
+	problems := make([]string, 0)
+	switch schema.Type {
+	case "object":
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return []string{fmt.Sprintf("%s: got %T, want object", location, value)}
+		}
+		for _, name := range schema.Required {
+			if _, ok := object[name]; !ok {
+				problems = append(problems, fmt.Sprintf("%s: missing required property %s", location, name))
+			}
+		}
+		for name, propertyValue := range object {
+			property, ok := schema.Properties[name]
+			if !ok {
+				problems = append(problems, fmt.Sprintf("%s: property %s is not documented", location, name))
+				continue
+			}
+			problems = append(problems, document.validate(property, propertyValue, location+"."+name)...)
+		}
+
+	case "array":
+		array, ok := value.([]interface{})
+		if !ok {
+			return []string{fmt.Sprintf("%s: got %T, want array", location, value)}
+		}
+		for i, item := range array {
+			problems = append(problems, document.validate(schema.Items, item, fmt.Sprintf("%s[%d]", location, i))...)
+		}
+
+	case "string":
+		text, ok := value.(string)
+		if !ok {
+			return []string{fmt.Sprintf("%s: got %T, want string", location, value)}
+		}
+		if len(schema.Enum) > 0 && !slices.Contains(schema.Enum, text) {
+			problems = append(problems, fmt.Sprintf("%s: %q is not one of %v", location, text, schema.Enum))
+		}
+		if schema.Format == "date-time" {
+			if _, err := time.Parse(time.RFC3339Nano, text); err != nil {
+				problems = append(problems, fmt.Sprintf("%s: %q is not a date-time", location, text))
+			}
+		}
+
+	case "integer":
+		number, ok := value.(json.Number)
+		if !ok || strings.ContainsAny(number.String(), ".eE") {
+			return []string{fmt.Sprintf("%s: got %v, want integer", location, value)}
+		}
+
+	case "number":
+		if _, ok := value.(json.Number); !ok {
+			return []string{fmt.Sprintf("%s: got %T, want number", location, value)}
+		}
+
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return []string{fmt.Sprintf("%s: got %T, want boolean", location, value)}
+		}
+
+	default:
+		problems = append(problems, fmt.Sprintf("%s: schema has unknown type %q", location, schema.Type))
+	}
+	return problems
+}
@@ -0,0 +1,178 @@
+package game
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/rs/zerolog/log"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// --------------------------------------------------------------------------------
+// JWT Data and Methods
+// --------------------------------------------------------------------------------
+
+// Check a request for the guesser session JWT, proving the player has entered the passphrase of this session.
+func (session *GameSession) checkRequestAdmitted(r *http.Request) bool {
+	// Ensure cookie actually exits
+
+	tokenCookie, err := r.Cookie(session.gameID)
+	if err != nil {
+		if err == http.ErrNoCookie {
+			log.Debug().Msg("Guesser JWT Check - No Token Cookie")
+			return false
+		}
+		log.Debug().Msg("Guesser JWT Check - Error Reading Cookie")
+		return false
+	}
+
+	return session.checkGuesserToken(tokenCookie.Value, playerFromRequest(r))
+}
+
+// Check a guesser session JWT, proving the player has entered the passphrase of this session.
+func (session *GameSession) checkGuesserToken(tokenString string, player playerIdentity) bool {
+	// Get claims from token by decoding with key
+
+	claims := &jwt.RegisteredClaims{}
+	token, err := jwt.ParseWithClaims(tokenString, claims, func(t *jwt.Token) (interface{}, error) {
+		return session.guesserJWTKey, nil
+	})
+
+	// Ensure decoding did not fail
+
+	if err != nil {
+		if err == jwt.ErrSignatureInvalid {
+			log.Debug().Msg("Guesser JWT Check - Invalid Signature")
+			return false
+		}
+		log.Debug().Msg("Guesser JWT Check - Other Token Parse Error")
+		return false
+	}
+
+	// Ensure the token is still valid (e.g. not expired)
+
+	if !token.Valid {
+		log.Debug().Msg("Guesser JWT Check - Token Invalid")
+		return false
+	}
+
+	// Ensure claims match expected -- the cookie is only valid for the player it was issued to.
+
+	if claims.Issuer != session.gameID || claims.Subject != player.ID {
+		return false
+	}
+
+	return true
+}
+
+// Sign a guesser session JWT for the player, returning the token and its expiry.
+func (session *GameSession) signGuesserToken(player playerIdentity) (string, time.Time, error) {
+	guesserJWTExpiry := time.Now().Add(gameDuration)
+	token := jwt.NewWithClaims(jwt.SigningMethodHS512, &jwt.RegisteredClaims{
+		Issuer:    session.gameID,
+		Subject:   player.ID,
+		ExpiresAt: jwt.NewNumericDate(guesserJWTExpiry),
+	})
+	tokenString, err := token.SignedString(session.guesserJWTKey)
+	return tokenString, guesserJWTExpiry, err
+}
+
+// Sign a guesser session JWT for the requesting player and set it as a cookie on the response.
+func (session *GameSession) issueGuesserSession(w http.ResponseWriter, player playerIdentity) {
+	tokenString, guesserJWTExpiry, err := session.signGuesserToken(player)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to sign guesser JWT")
+		return
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     session.gameID,
+		Value:    tokenString,
+		Path:     fmt.Sprintf("/game/%s/", session.gameID),
+		Expires:  guesserJWTExpiry,
+		HttpOnly: true,
+	})
+}
+
+// --------------------------------------------------------------------------------
+// Passphrase Methods
+// --------------------------------------------------------------------------------
+
+// Hash a passphrase for storage. An empty passphrase means the game is not protected, and gives a nil hash.
+func hashPassphrase(passphrase string) ([]byte, error) {
+	if passphrase == "" {
+		return nil, nil
+	}
+	return bcrypt.GenerateFromPassword([]byte(passphrase), bcrypt.DefaultCost)
+}
+
+// Check if the session is protected by a passphrase.
+func (session *GameSession) hasPassphrase() bool {
+	return session.config.passphraseHash != nil
+}
+
+// Check a passphrase attempt against the stored hash.
+func (session *GameSession) checkPassphrase(passphrase string) bool {
+	return bcrypt.CompareHashAndPassword(session.config.passphraseHash, []byte(passphrase)) == nil
+}
+
+// --------------------------------------------------------------------------------
+// Middleware
+// --------------------------------------------------------------------------------
+
+// Middleware to turn away players that have not entered the passphrase of a protected session.
+//
+// The game base is replaced by the passphrase form, all other routes are unauthorized.
+func (session *GameSession) requirePassphraseMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !session.hasPassphrase() || session.checkRequestAdmitted(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if strings.HasSuffix(r.URL.Path, "/"+session.gameID+"/") {
+			session.renderPassphrase(w, false)
+			return
+		}
+		w.WriteHeader(http.StatusUnauthorized)
+	})
+}
+
+// --------------------------------------------------------------------------------
+// Routing Functions
+// --------------------------------------------------------------------------------
+
+// Data to be passed to gamePassphrase.html template
+type gamePassphraseTemplateData struct {
+	Title            string
+	IncorrectAttempt bool
+}
+
+// Render the form asking for the passphrase of the session.
+func (session *GameSession) renderPassphrase(w http.ResponseWriter, incorrectAttempt bool) {
+	if incorrectAttempt {
+		w.WriteHeader(http.StatusUnauthorized)
+	}
+	err := gameTemplate.ExecuteTemplate(w, "gamePassphrase.html", gamePassphraseTemplateData{
+		Title:            session.config.title,
+		IncorrectAttempt: incorrectAttempt,
+	})
+	if err != nil {
+		log.Error().Str("GameID", session.gameID).Err(err).Msg("Failed to write game passphrase template")
+	}
+}
+
+// Check a submitted passphrase, issuing a guesser session and redirecting to the game if it is correct.
+func (session *GameSession) handlePassphrase(w http.ResponseWriter, r *http.Request) {
+	if session.hasPassphrase() && !session.checkPassphrase(r.FormValue("passphrase")) {
+		log.Debug().Str("GameID", session.gameID).Msg("Incorrect Passphrase")
+		session.renderPassphrase(w, true)
+		return
+	}
+
+	session.issueGuesserSession(w, playerFromRequest(r))
+	http.Redirect(w, r, fmt.Sprintf("/game/%s/", session.gameID), http.StatusSeeOther)
+}
@@ -0,0 +1,143 @@
+package game
+
+import (
+	"bytes"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Check if a player has been added to a session.
+func hasJoined(session *GameSession, playerID string) bool {
+	session.sessionMutex.Lock()
+	defer session.sessionMutex.Unlock()
+	return session.playerByID(playerID) != nil
+}
+
+func TestRequirePassphraseMiddleware(t *testing.T) {
+	server := newTestServer(t)
+	oracle := server.newPlayer(t)
+	gameID := oracle.createGame(url.Values{"passphrase": {"open sesame"}})
+	openGameID := oracle.createGame(url.Values{})
+	session, _ := server.master.sessionByID(gameID)
+
+	outsider := server.newPlayer(t)
+	admitted := server.newPlayer(t)
+	if status, _ := admitted.post("/game/"+gameID+"/passphrase", url.Values{"passphrase": {"wrong"}}); status != http.StatusUnauthorized {
+		t.Errorf("wrong passphrase responded %d, want %d", status, http.StatusUnauthorized)
+	}
+	if status, body := admitted.post("/game/"+gameID+"/passphrase", url.Values{"passphrase": {"open sesame"}}); status != http.StatusOK || strings.Contains(body, `name="passphrase"`) {
+		t.Fatalf("correct passphrase responded %d without redirecting to the game", status)
+	}
+
+	testCases := []struct {
+		name       string
+		player     *testPlayer
+		gameID     string
+		method     string
+		route      string
+		wantStatus int
+
+		// Set if the passphrase form is shown in place of the route.
+		wantForm bool
+	}{
+		{"outsider opens game", outsider, gameID, http.MethodGet, "", http.StatusOK, true},
+		{"outsider gets state", outsider, gameID, http.MethodGet, "state", http.StatusUnauthorized, false},
+		{"outsider gets controls", outsider, gameID, http.MethodGet, "controls", http.StatusUnauthorized, false},
+		{"outsider subscribes", outsider, gameID, http.MethodGet, "responsesSourceSSE", http.StatusUnauthorized, false},
+		{"outsider asks", outsider, gameID, http.MethodPost, "submitResponse", http.StatusUnauthorized, false},
+		{"outsider chats", outsider, gameID, http.MethodPost, "chat", http.StatusUnauthorized, false},
+		{"outsider opens open game", outsider, openGameID, http.MethodGet, "", http.StatusOK, false},
+		{"outsider gets state of open game", outsider, openGameID, http.MethodGet, "state", http.StatusOK, false},
+		{"admitted player opens game", admitted, gameID, http.MethodGet, "", http.StatusOK, false},
+		{"admitted player gets state", admitted, gameID, http.MethodGet, "state", http.StatusOK, false},
+		{"admitted player asks", admitted, gameID, http.MethodPost, "submitResponse", http.StatusOK, false},
+		{"creator gets state", oracle, gameID, http.MethodGet, "state", http.StatusOK, false},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			path := "/game/" + testCase.gameID + "/" + testCase.route
+			var status int
+			var body string
+			if testCase.method == http.MethodPost {
+				status, body = testCase.player.post(path, url.Values{"response": {"Is it blue?"}, "message": {"Hello"}})
+			} else {
+				status, body = testCase.player.get(path)
+			}
+			if status != testCase.wantStatus {
+				t.Errorf("responded %d, want %d", status, testCase.wantStatus)
+			}
+			if gotForm := strings.Contains(body, `name="passphrase"`); gotForm != testCase.wantForm {
+				t.Errorf("passphrase form shown = %v, want %v", gotForm, testCase.wantForm)
+			}
+		})
+	}
+
+	if hasJoined(session, outsider.id()) {
+		t.Error("player joined without entering the passphrase")
+	}
+	if !hasJoined(session, admitted.id()) {
+		t.Error("player did not join after entering the passphrase")
+	}
+}
+
+func TestCheckGuesserToken(t *testing.T) {
+	server := newTestServer(t)
+	oracle := server.newPlayer(t)
+	gameID := oracle.createGame(url.Values{"passphrase": {"open sesame"}})
+	otherGameID := oracle.createGame(url.Values{"passphrase": {"open sesame"}})
+	session, _ := server.master.sessionByID(gameID)
+	otherSession, _ := server.master.sessionByID(otherGameID)
+	player := playerIdentity{ID: "player", Name: "Player"}
+	if len(session.guesserJWTKey) != 64 || bytes.Equal(session.guesserJWTKey, otherSession.guesserJWTKey) {
+		t.Fatalf("games share a key or have a short key: %d bytes", len(session.guesserJWTKey))
+	}
+
+	signToken := func(key []byte, claims jwt.RegisteredClaims) string {
+		tokenString, err := jwt.NewWithClaims(jwt.SigningMethodHS512, &claims).SignedString(key)
+		if err != nil {
+			t.Fatalf("failed to sign token: %v", err)
+		}
+		return tokenString
+	}
+	validToken, _, err := session.signGuesserToken(player)
+	if err != nil {
+		t.Fatalf("failed to sign token: %v", err)
+	}
+	otherGameToken, _, _ := otherSession.signGuesserToken(player)
+
+	testCases := []struct {
+		name   string
+		token  string
+		player playerIdentity
+		want   bool
+	}{
+		{"valid", validToken, player, true},
+		{"other player", validToken, playerIdentity{ID: "other"}, false},
+		{"other game", otherGameToken, player, false},
+		{"other game issuer", signToken(session.guesserJWTKey, jwt.RegisteredClaims{
+			Issuer: otherGameID, Subject: player.ID, ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		}), player, false},
+		{"expired", signToken(session.guesserJWTKey, jwt.RegisteredClaims{
+			Issuer: gameID, Subject: player.ID, ExpiresAt: jwt.NewNumericDate(time.Now().Add(-time.Minute)),
+		}), player, false},
+		{"wrong key", signToken([]byte("not the key"), jwt.RegisteredClaims{
+			Issuer: gameID, Subject: player.ID, ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		}), player, false},
+		{"malformed", "not.a.token", player, false},
+		{"empty", "", player, false},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			if got := session.checkGuesserToken(testCase.token, testCase.player); got != testCase.want {
+				t.Errorf("got %v, want %v", got, testCase.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,191 @@
+package game
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// Name of the cookie holding the player identity JWT.
+	playerCookieName string = "player"
+
+	// Issuer of the player identity JWT.
+	playerJWTIssuer string = "twentyquestions"
+
+	// The duration a player identity is valid for. Reissued whenever the player changes their name.
+	playerIdentityDuration time.Duration = 365 * 24 * time.Hour
+
+	// Length of a player ID in number of runes.
+	playerIDLength int = 16
+
+	// Maximum length of a player name in runes.
+	playerNameMaxLength int = 32
+)
+
+// --------------------------------------------------------------------------------
+// JWT Data and Methods
+// --------------------------------------------------------------------------------
+
+// Identity of a player, shared across all games. Stored in the request context under "Player".
+type playerIdentity struct {
+	ID   string
+	Name string
+}
+
+// Claims stored in the player identity JWT.
+type playerClaims struct {
+	Name string `json:"name"`
+	jwt.RegisteredClaims
+}
+
+// Issues and checks player identity JWTs. One identifier is shared by the game master and all games.
+type playerIdentifier struct {
+	// Signing key for the player JWT.
+	jwtKey []byte
+}
+
+func newPlayerIdentifier(jwtKey []byte) *playerIdentifier {
+	return &playerIdentifier{
+		jwtKey: jwtKey,
+	}
+}
+
+// Check a request for the player JWT, returning the identity if present and valid.
+func (identifier *playerIdentifier) identify(r *http.Request) (playerIdentity, bool) {
+	// Ensure cookie actually exits
+
+	tokenCookie, err := r.Cookie(playerCookieName)
+	if err != nil {
+		if err == http.ErrNoCookie {
+			log.Debug().Msg("Player JWT Check - No Token Cookie")
+			return playerIdentity{}, false
+		}
+		log.Debug().Msg("Player JWT Check - Error Reading Cookie")
+		return playerIdentity{}, false
+	}
+
+	return identifier.identifyToken(tokenCookie.Value)
+}
+
+// Check a player JWT, returning the identity if it is valid -- used directly by the gRPC server, which has no cookies.
+func (identifier *playerIdentifier) identifyToken(tokenString string) (playerIdentity, bool) {
+	// Get claims from token by decoding with key
+
+	claims := &playerClaims{}
+	token, err := jwt.ParseWithClaims(tokenString, claims, func(t *jwt.Token) (interface{}, error) {
+		return identifier.jwtKey, nil
+	})
+
+	// Ensure decoding did not fail
+
+	if err != nil {
+		if err == jwt.ErrSignatureInvalid {
+			log.Debug().Msg("Player JWT Check - Invalid Signature")
+			return playerIdentity{}, false
+		}
+		log.Debug().Msg("Player JWT Check - Other Token Parse Error")
+		return playerIdentity{}, false
+	}
+
+	// Ensure the token is still valid (e.g. not expired)
+
+	if !token.Valid {
+		log.Debug().Msg("Player JWT Check - Token Invalid")
+		return playerIdentity{}, false
+	}
+
+	// Ensure claims match expected
+
+	if claims.Issuer != playerJWTIssuer || claims.Subject == "" {
+		return playerIdentity{}, false
+	}
+
+	return playerIdentity{
+		ID:   claims.Subject,
+		Name: claims.Name,
+	}, true
+}
+
+// Sign a new player JWT for the identity, returning the token and its expiry.
+func (identifier *playerIdentifier) sign(player playerIdentity) (string, time.Time, error) {
+	playerJWTExpiry := time.Now().Add(playerIdentityDuration)
+	token := jwt.NewWithClaims(jwt.SigningMethodHS512, &playerClaims{
+		Name: player.Name,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    playerJWTIssuer,
+			Subject:   player.ID,
+			ExpiresAt: jwt.NewNumericDate(playerJWTExpiry),
+		},
+	})
+	tokenString, err := token.SignedString(identifier.jwtKey)
+	return tokenString, playerJWTExpiry, err
+}
+
+// Sign a new player JWT for the identity and set it as a cookie on the response.
+func (identifier *playerIdentifier) issue(w http.ResponseWriter, player playerIdentity) {
+	tokenString, playerJWTExpiry, err := identifier.sign(player)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to sign player JWT")
+		return
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     playerCookieName,
+		Value:    tokenString,
+		Path:     "/",
+		Expires:  playerJWTExpiry,
+		HttpOnly: true,
+	})
+}
+
+// --------------------------------------------------------------------------------
+// Utility Functions
+// --------------------------------------------------------------------------------
+
+// Clean up a requested player name -- trimmed and truncated, falling back to a name derived from the ID.
+func normalizePlayerName(name string, playerID string) string {
+	name = strings.TrimSpace(truncateRunes(strings.TrimSpace(name), playerNameMaxLength))
+	if name == "" {
+		name = "Player " + playerID[:4]
+	}
+	return name
+}
+
+// Create the identity for a new player, with a random ID and the default name.
+func (master *GameMaster) newPlayerIdentity() playerIdentity {
+	playerID := master.randomString(playerIDLength)
+	return playerIdentity{
+		ID:   playerID,
+		Name: normalizePlayerName("", playerID),
+	}
+}
+
+// Get the player identity set by identifyPlayerMiddleware from the request context.
+func playerFromRequest(r *http.Request) playerIdentity {
+	return r.Context().Value("Player").(playerIdentity)
+}
+
+// --------------------------------------------------------------------------------
+// Middleware
+// --------------------------------------------------------------------------------
+
+// Middleware to identify the player making a request, issuing a new identity if they do not have one.
+// Sets a context value in the request for Player.
+func (master *GameMaster) identifyPlayerMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		player, ok := master.playerIdentifier.identify(r)
+		if !ok {
+			player = master.newPlayerIdentity()
+			master.playerIdentifier.issue(w, player)
+			log.Debug().Str("PlayerID", player.ID).Msg("New Player Identity Issued")
+		}
+
+		ctx := context.WithValue(r.Context(), "Player", player)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
@@ -0,0 +1,127 @@
+package game
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Enum for roundEventKind, determining what happened in a recorded round event.
+// String valued, as the kinds are read by the replay page.
+type roundEventKindEnum string
+
+const (
+	roundEventKind_RoundStarted      roundEventKindEnum = "roundStarted"
+	roundEventKind_QuestionAsked     roundEventKindEnum = "questionAsked"
+	roundEventKind_QuestionAnswered  roundEventKindEnum = "questionAnswered"
+	roundEventKind_HintGiven         roundEventKindEnum = "hintGiven"
+	roundEventKind_QuestionEdited    roundEventKindEnum = "questionEdited"
+	roundEventKind_QuestionRetracted roundEventKindEnum = "questionRetracted"
+	roundEventKind_AnswerAmended     roundEventKindEnum = "answerAmended"
+	roundEventKind_VerdictCorrect    roundEventKindEnum = "verdictCorrect"
+	roundEventKind_VerdictIncorrect  roundEventKindEnum = "verdictIncorrect"
+	roundEventKind_RoundEnded        roundEventKindEnum = "roundEnded"
+)
+
+// A single event in the log of a round -- enough to rebuild the game log step by step.
+type roundEvent struct {
+	Time time.Time          `json:"time"`
+	Kind roundEventKindEnum `json:"kind"`
+
+	// The player who caused the event.
+	PlayerName string `json:"playerName"`
+
+	// The position of the affected entry in the game log, and the number of the question if it is one.
+	Position int `json:"position"`
+	Index    int `json:"index,omitempty"`
+
+	// The new text of the entry -- the question, answer or hint.
+	Text string `json:"text,omitempty"`
+}
+
+// Record an event affecting the entry at the given position of the game log, and the player who caused it in the audit log.
+//
+// The caller must hold the gameStateMutex, and release it with unlockGameState.
+func (data *GameData) recordEvent(kind roundEventKindEnum, player playerIdentity, position int, text string) {
+	data.queueAudit(string(kind), player, text)
+
+	event := roundEvent{
+		Time:       time.Now(),
+		Kind:       kind,
+		PlayerName: player.Name,
+		Position:   position,
+		Text:       text,
+	}
+	if position >= 0 && position < len(data.questionAnswerPairs) {
+		event.Index = data.questionAnswerPairs[position].Index
+	}
+	data.events = append(data.events, event)
+}
+
+// Data to be passed to replay.html template
+type replayTemplateData struct {
+	GameID         string
+	RoundNumber    int
+	OracleName     string
+	VerdictCorrect bool
+	Secret         string
+
+	// The numbers of all rounds that can be replayed, for navigating between them.
+	FinishedRounds []int
+
+	Events []roundEvent
+}
+
+// Find the round to replay from the round number requested, defaulting to the latest finished round.
+// Returns nil if the round does not exist or is not over -- rounds in progress cannot be replayed.
+func (session *GameSession) replayableRound(roundParam string) (*GameData, []int) {
+	session.sessionMutex.Lock()
+	rounds := append([]*GameData(nil), session.rounds...)
+	session.sessionMutex.Unlock()
+
+	var requestedRound *GameData
+	finishedRounds := make([]int, 0, len(rounds))
+	for _, round := range rounds {
+		if !round.isGameOver() {
+			continue
+		}
+		finishedRounds = append(finishedRounds, round.roundNumber)
+		if roundParam == "" || roundParam == strconv.Itoa(round.roundNumber) {
+			requestedRound = round
+		}
+	}
+	return requestedRound, finishedRounds
+}
+
+// --------------------------------------------------------------------------------
+// Routing Functions
+// --------------------------------------------------------------------------------
+
+// Render the replay of a finished round, stepping through the recorded events with their original timing.
+func (session *GameSession) renderReplay(w http.ResponseWriter, r *http.Request) {
+	round, finishedRounds := session.replayableRound(r.FormValue("round"))
+	if round == nil {
+		http.Error(w, "replays are available once the round is over", http.StatusNotFound)
+		return
+	}
+
+	round.gameStateMutex.Lock()
+	templateData := replayTemplateData{
+		GameID:         session.gameID,
+		RoundNumber:    round.roundNumber,
+		OracleName:     round.oracleName,
+		VerdictCorrect: round.verdictCorrect,
+		Secret:         round.secret,
+		FinishedRounds: finishedRounds,
+		Events:         append([]roundEvent(nil), round.events...),
+	}
+	round.gameStateMutex.Unlock()
+
+	err := gameTemplate.ExecuteTemplate(w, "replay.html", templateData)
+	if err != nil {
+		log.Error().Str("GameID", session.gameID).Err(err).Msg("Failed to write replay template")
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
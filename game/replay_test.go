@@ -0,0 +1,138 @@
+package game
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestReplayEvents(t *testing.T) {
+	server := newTestServer(t)
+	oracle := server.newPlayer(t)
+	guesser := server.newPlayer(t)
+	gameID := oracle.createGame(url.Values{})
+	guesser.mustPost(gameID, "setName", url.Values{"name": {"Alice"}})
+	session, _ := server.master.sessionByID(gameID)
+	oracleName := session.currentRound().oracleName
+
+	oracle.mustPost(gameID, "giveHint", url.Values{"response": {"It is bigger than a breadbox"}})
+	guesser.mustPost(gameID, "submitResponse", url.Values{"response": {"Is it blue?"}})
+	guesser.mustPost(gameID, "editQuestion", url.Values{"entry": {"1"}, "response": {"Is it red?"}})
+	guesser.mustPost(gameID, "retractQuestion", url.Values{"entry": {"1"}})
+	guesser.mustPost(gameID, "submitResponse", url.Values{"response": {"Is it an animal?"}})
+	oracle.mustPost(gameID, "submitResponse", url.Values{"response": {"No"}})
+	oracle.mustPost(gameID, "amendAnswer", url.Values{"entry": {"2"}, "response": {"Yes"}})
+	if status, _ := oracle.get("/game/" + gameID + "/oracleVerdictCorrect"); status != http.StatusOK {
+		t.Fatalf("verdict responded %d", status)
+	}
+
+	// Every change is recorded in order against the entry it changed, enough to rebuild the game log step by step.
+	wantEvents := []roundEvent{
+		{Kind: roundEventKind_RoundStarted, PlayerName: oracleName, Position: -1},
+		{Kind: roundEventKind_HintGiven, PlayerName: oracleName, Position: 0, Text: "It is bigger than a breadbox"},
+		{Kind: roundEventKind_QuestionAsked, PlayerName: "Alice", Position: 1, Index: 1, Text: "Is it blue?"},
+		{Kind: roundEventKind_QuestionEdited, PlayerName: "Alice", Position: 1, Index: 1, Text: "Is it red?"},
+		{Kind: roundEventKind_QuestionRetracted, PlayerName: "Alice", Position: 1, Index: 1},
+		{Kind: roundEventKind_QuestionAsked, PlayerName: "Alice", Position: 2, Index: 1, Text: "Is it an animal?"},
+		{Kind: roundEventKind_QuestionAnswered, PlayerName: oracleName, Position: 2, Index: 1, Text: "No"},
+		{Kind: roundEventKind_AnswerAmended, PlayerName: oracleName, Position: 2, Index: 1, Text: "Yes"},
+		{Kind: roundEventKind_VerdictCorrect, PlayerName: oracleName, Position: -1},
+	}
+
+	round := session.currentRound()
+	round.gameStateMutex.Lock()
+	events := append([]roundEvent(nil), round.events...)
+	round.gameStateMutex.Unlock()
+	if len(events) != len(wantEvents) {
+		t.Fatalf("events = %+v, want %d events", events, len(wantEvents))
+	}
+	for i, event := range events {
+		want := wantEvents[i]
+		if event.Kind != want.Kind || event.PlayerName != want.PlayerName || event.Position != want.Position || event.Index != want.Index || event.Text != want.Text {
+			t.Errorf("event %d = %+v, want %+v", i, event, want)
+		}
+		if i > 0 && event.Time.Before(events[i-1].Time) {
+			t.Errorf("event %d at %v is before the event it follows", i, event.Time)
+		}
+	}
+}
+
+func TestReplayableRound(t *testing.T) {
+	server := newTestServer(t)
+	oracle := server.newPlayer(t)
+	guesser := server.newPlayer(t)
+	gameID := oracle.createGame(url.Values{})
+	guesser.join(gameID)
+	session, _ := server.master.sessionByID(gameID)
+
+	replay := func(roundParam string) (int, string) {
+		return guesser.get("/game/" + gameID + "/replay?round=" + roundParam)
+	}
+
+	if status, _ := replay(""); status != http.StatusNotFound {
+		t.Errorf("replay of a round in progress responded %d, want %d", status, http.StatusNotFound)
+	}
+
+	playRound(oracle, guesser, gameID, true)
+	guesser.mustPost(gameID, "nextRound", nil)
+
+	testCases := []struct {
+		name         string
+		roundParam   string
+		wantRound    int
+		wantFinished []int
+		wantNotFound bool
+
+		// Set if the second round is finished before the case.
+		finishSecond bool
+	}{
+		{"latest finished round", "", 1, []int{1}, false, false},
+		{"requested round", "1", 1, []int{1}, false, false},
+		{"round in progress", "2", 0, []int{1}, true, false},
+		{"missing round", "3", 0, []int{1}, true, false},
+		{"invalid round", "first", 0, []int{1}, true, false},
+		{"earlier round once another finished", "1", 1, []int{1, 2}, false, true},
+		{"latest of several finished rounds", "", 2, []int{1, 2}, false, true},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			if testCase.finishSecond && !session.currentRound().isGameOver() {
+				playRound(oracle, guesser, gameID, false)
+			}
+
+			round, finishedRounds := session.replayableRound(testCase.roundParam)
+			if (round == nil) != testCase.wantNotFound || (round != nil && round.roundNumber != testCase.wantRound) {
+				t.Errorf("got round %+v, want round %d", round, testCase.wantRound)
+			}
+			if !slices.Equal(finishedRounds, testCase.wantFinished) {
+				t.Errorf("finished rounds = %v, want %v", finishedRounds, testCase.wantFinished)
+			}
+
+			status, body := replay(testCase.roundParam)
+			if (status == http.StatusNotFound) != testCase.wantNotFound {
+				t.Fatalf("replay responded %d", status)
+			}
+			if !testCase.wantNotFound && !strings.Contains(body, "Round "+strconv.Itoa(testCase.wantRound)+" - ") {
+				t.Errorf("replay page does not show round %d", testCase.wantRound)
+			}
+		})
+	}
+}
+
+func TestReplayEventsEncoding(t *testing.T) {
+	event := roundEvent{Kind: roundEventKind_QuestionAsked, PlayerName: "Alice", Position: 1, Index: 1, Text: "Is it red?"}
+	encoded, err := json.Marshal(event)
+	if err != nil {
+		t.Fatalf("failed to encode event: %v", err)
+	}
+	for _, want := range []string{`"kind":"questionAsked"`, `"playerName":"Alice"`, `"position":1`, `"index":1`, `"text":"Is it red?"`} {
+		if !strings.Contains(string(encoded), want) {
+			t.Errorf("encoded event %s does not have %s", encoded, want)
+		}
+	}
+}
@@ -0,0 +1,218 @@
+package game
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// A change made to an entry after it was added -- kept so everyone can see what was changed, and by whom.
+type entryRevision struct {
+	Time       time.Time
+	EditorName string
+
+	// What was changed, e.g. "Answer amended".
+	Description string
+
+	// The value before the change.
+	PreviousValue string
+}
+
+// Find the question entry at a position in the round, ensuring it can be changed.
+//
+// The caller must hold the gameStateMutex.
+func (data *GameData) editableQuestionEntry(position int) (*questionAnswerPair, error) {
+	if data.gameState == gameState_GameOver {
+		return nil, errors.New("game is over")
+	}
+	if position < 0 || position >= len(data.questionAnswerPairs) {
+		return nil, errors.New("entry does not exist")
+	}
+
+	pair := &data.questionAnswerPairs[position]
+	if pair.Kind != entryKind_Question || pair.Retracted {
+		return nil, errors.New("entry is not a question")
+	}
+	return pair, nil
+}
+
+// Find the unanswered question at a position in the round, ensuring it was asked by the given player.
+//
+// The caller must hold the gameStateMutex.
+func (data *GameData) unansweredQuestionFromAsker(asker playerIdentity, position int) (*questionAnswerPair, error) {
+	pair, err := data.editableQuestionEntry(position)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only the last question can be unanswered.
+	if data.gameState != gameState_AwaitingAnswer || position != len(data.questionAnswerPairs)-1 {
+		return nil, errors.New("question has already been answered")
+	}
+	if pair.AskerID != asker.ID {
+		return nil, errors.New("question was asked by another player")
+	}
+	return pair, nil
+}
+
+// Change the text of an unanswered question. Only the asker may do this.
+func (data *GameData) editQuestion(editor playerIdentity, position int, question string) error {
+	data.gameStateMutex.Lock()
+	defer data.unlockGameState()
+
+	pair, err := data.unansweredQuestionFromAsker(editor, position)
+	if err != nil {
+		return err
+	}
+
+	pair.Revisions = append(pair.Revisions, entryRevision{
+		Time:          time.Now(),
+		EditorName:    editor.Name,
+		Description:   "Question edited",
+		PreviousValue: pair.Question,
+	})
+	pair.Question = question
+	data.recordEvent(roundEventKind_QuestionEdited, editor, position, question)
+	data.updateResponsesHTML()
+	return nil
+}
+
+// Retract an unanswered question, refunding it to the budget. Only the asker may do this.
+// The entry is kept, marked as retracted, and the game goes back to awaiting a question.
+func (data *GameData) retractQuestion(editor playerIdentity, position int) error {
+	data.gameStateMutex.Lock()
+	defer data.unlockGameState()
+
+	pair, err := data.unansweredQuestionFromAsker(editor, position)
+	if err != nil {
+		return err
+	}
+
+	pair.Revisions = append(pair.Revisions, entryRevision{
+		Time:          time.Now(),
+		EditorName:    editor.Name,
+		Description:   "Question retracted",
+		PreviousValue: pair.Question,
+	})
+	pair.Retracted = true
+	pair.Cost = 0
+	data.gameState = gameState_AwaitingQuestion
+	data.recordEvent(roundEventKind_QuestionRetracted, editor, position, "")
+	data.updateResponsesHTML()
+
+	// The computer guesser gave way to the retracted question, so it may ask again as it would after an answer.
+	if data.computerGuesser {
+		time.AfterFunc(computerGuesserQuestionDelay, data.askAsComputerGuesser)
+	}
+	return nil
+}
+
+// Change the answer to a previously answered question. Only the oracle may do this.
+func (data *GameData) amendAnswer(editor playerIdentity, position int, answer string) error {
+	data.gameStateMutex.Lock()
+	defer data.unlockGameState()
+
+	pair, err := data.editableQuestionEntry(position)
+	if err != nil {
+		return err
+	}
+	if pair.Answer == "" {
+		return errors.New("question has not been answered")
+	}
+
+	pair.Revisions = append(pair.Revisions, entryRevision{
+		Time:          time.Now(),
+		EditorName:    editor.Name,
+		Description:   "Answer amended",
+		PreviousValue: pair.Answer,
+	})
+	pair.Answer = answer
+	data.recordEvent(roundEventKind_AnswerAmended, editor, position, answer)
+	data.updateResponsesHTML()
+	return nil
+}
+
+// --------------------------------------------------------------------------------
+// Routing Functions
+// --------------------------------------------------------------------------------
+
+// Read the position of the entry and the new value from a request. The value may come from the form or an htmx prompt,
+// and is cut short like any other response.
+func revisionFromRequest(r *http.Request) (int, string, error) {
+	position, err := strconv.Atoi(r.FormValue("entry"))
+	if err != nil {
+		return 0, "", err
+	}
+
+	value := r.FormValue("response")
+	if value == "" {
+		value = r.Header.Get("HX-Prompt")
+	}
+	return position, truncateRunes(value, responseMaxLength), nil
+}
+
+// Edit an unanswered question -- only the asker may do this.
+func (data *GameData) handleEditQuestion(w http.ResponseWriter, r *http.Request) {
+	position, question, err := revisionFromRequest(r)
+	if err != nil || len(question) == 0 {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	err = data.editQuestion(playerFromRequest(r), position, question)
+	if err != nil {
+		log.Debug().Err(err).Msg("Question Not Editable!")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	data.session.broadcastResponses(data)
+}
+
+// Retract an unanswered question -- only the asker may do this.
+func (data *GameData) handleRetractQuestion(w http.ResponseWriter, r *http.Request) {
+	position, _, err := revisionFromRequest(r)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	err = data.retractQuestion(playerFromRequest(r), position)
+	if err != nil {
+		log.Debug().Err(err).Msg("Question Not Retractable!")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	data.session.broadcastResponses(data)
+}
+
+// Amend a previous answer -- only the oracle may do this.
+func (data *GameData) handleAmendAnswer(w http.ResponseWriter, r *http.Request) {
+	isOracle := r.Context().Value("IsOracle").(bool)
+	if !isOracle {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	position, answer, err := revisionFromRequest(r)
+	if err != nil || len(answer) == 0 {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	err = data.amendAnswer(playerFromRequest(r), position, answer)
+	if err != nil {
+		log.Debug().Err(err).Msg("Answer Not Amendable!")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	data.session.broadcastResponses(data)
+}
@@ -0,0 +1,208 @@
+package game
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hmcalister/twentyquestions/transcript"
+)
+
+func TestRevisionFromRequest(t *testing.T) {
+	testCases := []struct {
+		name         string
+		form         string
+		prompt       string
+		wantPosition int
+		wantValue    string
+		wantErr      bool
+	}{
+		{"form value", "entry=2&response=Is+it+big%3F", "", 2, "Is it big?", false},
+		{"prompt value", "entry=0", "No", 0, "No", false},
+		{"form value before prompt", "entry=1&response=Yes", "No", 1, "Yes", false},
+		{"no value", "entry=1", "", 1, "", false},
+		{"long value", "entry=1&response=" + strings.Repeat("a", responseMaxLength+1), "", 1, strings.Repeat("a", responseMaxLength), false},
+		{"missing entry", "response=Yes", "", 0, "", true},
+		{"invalid entry", "entry=first&response=Yes", "", 0, "", true},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			request := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(testCase.form))
+			request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			if testCase.prompt != "" {
+				request.Header.Set("HX-Prompt", testCase.prompt)
+			}
+
+			position, value, err := revisionFromRequest(request)
+			if (err != nil) != testCase.wantErr {
+				t.Fatalf("error = %v, want error %v", err, testCase.wantErr)
+			}
+			if position != testCase.wantPosition || value != testCase.wantValue {
+				t.Errorf("got entry %d with %q, want entry %d with %q", position, value, testCase.wantPosition, testCase.wantValue)
+			}
+		})
+	}
+}
+
+func TestRevisions(t *testing.T) {
+	server := newTestServer(t)
+	oracle := server.newPlayer(t)
+	alice := server.newPlayer(t)
+	bob := server.newPlayer(t)
+
+	gameID := oracle.createGame(url.Values{})
+	oracle.mustPost(gameID, "setName", url.Values{"name": {"Olive"}})
+	alice.mustPost(gameID, "setName", url.Values{"name": {"Alice"}})
+	bob.join(gameID)
+
+	revise := func(position int, value string) url.Values {
+		return url.Values{"entry": {strconv.Itoa(position)}, "response": {value}}
+	}
+
+	steps := []struct {
+		name       string
+		player     *testPlayer
+		route      string
+		form       url.Values
+		wantStatus int
+	}{
+		{"question asked", alice, "submitResponse", url.Values{"response": {"Is it blue?"}}, http.StatusOK},
+		{"another guesser edits", bob, "editQuestion", revise(0, "Is it red?"), http.StatusBadRequest},
+		{"empty edit", alice, "editQuestion", revise(0, ""), http.StatusBadRequest},
+		{"missing entry edited", alice, "editQuestion", revise(3, "Is it big?"), http.StatusBadRequest},
+		{"question edited", alice, "editQuestion", revise(0, "Is it big?"), http.StatusOK},
+		{"unanswered question amended", oracle, "amendAnswer", revise(0, "Yes"), http.StatusBadRequest},
+		{"another guesser retracts", bob, "retractQuestion", revise(0, ""), http.StatusBadRequest},
+		{"question retracted", alice, "retractQuestion", revise(0, ""), http.StatusOK},
+		{"retracted question edited", alice, "editQuestion", revise(0, "Is it small?"), http.StatusBadRequest},
+		{"retracted question retracted", alice, "retractQuestion", revise(0, ""), http.StatusBadRequest},
+		{"next question asked", alice, "submitResponse", url.Values{"response": {"Is it an animal?"}}, http.StatusOK},
+		{"question answered", oracle, "submitResponse", url.Values{"response": {"Yes"}}, http.StatusOK},
+		{"answered question edited", alice, "editQuestion", revise(1, "Is it a plant?"), http.StatusBadRequest},
+		{"answered question retracted", alice, "retractQuestion", revise(1, ""), http.StatusBadRequest},
+		{"guesser amends", alice, "amendAnswer", revise(1, "No"), http.StatusUnauthorized},
+		{"retracted question amended", oracle, "amendAnswer", revise(0, "No"), http.StatusBadRequest},
+		{"answer amended", oracle, "amendAnswer", revise(1, "No"), http.StatusOK},
+	}
+
+	for _, step := range steps {
+		status, body := step.player.post("/game/"+gameID+"/"+step.route, step.form)
+		if status != step.wantStatus {
+			t.Fatalf("%s: responded %d, want %d: %s", step.name, status, step.wantStatus, body)
+		}
+	}
+
+	state := alice.state(gameID)
+	if len(state.Round.Entries) != 2 {
+		t.Fatalf("entries = %+v, want the retracted and the answered question", state.Round.Entries)
+	}
+	// The retracted question is refunded, so only the answered question counts against the budget.
+	if state.QuestionsRemaining != questionBudgetDefault-1 {
+		t.Errorf("%d questions remaining, want %d", state.QuestionsRemaining, questionBudgetDefault-1)
+	}
+
+	testCases := []struct {
+		name          string
+		entry         transcript.Entry
+		wantQuestion  string
+		wantAnswer    string
+		wantRetracted bool
+		wantCost      int
+		wantRevisions []transcript.Revision
+	}{
+		{"retracted question", state.Round.Entries[0], "Is it big?", "", true, 0, []transcript.Revision{
+			{EditorName: "Alice", Description: "Question edited", PreviousValue: "Is it blue?"},
+			{EditorName: "Alice", Description: "Question retracted", PreviousValue: "Is it big?"},
+		}},
+		{"amended answer", state.Round.Entries[1], "Is it an animal?", "No", false, 1, []transcript.Revision{
+			{EditorName: "Olive", Description: "Answer amended", PreviousValue: "Yes"},
+		}},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			entry := testCase.entry
+			if entry.Question != testCase.wantQuestion || entry.Answer != testCase.wantAnswer || entry.Retracted != testCase.wantRetracted || entry.Cost != testCase.wantCost {
+				t.Errorf("got %q answered %q, retracted %v and cost %d, want %q answered %q, retracted %v and cost %d",
+					entry.Question, entry.Answer, entry.Retracted, entry.Cost, testCase.wantQuestion, testCase.wantAnswer, testCase.wantRetracted, testCase.wantCost)
+			}
+			if len(entry.Revisions) != len(testCase.wantRevisions) {
+				t.Fatalf("revisions = %+v, want %+v", entry.Revisions, testCase.wantRevisions)
+			}
+			for i, revision := range entry.Revisions {
+				want := testCase.wantRevisions[i]
+				if revision.EditorName != want.EditorName || revision.Description != want.Description || revision.PreviousValue != want.PreviousValue {
+					t.Errorf("revision %d = %+v, want %+v", i, revision, want)
+				}
+			}
+		})
+	}
+
+	// Nothing may be changed once the round is over.
+	if status, _ := oracle.get("/game/" + gameID + "/oracleVerdictCorrect"); status != http.StatusOK {
+		t.Fatalf("verdict responded %d", status)
+	}
+	if status, _ := oracle.post("/game/"+gameID+"/amendAnswer", revise(1, "Yes")); status != http.StatusBadRequest {
+		t.Errorf("amending after the round responded %d, want %d", status, http.StatusBadRequest)
+	}
+}
+
+func TestRetractWithComputerOracle(t *testing.T) {
+	server := newTestServer(t)
+	guesser := server.newPlayer(t)
+	gameID := guesser.createGame(url.Values{"computerOracle": {"on"}})
+	session, _ := server.master.sessionByID(gameID)
+	data := session.currentRound()
+
+	// Both questions are asked well within the answer delay, so neither has been answered yet.
+	guesser.mustPost(gameID, "submitResponse", url.Values{"response": {"Is it an animal?"}})
+	guesser.mustPost(gameID, "retractQuestion", url.Values{"entry": {"0"}})
+	guesser.mustPost(gameID, "submitResponse", url.Values{"response": {"Is it big?"}})
+
+	// The answer to the retracted question comes due first, and must not answer the question asked after it.
+	data.answerAsComputerOracle(0)
+	if state := guesser.state(gameID); state.Status != roundStatus_AwaitingAnswer {
+		t.Fatalf("status %s after the retracted question came due, want the next question still awaiting an answer", state.Status)
+	}
+
+	data.answerAsComputerOracle(1)
+	entries := guesser.state(gameID).Round.Entries
+	if len(entries) != 2 || entries[0].Answer != "" || entries[1].Answer == "" {
+		t.Errorf("entries = %+v, want only the question asked after the retraction answered", entries)
+	}
+}
+
+func TestRetractWithComputerGuesser(t *testing.T) {
+	server := newTestServer(t)
+	oracle := server.newPlayer(t)
+	guesser := server.newPlayer(t)
+	gameID := oracle.createGame(url.Values{})
+	guesser.join(gameID)
+	session, _ := server.master.sessionByID(gameID)
+
+	// The question is asked before the computer guesser, which gives way to it when its turn comes.
+	if !session.addComputerGuesser() {
+		t.Fatal("failed to add computer guesser")
+	}
+	guesser.mustPost(gameID, "submitResponse", url.Values{"response": {"Is it blue?"}})
+	time.Sleep(computerGuesserQuestionDelay + 500*time.Millisecond)
+	guesser.mustPost(gameID, "retractQuestion", url.Values{"entry": {"0"}})
+
+	deadline := time.Now().Add(3 * computerGuesserQuestionDelay)
+	for time.Now().Before(deadline) {
+		entries := guesser.state(gameID).Round.Entries
+		if len(entries) == 2 {
+			if entries[1].AskerName != computerGuesserPlayerName {
+				t.Errorf("question asked by %s, want the computer guesser", entries[1].AskerName)
+			}
+			return
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	t.Error("computer guesser did not ask after the question was retracted")
+}
@@ -0,0 +1,684 @@
+package game
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog/log"
+
+	"github.com/hmcalister/twentyquestions/wordpacks"
+)
+
+// Enum for rotationMode, determining who becomes the oracle in the next round.
+type rotationModeEnum int
+
+const (
+	// Each player is oracle in turn, in the order they joined the session.
+	rotationMode_RoundRobin rotationModeEnum = iota
+
+	// The winner of a round is the oracle of the next round -- the guesser who solved it, or the oracle if they stumped everyone.
+	rotationMode_WinnerBecomesOracle rotationModeEnum = iota
+)
+
+// Parse the rotation mode from a form value, defaulting to round robin.
+func parseRotationMode(value string) rotationModeEnum {
+	switch value {
+	case "winner":
+		return rotationMode_WinnerBecomesOracle
+	default:
+		return rotationMode_RoundRobin
+	}
+}
+
+const (
+	// Maximum length of a public game title in runes.
+	sessionTitleMaxLength int = 64
+
+	// Default and maximum number of questions guessers may ask each round.
+	questionBudgetDefault int = 20
+	questionBudgetMax     int = 100
+
+	// Default and maximum number of questions a hint costs the guessers.
+	hintCostDefault int = 1
+	hintCostMax     int = 5
+)
+
+var (
+	// The categories a public game may be listed under -- the first is the default. Categories of custom word packs are added to these.
+	LobbyCategories = []string{"Anything", "Animals", "Objects", "Famous People", "Places", "Food"}
+)
+
+// Options chosen by the creator of a session.
+type gameSessionConfig struct {
+	// How the oracle is chosen for each new round.
+	rotationMode rotationModeEnum
+
+	// If the game is listed in the public lobby, along with the title and category shown there.
+	isPublic bool
+	title    string
+	category string
+
+	// The number of questions guessers may ask each round, and the number of those questions each hint costs.
+	questionBudget int
+	hintCost       int
+
+	// If guessers propose and vote on questions, with the top proposal asked once the voting window closes.
+	votingMode   bool
+	votingWindow time.Duration
+
+	// Hash of the passphrase guessers must enter before joining, or nil if the game is not protected.
+	passphraseHash []byte
+
+	// If a secret is picked from the word packs for the oracle of each round, from the category and with this difficulty.
+	pickSecret       bool
+	secretDifficulty wordpacks.Difficulty
+
+	// If the computer oracle is the oracle of every round, with all players guessing.
+	computerOracle bool
+}
+
+// Parse the session options from the new game form, with the category one of the given categories.
+// Returns an error if the options are invalid.
+func parseGameSessionConfig(form url.Values, categories []string) (gameSessionConfig, error) {
+	config := gameSessionConfig{
+		rotationMode:   parseRotationMode(form.Get("rotation")),
+		isPublic:       form.Get("public") == "on",
+		title:          truncateRunes(strings.TrimSpace(form.Get("title")), sessionTitleMaxLength),
+		category:       LobbyCategories[0],
+		questionBudget: parseBoundedInt(form.Get("questionBudget"), questionBudgetDefault, 1, questionBudgetMax),
+		hintCost:       parseBoundedInt(form.Get("hintCost"), hintCostDefault, 0, hintCostMax),
+		votingMode:     form.Get("voting") == "on",
+		votingWindow:   parseVotingWindow(form.Get("votingWindow")),
+		pickSecret:     form.Get("pickSecret") == "on",
+		computerOracle: form.Get("computerOracle") == "on",
+	}
+
+	if config.title == "" {
+		config.title = "Twenty Questions"
+	}
+	if slices.Contains(categories, form.Get("category")) {
+		config.category = form.Get("category")
+	}
+
+	secretDifficulty, err := wordpacks.ParseDifficulty(form.Get("difficulty"))
+	if err != nil {
+		return config, err
+	}
+	config.secretDifficulty = secretDifficulty
+
+	passphraseHash, err := hashPassphrase(form.Get("passphrase"))
+	if err != nil {
+		return config, err
+	}
+	config.passphraseHash = passphraseHash
+	return config, nil
+}
+
+// --------------------------------------------------------------------------------
+// Game Session struct
+// --------------------------------------------------------------------------------
+
+// A player that has joined a session, along with their cumulative score.
+type sessionPlayer struct {
+	ID    string
+	Name  string
+	Score int
+}
+
+// A session of one or more rounds played at the same URL by the same players. Each round is a GameData.
+type GameSession struct {
+	gameID string
+
+	router *chi.Mux
+
+	// The game master this session belongs to -- used for the player identifier, sanitizer, lobby and capacity limits.
+	master *GameMaster
+
+	// Signing key for the guesser session JWT, issued once a player enters the passphrase.
+	guesserJWTKey []byte
+
+	// Options chosen by the creator of the session.
+	config gameSessionConfig
+
+	// Players in the order they joined the session -- this is the order used for round robin rotation.
+	players []*sessionPlayer
+
+	// All rounds played in this session, the last being the current round.
+	rounds []*GameData
+
+	// Mutex to ensure atomic handling of players and rounds.
+	sessionMutex sync.Mutex
+
+	// Side chat messages, bounded to the most recent chatHistoryLimit messages.
+	chatHistory []chatMessage
+
+	// If players may currently chat -- toggled by the oracle.
+	chatEnabled bool
+
+	// If the computer guesser has joined the session -- added by the oracle.
+	computerGuesser bool
+
+	// Broadcaster for all SSE clients -- connections persist across rounds.
+	broadcaster *sseBroadcaster
+
+	// Time the session was created.
+	createdAt time.Time
+
+	// IP address of the player who created the session -- used to limit the games created from one address.
+	creatorIP string
+
+	// Time the session is deleted, and the timer that deletes it -- extended by an admin.
+	// The expiry time is guarded by the sessionMutex.
+	expiresAt   time.Time
+	expiryTimer *time.Timer
+
+	// Set once an admin has ended the session -- no new rounds may be started.
+	ended bool
+
+	// Number of live connections (SSE or gRPC streams) of each player, by player ID -- guarded by the sessionMutex.
+	// Players without a connection have left, and are not chosen as the next oracle.
+	connections map[string]int
+}
+
+// Create a new game session with the creator as the oracle of the first round, including registering routes on router.
+// If the session has a computer oracle, the computer oracle is the oracle instead and the creator is the first guesser.
+func newGameSession(master *GameMaster, gameID string, creator playerIdentity, config gameSessionConfig, guesserJWTKey []byte) *GameSession {
+	session := &GameSession{
+		gameID:        gameID,
+		router:        chi.NewRouter(),
+		master:        master,
+		guesserJWTKey: guesserJWTKey,
+		config:        config,
+		players:       make([]*sessionPlayer, 0),
+		rounds:        make([]*GameData, 0),
+		chatHistory:   make([]chatMessage, 0),
+		chatEnabled:   true,
+		connections:   make(map[string]int),
+		broadcaster:   newSSEBroadcaster(master.limits.MaxConnectionsPerGame),
+		createdAt:     time.Now(),
+	}
+
+	oracle := &sessionPlayer{
+		ID:   creator.ID,
+		Name: creator.Name,
+	}
+	if config.computerOracle {
+		oracle = &sessionPlayer{
+			ID:   computerOraclePlayerID,
+			Name: computerOraclePlayerName,
+		}
+		session.players = append(session.players, &sessionPlayer{
+			ID:   creator.ID,
+			Name: creator.Name,
+		})
+	}
+	session.players = append(session.players, oracle)
+	session.rounds = append(session.rounds, newGameData(session, 1, oracle))
+
+	// Route to enter the passphrase of a protected session -- the only route available before joining.
+	session.router.Post("/"+session.gameID+"/passphrase", session.handlePassphrase)
+
+	// Routes that only look at the session -- the requesting player is not added to it.
+	session.router.Group(func(router chi.Router) {
+		router.Use(session.requirePassphraseMiddleware)
+		router.Use(session.roundRoleMiddleware)
+
+		router.Get("/"+session.gameID+"/controls", session.renderControls)
+		router.Get("/"+session.gameID+"/export", session.handleExport)
+		router.Get("/"+session.gameID+"/state", session.handleState)
+		router.Get("/"+session.gameID+"/replay", session.renderReplay)
+		router.Get("/"+session.gameID+"/webhooks", session.handleListWebhooks)
+		router.Post("/"+session.gameID+"/webhooks", session.handleAddWebhook)
+		router.Delete("/"+session.gameID+"/webhooks/{webhookID}", session.handleRemoveWebhook)
+		router.Post("/"+session.gameID+"/webhooks/{webhookID}/test", session.handleTestWebhook)
+	})
+
+	// Routes to play the game -- always ensure the requesting player may join, and has joined, the session.
+	session.router.Group(func(router chi.Router) {
+		router.Use(session.requirePassphraseMiddleware)
+		router.Use(session.joinSessionMiddleware)
+
+		router.Get("/"+session.gameID+"/", session.renderGameBase)
+		router.Get("/"+session.gameID+"/responsesSourceSSE", session.responsesSourceSSE)
+		router.Post("/"+session.gameID+"/nextRound", session.handleNextRound)
+		router.Post("/"+session.gameID+"/setName", session.handleSetName)
+		router.Post("/"+session.gameID+"/chat", session.handleChatMessage)
+		router.Post("/"+session.gameID+"/toggleChat", session.handleToggleChat)
+		router.Post("/"+session.gameID+"/addComputerGuesser", session.handleAddComputerGuesser)
+
+		router.Post("/"+session.gameID+"/submitResponse", session.forwardToCurrentRound((*GameData).handleNewResponse))
+		router.Get("/"+session.gameID+"/oracleVerdictCorrect", session.forwardToCurrentRound((*GameData).oracleVerdictCorrect))
+		router.Get("/"+session.gameID+"/oracleVerdictIncorrect", session.forwardToCurrentRound((*GameData).oracleVerdictIncorrect))
+		router.Post("/"+session.gameID+"/editQuestion", session.forwardToCurrentRound((*GameData).handleEditQuestion))
+		router.Post("/"+session.gameID+"/retractQuestion", session.forwardToCurrentRound((*GameData).handleRetractQuestion))
+		router.Post("/"+session.gameID+"/amendAnswer", session.forwardToCurrentRound((*GameData).handleAmendAnswer))
+		router.Post("/"+session.gameID+"/giveHint", session.forwardToCurrentRound((*GameData).handleGiveHint))
+		router.Post("/"+session.gameID+"/proposeQuestion", session.forwardToCurrentRound((*GameData).handleProposeQuestion))
+		router.Post("/"+session.gameID+"/upvoteProposal", session.forwardToCurrentRound((*GameData).handleUpvoteProposal))
+		router.Post("/"+session.gameID+"/takeTopProposal", session.forwardToCurrentRound((*GameData).handleTakeTopProposal))
+		router.Post("/"+session.gameID+"/setSecret", session.forwardToCurrentRound((*GameData).handleSetSecret))
+		router.Post("/"+session.gameID+"/pickSecret", session.forwardToCurrentRound((*GameData).handlePickSecret))
+	})
+
+	return session
+}
+
+// Get the round currently being played.
+func (session *GameSession) currentRound() *GameData {
+	session.sessionMutex.Lock()
+	defer session.sessionMutex.Unlock()
+	return session.rounds[len(session.rounds)-1]
+}
+
+// Find a player in the session by ID, or nil if they have not joined.
+//
+// The caller must hold the sessionMutex.
+func (session *GameSession) playerByID(playerID string) *sessionPlayer {
+	for _, player := range session.players {
+		if player.ID == playerID {
+			return player
+		}
+	}
+	return nil
+}
+
+// Add the player to the session if they are not already a member, keeping their name up to date.
+// Returns true if the player list changed.
+func (session *GameSession) joinSession(player playerIdentity) bool {
+	session.sessionMutex.Lock()
+	defer session.sessionMutex.Unlock()
+
+	existingPlayer := session.playerByID(player.ID)
+	if existingPlayer == nil {
+		session.players = append(session.players, &sessionPlayer{
+			ID:   player.ID,
+			Name: player.Name,
+		})
+		log.Debug().Str("GameID", session.gameID).Str("PlayerID", player.ID).Msg("Player Joined Session")
+		return true
+	}
+
+	if existingPlayer.Name != player.Name {
+		existingPlayer.Name = player.Name
+		return true
+	}
+	return false
+}
+
+// Count a live connection of the player, returning a function to call once the connection closes.
+func (session *GameSession) connect(playerID string) func() {
+	session.sessionMutex.Lock()
+	session.connections[playerID] += 1
+	session.sessionMutex.Unlock()
+
+	return func() {
+		session.sessionMutex.Lock()
+		defer session.sessionMutex.Unlock()
+		session.connections[playerID] -= 1
+		if session.connections[playerID] <= 0 {
+			delete(session.connections, playerID)
+		}
+	}
+}
+
+// Check if the player may be the next oracle -- they must be able to, and must still be connected.
+//
+// The caller must hold the sessionMutex.
+func (session *GameSession) canBeNextOracle(player *sessionPlayer) bool {
+	return canBeOracle(player) && session.connections[player.ID] > 0
+}
+
+// Add the player to the session, letting all clients and the lobby know if the player list changed.
+func (session *GameSession) join(player playerIdentity) {
+	if session.joinSession(player) {
+		session.broadcaster.broadcast(session.scoreboardEvent())
+		session.notifyListingChange()
+	}
+}
+
+// Determine the oracle for the round following the given round, based on the rotation mode.
+//
+// The caller must hold the sessionMutex.
+func (session *GameSession) nextOracle(previousRound *GameData) *sessionPlayer {
+	if session.config.computerOracle {
+		if computerOracle := session.playerByID(computerOraclePlayerID); computerOracle != nil {
+			return computerOracle
+		}
+	}
+
+	if session.config.rotationMode == rotationMode_WinnerBecomesOracle {
+		if winner := session.playerByID(previousRound.roundWinnerID()); winner != nil && session.canBeNextOracle(winner) {
+			return winner
+		}
+	}
+
+	// Round robin -- the player after the previous oracle in join order, wrapping around and skipping players who cannot be oracle
+	// or have left.
+	for i, player := range session.players {
+		if player.ID == previousRound.oracleID {
+			for offset := 1; offset <= len(session.players); offset++ {
+				if nextPlayer := session.players[(i+offset)%len(session.players)]; session.canBeNextOracle(nextPlayer) {
+					return nextPlayer
+				}
+			}
+		}
+	}
+
+	// Nobody who may be oracle is connected, so the previous oracle carries on rather than handing the round to a player who has left.
+	if previousOracle := session.playerByID(previousRound.oracleID); previousOracle != nil && canBeOracle(previousOracle) {
+		return previousOracle
+	}
+	return session.players[0]
+}
+
+// Start a new round with the next oracle. Returns an error if the current round is not yet over.
+func (session *GameSession) startNextRound() error {
+	session.sessionMutex.Lock()
+	defer session.sessionMutex.Unlock()
+
+	if session.ended {
+		return errors.New("game has ended")
+	}
+	previousRound := session.rounds[len(session.rounds)-1]
+	if !previousRound.isGameOver() {
+		return errors.New("current round is not over")
+	}
+
+	oracle := session.nextOracle(previousRound)
+	round := newGameData(session, len(session.rounds)+1, oracle)
+	session.rounds = append(session.rounds, round)
+	if session.computerGuesser {
+		round.startComputerGuesser()
+	}
+	log.Info().Str("GameID", session.gameID).Int("Round", len(session.rounds)).Str("OracleID", oracle.ID).Msg("New Round Started")
+	return nil
+}
+
+// Update the scoreboard once a round is over, learn from the round, and let all clients know the round has ended.
+func (session *GameSession) finishRound(round *GameData) {
+	session.sessionMutex.Lock()
+	if winner := session.playerByID(round.roundWinnerID()); winner != nil {
+		winner.Score += 1
+	}
+	session.sessionMutex.Unlock()
+	session.recordRoundStats(round)
+	session.learnFromRound(round)
+	session.saveToArchive()
+	session.publishGameOver(round)
+
+	session.broadcastResponses(round)
+	session.broadcaster.broadcast(session.scoreboardEvent())
+	session.broadcaster.broadcast(controlsEvent())
+}
+
+// Let the lobby know the listing of this session may have changed -- only public sessions are listed.
+func (session *GameSession) notifyListingChange() {
+	if session.config.isPublic {
+		session.master.broadcastLobby()
+	}
+}
+
+// Summarize the session for the public lobby.
+func (session *GameSession) listing() lobbyListing {
+	session.sessionMutex.Lock()
+	round := session.rounds[len(session.rounds)-1]
+	playerCount := len(session.players)
+	session.sessionMutex.Unlock()
+
+	return lobbyListing{
+		GameID:        session.gameID,
+		Title:         session.config.title,
+		Category:      session.config.category,
+		Locked:        session.hasPassphrase(),
+		URL:           fmt.Sprintf("/game/%s/", session.gameID),
+		Players:       playerCount,
+		Round:         round.roundNumber,
+		QuestionsUsed: round.questionsUsed(),
+		CreatedAt:     session.createdAt,
+		AgeSeconds:    int64(time.Since(session.createdAt).Seconds()),
+	}
+}
+
+// Disconnect all clients, e.g. when the session is deleted.
+func (session *GameSession) sessionCleanup() {
+	session.broadcaster.closeAll()
+}
+
+// --------------------------------------------------------------------------------
+// SSE Events
+// --------------------------------------------------------------------------------
+
+// Data to be passed to scoreboard.html template
+type scoreboardTemplateData struct {
+	RoundNumber int
+	Players     []scoreboardEntry
+}
+
+type scoreboardEntry struct {
+	Name     string
+	Score    int
+	IsOracle bool
+}
+
+// Render the cumulative scoreboard, highest score first.
+func (session *GameSession) scoreboardEvent() sseEvent {
+	session.sessionMutex.Lock()
+	round := session.rounds[len(session.rounds)-1]
+	templateData := scoreboardTemplateData{
+		RoundNumber: round.roundNumber,
+		Players:     make([]scoreboardEntry, 0, len(session.players)),
+	}
+	for _, player := range session.players {
+		templateData.Players = append(templateData.Players, scoreboardEntry{
+			Name:     player.Name,
+			Score:    player.Score,
+			IsOracle: player.ID == round.oracleID,
+		})
+	}
+	session.sessionMutex.Unlock()
+
+	sort.SliceStable(templateData.Players, func(i, j int) bool {
+		return templateData.Players[i].Score > templateData.Players[j].Score
+	})
+
+	var scoreboardBytes bytes.Buffer
+	err := gameTemplate.ExecuteTemplate(&scoreboardBytes, "scoreboard.html", templateData)
+	if err != nil {
+		log.Error().Str("GameID", session.gameID).Err(err).Msg("Failed to write scoreboard template")
+	}
+	return sseEvent{name: "scoreboard", data: scoreboardBytes.String()}
+}
+
+// Event telling clients to refetch their controls, e.g. because their role or the round state changed.
+func controlsEvent() sseEvent {
+	return sseEvent{name: "controls", data: ""}
+}
+
+// Send the responses of the round to all clients -- only if the round is still the current round.
+func (session *GameSession) broadcastResponses(round *GameData) {
+	if session.currentRound() != round {
+		return
+	}
+	session.broadcaster.broadcast(sseEvent{name: "message", data: round.responsesHTML()})
+	session.notifyListingChange()
+}
+
+// --------------------------------------------------------------------------------
+// Middleware
+// --------------------------------------------------------------------------------
+
+// Middleware to add the requesting player to the session, setting a context value in the request for IsOracle.
+func (session *GameSession) joinSessionMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		session.join(playerFromRequest(r))
+		next.ServeHTTP(w, session.currentRound().withRoundRole(r))
+	})
+}
+
+// Middleware to set a context value in the request for IsOracle, without adding the requesting player to the session.
+func (session *GameSession) roundRoleMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, session.currentRound().withRoundRole(r))
+	})
+}
+
+// Forward a request to the current round, ensuring the IsOracle context value refers to that same round.
+func (session *GameSession) forwardToCurrentRound(handler func(*GameData, http.ResponseWriter, *http.Request)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		round := session.currentRound()
+		handler(round, w, round.withRoundRole(r))
+	}
+}
+
+// --------------------------------------------------------------------------------
+// Routing Functions
+// --------------------------------------------------------------------------------
+
+// Data to be passed to gameBase.html template
+type gameBaseTemplateData struct {
+	GameID   string
+	IsOracle bool
+	Controls gameControlsTemplateData
+}
+
+// Data to be passed to gameControls.html template
+type gameControlsTemplateData struct {
+	IsOracle    bool
+	IsGameOver  bool
+	RoundNumber int
+	PlayerID    string
+	PlayerName  string
+	ChatEnabled bool
+	VotingMode  bool
+	HintCost    int
+
+	// If an admin has ended the game, so no new rounds may be started.
+	GameEnded bool
+
+	// If the oracle may add the computer guesser -- it has not yet joined, and the session is not in voting mode.
+	CanAddComputerGuesser bool
+
+	// The secret recorded by the oracle -- only set when rendering for the oracle.
+	Secret string
+
+	// Set when rendering for an htmx request, to update the role in the page title and the role style out of band.
+	UpdateRoleTitle bool
+}
+
+// Gather the data to render the controls for the requesting player.
+func (session *GameSession) controlsTemplateData(r *http.Request) gameControlsTemplateData {
+	round := session.currentRound()
+	isOracle := r.Context().Value("IsOracle").(bool)
+	templateData := gameControlsTemplateData{
+		IsOracle:    isOracle,
+		IsGameOver:  round.isGameOver(),
+		RoundNumber: round.roundNumber,
+		PlayerID:    playerFromRequest(r).ID,
+		PlayerName:  playerFromRequest(r).Name,
+		ChatEnabled: session.isChatEnabled(),
+		VotingMode:  session.config.votingMode,
+		HintCost:    session.config.hintCost,
+		GameEnded:   session.hasEnded(),
+	}
+	if isOracle {
+		templateData.CanAddComputerGuesser = !session.config.votingMode && !session.hasComputerGuesser()
+		templateData.Secret = round.recordedSecret()
+	}
+	return templateData
+}
+
+// Render the game base -- should the first call to the game router.
+func (session *GameSession) renderGameBase(w http.ResponseWriter, r *http.Request) {
+	// Turn players away early if they will not be able to connect for updates.
+	if session.broadcaster.isFull() {
+		writeCapacityRejection(w, r, rejection_GameFull)
+		return
+	}
+
+	// Render the template with the controls for this player. The responses and scoreboard are sent when the SSE connection opens.
+	err := gameTemplate.ExecuteTemplate(w, "gameBase.html", gameBaseTemplateData{
+		GameID:   session.gameID,
+		IsOracle: r.Context().Value("IsOracle").(bool),
+		Controls: session.controlsTemplateData(r),
+	})
+	if err != nil {
+		log.Error().Str("GameID", session.gameID).Err(err).Msg("Failed to write game base template")
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
+
+// Render the controls for the requesting player -- refetched by clients whenever a controls event is sent.
+func (session *GameSession) renderControls(w http.ResponseWriter, r *http.Request) {
+	templateData := session.controlsTemplateData(r)
+	templateData.UpdateRoleTitle = true
+	err := gameTemplate.ExecuteTemplate(w, "gameControls.html", templateData)
+	if err != nil {
+		log.Error().Str("GameID", session.gameID).Err(err).Msg("Failed to write game controls template")
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
+
+// SSE endpoint -- sends the current responses, scoreboard, chat and controls on connection, then all updates.
+func (session *GameSession) responsesSourceSSE(w http.ResponseWriter, r *http.Request) {
+	log.Debug().Msg("New Client SSE Connection")
+	disconnect := session.connect(playerFromRequest(r).ID)
+	defer disconnect()
+	err := session.broadcaster.serve(w, r, func() []sseEvent {
+		round := session.currentRound()
+		return []sseEvent{
+			{name: "message", data: round.responsesHTML()},
+			round.proposalsEvent(),
+			session.scoreboardEvent(),
+			session.chatEvent(),
+			controlsEvent(),
+			session.master.noticeEvent(),
+		}
+	})
+	if err == errBroadcasterFull {
+		writeCapacityRejection(w, r, rejection_GameFull)
+	}
+}
+
+// Start the next round -- any player may do this once the current round is over.
+func (session *GameSession) handleNextRound(w http.ResponseWriter, r *http.Request) {
+	err := session.startNextRound()
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	round := session.currentRound()
+	round.audit(auditAction_RoundStarted, playerFromRequest(r), "")
+	session.broadcaster.broadcast(sseEvent{name: "message", data: round.responsesHTML()})
+	session.broadcaster.broadcast(round.proposalsEvent())
+	session.broadcaster.broadcast(session.scoreboardEvent())
+	session.broadcaster.broadcast(controlsEvent())
+	session.notifyListingChange()
+}
+
+// Change the name of the requesting player, reissuing their identity so the name is kept in future games.
+func (session *GameSession) handleSetName(w http.ResponseWriter, r *http.Request) {
+	player := playerFromRequest(r)
+	player.Name = normalizePlayerName(r.FormValue("name"), player.ID)
+	session.master.playerIdentifier.issue(w, player)
+	session.currentRound().audit(auditAction_NameChanged, player, player.Name)
+
+	ctx := context.WithValue(r.Context(), "Player", player)
+	r = r.WithContext(ctx)
+	if session.joinSession(player) {
+		session.broadcaster.broadcast(session.scoreboardEvent())
+	}
+
+	session.renderControls(w, r)
+}
@@ -0,0 +1,118 @@
+package game
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestParseRotationMode(t *testing.T) {
+	testCases := []struct {
+		value string
+		want  rotationModeEnum
+	}{
+		{"", rotationMode_RoundRobin},
+		{"roundRobin", rotationMode_RoundRobin},
+		{"winner", rotationMode_WinnerBecomesOracle},
+		{"loser", rotationMode_RoundRobin},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.value, func(t *testing.T) {
+			if got := parseRotationMode(testCase.value); got != testCase.want {
+				t.Errorf("got %v, want %v", got, testCase.want)
+			}
+		})
+	}
+}
+
+func TestNextOracle(t *testing.T) {
+	testCases := []struct {
+		name     string
+		rotation string
+
+		// The players who are connected when the round ends, by name. Alice is the oracle of the first round.
+		connected  []string
+		winner     string
+		correct    bool
+		wantOracle string
+	}{
+		{"round robin", "", []string{"Alice", "Bob", "Carol"}, "Carol", true, "Bob"},
+		{"round robin skips player who left", "", []string{"Alice", "Carol"}, "Carol", true, "Carol"},
+		{"round robin skips every player who left", "", []string{"Alice"}, "Carol", true, "Alice"},
+		{"nobody connected", "", nil, "Carol", true, "Alice"},
+		{"winner becomes oracle", "winner", []string{"Alice", "Bob", "Carol"}, "Carol", true, "Carol"},
+		{"oracle wins", "winner", []string{"Alice", "Bob", "Carol"}, "Carol", false, "Alice"},
+		{"winner left", "winner", []string{"Alice", "Bob"}, "Carol", true, "Bob"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			server := newTestServer(t)
+			players := map[string]*testPlayer{
+				"Alice": server.newPlayer(t),
+				"Bob":   server.newPlayer(t),
+				"Carol": server.newPlayer(t),
+			}
+			gameID := players["Alice"].createGame(url.Values{"rotation": {testCase.rotation}})
+			for _, name := range []string{"Alice", "Bob", "Carol"} {
+				players[name].mustPost(gameID, "setName", url.Values{"name": {name}})
+			}
+			session, _ := server.master.sessionByID(gameID)
+			for _, name := range testCase.connected {
+				t.Cleanup(session.connect(players[name].id()))
+			}
+
+			playRound(players["Alice"], players[testCase.winner], gameID, testCase.correct)
+			players["Alice"].mustPost(gameID, "nextRound", nil)
+
+			if oracleID := session.currentRound().oracleID; oracleID != players[testCase.wantOracle].id() {
+				t.Errorf("oracle is %s, want %s", session.currentRound().oracleName, testCase.wantOracle)
+			}
+		})
+	}
+}
+
+func TestOracleRotation(t *testing.T) {
+	server := newTestServer(t)
+	alice := server.newPlayer(t)
+	bob := server.newPlayer(t)
+	carol := server.newPlayer(t)
+	gameID := alice.createGame(url.Values{})
+	session, _ := server.master.sessionByID(gameID)
+	for name, player := range map[string]*testPlayer{"Alice": alice, "Bob": bob, "Carol": carol} {
+		player.mustPost(gameID, "setName", url.Values{"name": {name}})
+		t.Cleanup(session.connect(player.id()))
+	}
+
+	// Each round is won by the player before the oracle in join order, so Carol wins the first and last rounds.
+	rounds := []struct {
+		oracle *testPlayer
+		winner *testPlayer
+	}{
+		{alice, carol},
+		{bob, alice},
+		{carol, bob},
+		{alice, carol},
+	}
+	for i, round := range rounds {
+		if oracleID := session.currentRound().oracleID; oracleID != round.oracle.id() {
+			t.Fatalf("round %d oracle is %s, want the next player in join order", i+1, session.currentRound().oracleName)
+		}
+		playRound(round.oracle, round.winner, gameID, true)
+		round.oracle.mustPost(gameID, "nextRound", nil)
+	}
+
+	wantScores := map[string]int{"Alice": 1, "Bob": 1, "Carol": 2}
+	state := bob.state(gameID)
+	if len(state.Players) != len(wantScores) {
+		t.Fatalf("players = %+v, want every player on the scoreboard", state.Players)
+	}
+	if state.Players[0].Name != "Carol" {
+		t.Errorf("scoreboard led by %s, want the player with the highest score", state.Players[0].Name)
+	}
+	for _, player := range state.Players {
+		if player.Score != wantScores[player.Name] {
+			t.Errorf("%s has score %d, want %d", player.Name, player.Score, wantScores[player.Name])
+		}
+	}
+}
@@ -0,0 +1,215 @@
+package game
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// Number of events that may be queued for a single client before broadcasts block on that client.
+	sseClientBufferSize int = 16
+)
+
+// A single named Server Sent Event. The name is used by htmx (sse-swap / hx-trigger="sse:name") to route the data.
+type sseEvent struct {
+	name string
+	data string
+}
+
+// Server Sent Event client -- to return the events to the clients as they happen.
+type sseClient struct {
+	// Context, with cancel indicating if the client has left.
+	context context.Context
+
+	cancelFunc context.CancelFunc
+
+	// Channel to write events back to the client. Buffered, so a broadcast does not wait on a single slow client.
+	eventsChannel chan sseEvent
+
+	// Set for admins watching a game -- they are not counted as clients, so never take the place of a player.
+	spectator bool
+}
+
+var (
+	// Returned when a client cannot be served because the broadcaster already has the maximum number of clients.
+	errBroadcasterFull = errors.New("broadcaster has maximum number of clients")
+)
+
+// Collection of SSE clients that all receive the same events.
+type sseBroadcaster struct {
+	// Array of all sseClients -- pruned of closed clients when next event is sent.
+	clients []*sseClient
+
+	// Maximum number of connected clients, or zero for no limit.
+	maxClients int
+
+	// Mutex to ensure atomic handling of SSE clients -- we don't want to accidentally miss a client!
+	clientsMutex sync.Mutex
+}
+
+func newSSEBroadcaster(maxClients int) *sseBroadcaster {
+	return &sseBroadcaster{
+		clients:    make([]*sseClient, 0),
+		maxClients: maxClients,
+	}
+}
+
+// Write a single event to the response writer in the SSE wire format. Multi-line data is split over several data fields.
+func writeSSEEvent(w http.ResponseWriter, event sseEvent) {
+	if event.name != "" {
+		fmt.Fprintf(w, "event: %s\n", event.name)
+	}
+	for _, line := range strings.Split(event.data, "\n") {
+		fmt.Fprintf(w, "data: %s\n", line)
+	}
+	fmt.Fprint(w, "\n")
+}
+
+// Send an event to all clients, splicing out any clients that have left.
+func (broadcaster *sseBroadcaster) broadcast(event sseEvent) {
+	broadcaster.clientsMutex.Lock()
+	defer broadcaster.clientsMutex.Unlock()
+
+	// Note this loop does NOT always increment i, as sometimes we splice out a done client and must repeat that index.
+	// If we splice out the last client, the i will now be equal to len(clients) so the loop will terminate, not overrun its bounds
+	for i := 0; i < len(broadcaster.clients); {
+		currentClient := broadcaster.clients[i]
+		select {
+		case <-currentClient.context.Done():
+			// Splice out the done client with the end client. Then remove the end client.
+			// This requires us to look at the current index again, so don't update i.
+			broadcaster.clients[i] = broadcaster.clients[len(broadcaster.clients)-1]
+			broadcaster.clients = broadcaster.clients[:len(broadcaster.clients)-1]
+		case currentClient.eventsChannel <- event:
+			i += 1
+		}
+	}
+}
+
+// Number of clients currently connected -- clients that have left but are not yet pruned are not counted.
+func (broadcaster *sseBroadcaster) clientCount() int {
+	broadcaster.clientsMutex.Lock()
+	defer broadcaster.clientsMutex.Unlock()
+	return broadcaster.activeClientCount()
+}
+
+// Check if the broadcaster has the maximum number of clients connected.
+func (broadcaster *sseBroadcaster) isFull() bool {
+	return limitReached(broadcaster.maxClients, broadcaster.clientCount())
+}
+
+// Count the clients that have not left, other than spectators.
+//
+// The caller must hold the clientsMutex.
+func (broadcaster *sseBroadcaster) activeClientCount() int {
+	count := 0
+	for _, currentClient := range broadcaster.clients {
+		if currentClient.context.Err() == nil && !currentClient.spectator {
+			count += 1
+		}
+	}
+	return count
+}
+
+// Disconnect all clients, e.g. when the game is deleted.
+func (broadcaster *sseBroadcaster) closeAll() {
+	broadcaster.clientsMutex.Lock()
+	defer broadcaster.clientsMutex.Unlock()
+
+	for _, currentClient := range broadcaster.clients {
+		currentClient.cancelFunc()
+	}
+	broadcaster.clients = make([]*sseClient, 0)
+}
+
+// Add a new client receiving every broadcast event until the context is done, or the client is cancelled.
+// The caller must call the cancelFunc of the client once it is finished with it.
+//
+// Returns errBroadcasterFull if the maximum number of clients are already connected.
+func (broadcaster *sseBroadcaster) subscribe(ctx context.Context) (*sseClient, error) {
+	return broadcaster.addClient(ctx, false)
+}
+
+// Add a new client as subscribe does, but as a spectator that is neither limited by nor counted towards the maximum number of clients.
+func (broadcaster *sseBroadcaster) subscribeSpectator(ctx context.Context) *sseClient {
+	newClient, _ := broadcaster.addClient(ctx, true)
+	return newClient
+}
+
+// Add a new client to the broadcaster. Spectators are always added.
+func (broadcaster *sseBroadcaster) addClient(ctx context.Context, spectator bool) (*sseClient, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	newClient := &sseClient{
+		context:       ctx,
+		cancelFunc:    cancel,
+		eventsChannel: make(chan sseEvent, sseClientBufferSize),
+		spectator:     spectator,
+	}
+
+	// Atomically check capacity and add the new client to the clients list -- mutex avoids appending to list while splicing out list in broadcast.
+	broadcaster.clientsMutex.Lock()
+	defer broadcaster.clientsMutex.Unlock()
+	if !spectator && limitReached(broadcaster.maxClients, broadcaster.activeClientCount()) {
+		cancel()
+		return nil, errBroadcasterFull
+	}
+	broadcaster.clients = append(broadcaster.clients, newClient)
+	log.Debug().Int("Total Clients", len(broadcaster.clients)).Msg("New Client Added")
+	return newClient, nil
+}
+
+// Serve an SSE connection, blocking until the client leaves or the broadcaster is closed.
+//
+// initialEvents is called after the client is registered, so no broadcast can fall between the initial state and the first update.
+// Returns errBroadcasterFull without writing to the response if the maximum number of clients are already connected.
+func (broadcaster *sseBroadcaster) serve(w http.ResponseWriter, r *http.Request, initialEvents func() []sseEvent) error {
+	return broadcaster.serveClient(w, r, false, initialEvents)
+}
+
+// Serve an SSE connection as serve does, but as a spectator that is neither limited by nor counted towards the maximum number of clients.
+func (broadcaster *sseBroadcaster) serveSpectator(w http.ResponseWriter, r *http.Request, initialEvents func() []sseEvent) {
+	broadcaster.serveClient(w, r, true, initialEvents)
+}
+
+// Serve an SSE connection for a player or a spectator.
+func (broadcaster *sseBroadcaster) serveClient(w http.ResponseWriter, r *http.Request, spectator bool, initialEvents func() []sseEvent) error {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		log.Error().Msg("Response writer does not support flushing, cannot serve SSE")
+		w.WriteHeader(http.StatusInternalServerError)
+		return nil
+	}
+
+	newClient, err := broadcaster.addClient(r.Context(), spectator)
+	if err != nil {
+		return err
+	}
+	defer newClient.cancelFunc()
+	ctx := newClient.context
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+
+	for _, event := range initialEvents() {
+		writeSSEEvent(w, event)
+	}
+	flusher.Flush()
+
+	// Block return until the client leaves -- ensures the client only makes ONE connection, rather than continually polling.
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event := <-newClient.eventsChannel:
+			writeSSEEvent(w, event)
+			flusher.Flush()
+		}
+	}
+}
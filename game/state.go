@@ -0,0 +1,157 @@
+package game
+
+import (
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/hmcalister/twentyquestions/transcript"
+)
+
+// Statuses of the current round of a game.
+const (
+	roundStatus_AwaitingQuestion = "awaitingQuestion"
+	roundStatus_AwaitingAnswer   = "awaitingAnswer"
+	roundStatus_Over             = "over"
+)
+
+// Roles a player may have in the current round of a game.
+const (
+	playerRole_Oracle  = "oracle"
+	playerRole_Guesser = "guesser"
+)
+
+// The state of a game as a player sees it, so clients need not parse the rendered HTML sent in events.
+// The secret is only included for the oracle until the round is over, the same as on the web.
+type gameState struct {
+	GameID   string `json:"gameID"`
+	Title    string `json:"title,omitempty"`
+	Category string `json:"category,omitempty"`
+
+	// The role of the requesting player in the current round.
+	Role string `json:"role"`
+
+	// One of roundStatus_AwaitingQuestion, roundStatus_AwaitingAnswer or roundStatus_Over.
+	Status             string `json:"status"`
+	QuestionsRemaining int    `json:"questionsRemaining"`
+
+	// If an admin has ended the game, so no new rounds may be started.
+	GameEnded bool `json:"gameEnded"`
+
+	// The cumulative scores, highest first.
+	Players []gameStatePlayer `json:"players"`
+
+	Round transcript.Round `json:"round"`
+
+	ChatEnabled bool                   `json:"chatEnabled"`
+	Chat        []gameStateChatMessage `json:"chat"`
+
+	// The maintenance notice set by an admin, if any.
+	Notice string `json:"notice,omitempty"`
+}
+
+// A player of a game and their score.
+type gameStatePlayer struct {
+	Name     string `json:"name"`
+	Score    int    `json:"score"`
+	IsOracle bool   `json:"isOracle"`
+
+	// Set for the requesting player.
+	IsYou bool `json:"isYou"`
+}
+
+// A message in the side chat of a game.
+type gameStateChatMessage struct {
+	PlayerName string    `json:"playerName"`
+	Message    string    `json:"message"`
+	SentAt     time.Time `json:"sentAt"`
+}
+
+// Snapshot the game as the player may see it.
+func (session *GameSession) state(player playerIdentity) gameState {
+	round := session.currentRound()
+	isOracle := round.oracleID == player.ID
+	state := gameState{
+		GameID:   session.gameID,
+		Title:    session.config.title,
+		Category: session.config.category,
+		Role:     playerRole_Guesser,
+		Status:   roundStatus_AwaitingQuestion,
+		Players:  make([]gameStatePlayer, 0),
+		Round:    round.transcriptRound(isOracle),
+		Chat:     make([]gameStateChatMessage, 0),
+	}
+	if isOracle {
+		state.Role = playerRole_Oracle
+	}
+
+	session.sessionMutex.Lock()
+	for _, sessionPlayer := range session.players {
+		state.Players = append(state.Players, gameStatePlayer{
+			Name:     sessionPlayer.Name,
+			Score:    sessionPlayer.Score,
+			IsOracle: sessionPlayer.ID == round.oracleID,
+			IsYou:    sessionPlayer.ID == player.ID,
+		})
+	}
+	if winner := session.playerByID(round.roundWinnerID()); winner != nil {
+		state.Round.WinnerName = winner.Name
+	}
+	state.GameEnded = session.ended
+	state.ChatEnabled = session.chatEnabled
+	for _, message := range session.chatHistory {
+		state.Chat = append(state.Chat, gameStateChatMessage(message))
+	}
+	session.sessionMutex.Unlock()
+	sort.SliceStable(state.Players, func(i, j int) bool {
+		return state.Players[i].Score > state.Players[j].Score
+	})
+
+	session.master.noticeMutex.Lock()
+	state.Notice = session.master.notice
+	session.master.noticeMutex.Unlock()
+
+	budgetUsed := 0
+	for _, entry := range state.Round.Entries {
+		budgetUsed += entry.Cost
+	}
+	state.QuestionsRemaining = max(session.config.questionBudget-budgetUsed, 0)
+
+	// The round awaits an answer while its last entry is an unanswered question that has not been retracted.
+	if entryCount := len(state.Round.Entries); entryCount > 0 {
+		lastEntry := state.Round.Entries[entryCount-1]
+		if lastEntry.Kind == transcript.EntryKind_Question && !lastEntry.Retracted && lastEntry.AnsweredAt == nil {
+			state.Status = roundStatus_AwaitingAnswer
+		}
+	}
+	if state.Round.Outcome != transcript.Outcome_InProgress {
+		state.Status = roundStatus_Over
+	}
+	return state
+}
+
+// --------------------------------------------------------------------------------
+// Routing Functions
+// --------------------------------------------------------------------------------
+
+// Write the state of the game as the requesting player sees it.
+func (session *GameSession) handleState(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, session.state(playerFromRequest(r)))
+}
+
+// Get the state of a game as the requesting player sees it. Games with a passphrase need it to have been entered.
+func (master *GameMaster) apiGameState(w http.ResponseWriter, r *http.Request) {
+	session, ok := master.sessionByID(chi.URLParam(r, "gameID"))
+	if !ok {
+		writeJSONError(w, http.StatusNotFound, "game does not exist")
+		return
+	}
+	if session.hasPassphrase() && !session.checkRequestAdmitted(r) {
+		writeJSONError(w, http.StatusUnauthorized, "passphrase required")
+		return
+	}
+
+	session.handleState(w, r)
+}
@@ -0,0 +1,157 @@
+package game
+
+import (
+	"html/template"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog/log"
+
+	"github.com/hmcalister/twentyquestions/stats"
+	"github.com/hmcalister/twentyquestions/templates"
+)
+
+const (
+	// Maximum number of players shown on a leaderboard.
+	leaderboardLimit int = 50
+)
+
+var (
+	// Template for the leaderboard page.
+	leaderboardTemplate = template.Must(template.New("leaderboard.html").Funcs(template.FuncMap{
+		"rank": leaderboardRank,
+	}).ParseFS(templates.Files, "leaderboard.html"))
+)
+
+// Get the rank shown on the leaderboard from the position of a player, starting at 1.
+func leaderboardRank(position int) int {
+	return position + 1
+}
+
+// Summarize a finished round for the player statistics. The guessers are all players in the session other than the oracle.
+func (data *GameData) roundResult(guessers []stats.Player) stats.RoundResult {
+	data.gameStateMutex.Lock()
+	defer data.gameStateMutex.Unlock()
+
+	result := stats.RoundResult{
+		EndedAt:         data.endedAt,
+		Oracle:          stats.Player{ID: data.oracleID, Name: data.oracleName},
+		Guessers:        guessers,
+		QuestionsAsked:  data.questionEntryCount(),
+		AnswerDurations: make([]time.Duration, 0, len(data.questionAnswerPairs)),
+	}
+	if data.verdictCorrect {
+		result.WinnerID = data.winnerID
+	}
+	for _, pair := range data.questionAnswerPairs {
+		if !pair.AnsweredAt.IsZero() {
+			result.AnswerDurations = append(result.AnswerDurations, pair.AnsweredAt.Sub(pair.AskedAt))
+		}
+	}
+	return result
+}
+
+// Check if a player is one the computer plays, and so is left out of the player statistics.
+func isComputerPlayer(playerID string) bool {
+	return playerID == computerOraclePlayerID || playerID == computerGuesserPlayerID
+}
+
+// Record the result of a finished round in the player statistics. The computer oracle and computer guesser are left out,
+// so they never appear on the leaderboards.
+func (session *GameSession) recordRoundStats(round *GameData) {
+	session.sessionMutex.Lock()
+	guessers := make([]stats.Player, 0, len(session.players))
+	for _, player := range session.players {
+		if player.ID != round.oracleID && !isComputerPlayer(player.ID) {
+			guessers = append(guessers, stats.Player{ID: player.ID, Name: player.Name})
+		}
+	}
+	session.sessionMutex.Unlock()
+
+	result := round.roundResult(guessers)
+	if isComputerPlayer(result.Oracle.ID) {
+		result.Oracle = stats.Player{}
+	}
+	err := session.master.stats.RecordRound(result)
+	if err != nil {
+		log.Error().Str("GameID", session.gameID).Err(err).Msg("Failed to save round statistics")
+	}
+}
+
+// --------------------------------------------------------------------------------
+// Routing Functions
+// --------------------------------------------------------------------------------
+
+// Data to be passed to leaderboard.html template
+type leaderboardTemplateData struct {
+	Window      string
+	Metric      string
+	Leaderboard []stats.PlayerStats
+
+	// The statistics of the requesting player, if they have played within the window.
+	PlayerStats    stats.PlayerStats
+	HasPlayerStats bool
+}
+
+// Parse the time window and metric of a leaderboard request.
+func leaderboardOptions(r *http.Request) (stats.Window, stats.Metric, error) {
+	window, err := stats.ParseWindow(r.FormValue("window"))
+	if err != nil {
+		return window, 0, err
+	}
+	metric, err := stats.ParseMetric(r.FormValue("sort"))
+	return window, metric, err
+}
+
+// Render the leaderboard for a time window, ranked by the requested metric.
+func (master *GameMaster) renderLeaderboard(w http.ResponseWriter, r *http.Request) {
+	window, metric, err := leaderboardOptions(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	templateData := leaderboardTemplateData{
+		Window:      window.String(),
+		Metric:      metric.String(),
+		Leaderboard: master.stats.Leaderboard(window, metric, leaderboardLimit),
+	}
+	templateData.PlayerStats, templateData.HasPlayerStats = master.stats.Player(playerFromRequest(r).ID, window)
+
+	err = leaderboardTemplate.Execute(w, templateData)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to write leaderboard template")
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
+
+// JSON endpoint for the leaderboard.
+func (master *GameMaster) apiLeaderboard(w http.ResponseWriter, r *http.Request) {
+	window, metric, err := leaderboardOptions(r)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, master.stats.Leaderboard(window, metric, leaderboardLimit))
+}
+
+// JSON endpoint for the statistics of a single player. The player ID "me" refers to the requesting player.
+func (master *GameMaster) apiPlayerStats(w http.ResponseWriter, r *http.Request) {
+	window, err := stats.ParseWindow(r.FormValue("window"))
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	playerID := chi.URLParam(r, "playerID")
+	if playerID == "me" {
+		playerID = playerFromRequest(r).ID
+	}
+	playerStats, ok := master.stats.Player(playerID, window)
+	if !ok {
+		writeJSONError(w, http.StatusNotFound, "player has not played in this time window")
+		return
+	}
+	writeJSON(w, http.StatusOK, playerStats)
+}
@@ -0,0 +1,44 @@
+package game
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/hmcalister/twentyquestions/stats"
+)
+
+func TestComputerPlayersLeftOutOfStats(t *testing.T) {
+	testCases := []struct {
+		name        string
+		options     url.Values
+		addGuesser  bool
+		wantPlayers int
+	}{
+		{"computer oracle", url.Values{"computerOracle": {"on"}}, false, 1},
+		{"computer guesser", url.Values{}, true, 1},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			server := newTestServer(t)
+			creator := server.newPlayer(t)
+			gameID := creator.createGame(testCase.options)
+			session, _ := server.master.sessionByID(gameID)
+			if testCase.addGuesser && !session.addComputerGuesser() {
+				t.Fatal("failed to add computer guesser")
+			}
+
+			session.recordRoundStats(session.currentRound())
+
+			leaderboard := server.master.stats.Leaderboard(stats.Window_All, stats.Metric_GamesPlayed, leaderboardLimit)
+			for _, playerStats := range leaderboard {
+				if isComputerPlayer(playerStats.PlayerID) {
+					t.Errorf("%s is on the leaderboard", playerStats.PlayerID)
+				}
+			}
+			if len(leaderboard) != testCase.wantPlayers {
+				t.Errorf("leaderboard has %d players, want %d", len(leaderboard), testCase.wantPlayers)
+			}
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package game
+
+import (
+	"expvar"
+	"time"
+)
+
+var (
+	// Totals of how long oracles take to answer, and how long rounds last, across all games.
+	// Averages are found by dividing the seconds by the respective count.
+	gameTimings = expvar.NewMap("gameTimings")
+)
+
+// Record how long the oracle took to answer a question.
+func recordAnswerTiming(answerDuration time.Duration) {
+	gameTimings.Add("answers", 1)
+	gameTimings.AddFloat("answerSecondsTotal", answerDuration.Seconds())
+}
+
+// Record how long a round lasted, from the start of the round until the verdict.
+func recordRoundTiming(roundDuration time.Duration) {
+	gameTimings.Add("rounds", 1)
+	gameTimings.AddFloat("roundSecondsTotal", roundDuration.Seconds())
+}
+
+// Format the time between two events as a short duration, e.g. "1m5s" -- used by the templates.
+// Returns an empty string if either event has not happened.
+func formatElapsed(from time.Time, to time.Time) string {
+	if from.IsZero() || to.IsZero() {
+		return ""
+	}
+	return to.Sub(from).Round(time.Second).String()
+}
+
+// Format a time for the datetime attribute of a time element.
+func formatTimestamp(timestamp time.Time) string {
+	return timestamp.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,93 @@
+package game
+
+import (
+	"expvar"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestFormatElapsed(t *testing.T) {
+	start := time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		name string
+		from time.Time
+		to   time.Time
+		want string
+	}{
+		{"not started", time.Time{}, start, ""},
+		{"not happened", start, time.Time{}, ""},
+		{"seconds", start, start.Add(1400 * time.Millisecond), "1s"},
+		{"rounded up", start, start.Add(1500 * time.Millisecond), "2s"},
+		{"minutes", start, start.Add(65 * time.Second), "1m5s"},
+		{"hours", start, start.Add(time.Hour + 2*time.Minute), "1h2m0s"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			if got := formatElapsed(testCase.from, testCase.to); got != testCase.want {
+				t.Errorf("got %q, want %q", got, testCase.want)
+			}
+		})
+	}
+}
+
+func TestFormatTimestamp(t *testing.T) {
+	timestamp := time.Date(2024, time.March, 1, 12, 30, 0, 0, time.FixedZone("NZDT", 13*60*60))
+	if got, want := formatTimestamp(timestamp), "2024-02-29T23:30:00Z"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+// Get the current value of a counter of gameTimings, or zero if it has never been recorded.
+func gameTimingsCount(key string) int64 {
+	if count, ok := gameTimings.Get(key).(*expvar.Int); ok {
+		return count.Value()
+	}
+	return 0
+}
+
+func TestAnswerTiming(t *testing.T) {
+	server := newTestServer(t)
+	oracle := server.newPlayer(t)
+	guesser := server.newPlayer(t)
+	gameID := oracle.createGame(url.Values{})
+	guesser.join(gameID)
+
+	answersBefore, roundsBefore := gameTimingsCount("answers"), gameTimingsCount("rounds")
+	guesser.mustPost(gameID, "submitResponse", url.Values{"response": {"Is it an animal?"}})
+	time.Sleep(50 * time.Millisecond)
+	oracle.mustPost(gameID, "submitResponse", url.Values{"response": {"Yes"}})
+	guesser.mustPost(gameID, "submitResponse", url.Values{"response": {"Is it a whale?"}})
+
+	// The second question is still awaiting an answer, so it has no answer time.
+	round := guesser.state(gameID).Round
+	answered, unanswered := round.Entries[0], round.Entries[1]
+	if answered.AnsweredAt == nil || answered.AnswerDuration() < 50*time.Millisecond {
+		t.Errorf("answered question timed as %v, want at least the time before the answer", answered.AnswerDuration())
+	}
+	if answered.AskedAt.Before(round.StartedAt) || unanswered.AskedAt.Before(*answered.AnsweredAt) {
+		t.Errorf("questions asked at %v and %v, want after the round started at %v and in order", answered.AskedAt, unanswered.AskedAt, round.StartedAt)
+	}
+	if unanswered.AnsweredAt != nil || round.EndedAt != nil {
+		t.Errorf("unanswered question answered at %v in round ended at %v, want neither", unanswered.AnsweredAt, round.EndedAt)
+	}
+
+	oracle.mustPost(gameID, "submitResponse", url.Values{"response": {"Yes"}})
+	if status, _ := oracle.get("/game/" + gameID + "/oracleVerdictCorrect"); status != http.StatusOK {
+		t.Fatalf("verdict responded %d", status)
+	}
+	round = guesser.state(gameID).Round
+	if round.EndedAt == nil || round.Duration() < answered.AnswerDuration() {
+		t.Errorf("round timed as %v, want at least as long as its answers", round.Duration())
+	}
+
+	// Both answers and the round are added to the totals across all games.
+	if answers := gameTimingsCount("answers") - answersBefore; answers != 2 {
+		t.Errorf("%d answers timed, want 2", answers)
+	}
+	if rounds := gameTimingsCount("rounds") - roundsBefore; rounds != 1 {
+		t.Errorf("%d rounds timed, want 1", rounds)
+	}
+}
@@ -0,0 +1,269 @@
+package game
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// Default and bounds of the voting window, from the first proposal until the top question is asked.
+	votingWindowDefault time.Duration = 60 * time.Second
+	votingWindowMin     time.Duration = 10 * time.Second
+	votingWindowMax     time.Duration = 10 * time.Minute
+
+	// Maximum number of open proposals from a single guesser, so one player cannot flood the vote.
+	proposalsPerPlayerMax int = 3
+)
+
+// Parse the voting window from a form value in seconds, clamped to sensible bounds.
+func parseVotingWindow(value string) time.Duration {
+	seconds, err := strconv.Atoi(value)
+	if err != nil {
+		return votingWindowDefault
+	}
+	return min(max(time.Duration(seconds)*time.Second, votingWindowMin), votingWindowMax)
+}
+
+// A candidate question proposed by a guesser in voting mode.
+type questionProposal struct {
+	ID int
+
+	// The player who proposed the question -- asks the question if it wins the vote.
+	ProposerID   string
+	ProposerName string
+
+	Question string
+
+	// The players who have voted for this proposal, including the proposer.
+	voterIDs map[string]bool
+}
+
+// Data to be passed to proposals.html template
+type proposalsTemplateData struct {
+	VotingWindowSeconds int
+	Proposals           []proposalView
+}
+
+type proposalView struct {
+	ID           int
+	ProposerName string
+	Question     string
+	Votes        int
+}
+
+// Add a candidate question, opening a voting window if one is not already open.
+// Returns an error if the game is not currently awaiting a question, or if the proposer already has too many open proposals.
+func (data *GameData) addProposal(proposer playerIdentity, question string) error {
+	data.gameStateMutex.Lock()
+	defer data.unlockGameState()
+
+	if data.gameState != gameState_AwaitingQuestion {
+		return errors.New("not currently awaiting question")
+	}
+	if data.budgetUsed() >= data.session.config.questionBudget {
+		return errors.New("no questions remaining")
+	}
+	proposalCount := 0
+	for _, proposal := range data.proposals {
+		if proposal.ProposerID == proposer.ID {
+			proposalCount += 1
+		}
+	}
+	if proposalCount >= proposalsPerPlayerMax {
+		return errors.New("too many proposals")
+	}
+
+	data.nextProposalID += 1
+	data.proposals = append(data.proposals, &questionProposal{
+		ID:           data.nextProposalID,
+		ProposerID:   proposer.ID,
+		ProposerName: proposer.Name,
+		Question:     question,
+		voterIDs:     map[string]bool{proposer.ID: true},
+	})
+	data.queueAudit(auditAction_QuestionProposed, proposer, question)
+
+	// The first proposal opens the voting window. The window number ensures a late timer cannot close a later window.
+	if data.votingTimer == nil {
+		votingWindowNumber := data.votingWindowNumber
+		data.votingTimer = time.AfterFunc(data.session.config.votingWindow, func() {
+			data.closeVotingWindow(votingWindowNumber)
+		})
+	}
+	return nil
+}
+
+// Add a vote for a proposal. Voting for the same proposal twice has no effect.
+// Returns an error if the proposal does not exist (e.g. the voting window has closed).
+func (data *GameData) upvoteProposal(voter playerIdentity, proposalID int) error {
+	data.gameStateMutex.Lock()
+	defer data.unlockGameState()
+
+	for _, proposal := range data.proposals {
+		if proposal.ID == proposalID {
+			proposal.voterIDs[voter.ID] = true
+			data.queueAudit(auditAction_ProposalUpvoted, voter, strconv.Itoa(proposalID))
+			return nil
+		}
+	}
+	return errors.New("proposal does not exist")
+}
+
+// Get the proposal with the most votes -- the earliest proposal wins a tie.
+// Returns false if there are no proposals, or if the given voting window has already closed.
+//
+// The caller must hold the gameStateMutex.
+func (data *GameData) topProposal(votingWindowNumber int) (*questionProposal, bool) {
+	if votingWindowNumber != data.votingWindowNumber || len(data.proposals) == 0 {
+		return nil, false
+	}
+
+	topProposal := data.proposals[0]
+	for _, proposal := range data.proposals[1:] {
+		if len(proposal.voterIDs) > len(topProposal.voterIDs) {
+			topProposal = proposal
+		}
+	}
+	return topProposal, true
+}
+
+// Discard all proposals and close the voting window.
+//
+// The caller must hold the gameStateMutex.
+func (data *GameData) clearProposals() {
+	if data.votingTimer != nil {
+		data.votingTimer.Stop()
+		data.votingTimer = nil
+	}
+	data.votingWindowNumber += 1
+	data.proposals = make([]*questionProposal, 0)
+}
+
+// Get the number of the currently open (or next) voting window.
+func (data *GameData) currentVotingWindowNumber() int {
+	data.gameStateMutex.Lock()
+	defer data.gameStateMutex.Unlock()
+	return data.votingWindowNumber
+}
+
+// Close the voting window, asking the top proposal like any other question.
+// The proposals are only discarded once the top proposal is asked -- otherwise they are kept, and the next proposal opens a new window.
+// Returns false if there was no proposal to ask, or if it could not be asked.
+func (data *GameData) closeVotingWindow(votingWindowNumber int) bool {
+	data.gameStateMutex.Lock()
+	topProposal, ok := data.topProposal(votingWindowNumber)
+	if !ok {
+		data.gameStateMutex.Unlock()
+		return false
+	}
+
+	log.Debug().Str("GameID", data.gameID).Int("Votes", len(topProposal.voterIDs)).Msg("Voting Window Closed")
+	err := data.askQuestion(playerIdentity{ID: topProposal.ProposerID, Name: topProposal.ProposerName}, topProposal.Question)
+	if err == nil {
+		data.clearProposals()
+	} else {
+		log.Debug().Str("GameID", data.gameID).Err(err).Msg("Top Proposal Could Not Be Asked")
+		if data.votingTimer != nil {
+			data.votingTimer.Stop()
+			data.votingTimer = nil
+		}
+	}
+	data.unlockGameState()
+
+	data.session.broadcaster.broadcast(data.proposalsEvent())
+	data.session.broadcastResponses(data)
+	return err == nil
+}
+
+// Render the current proposals as an SSE event, most votes first.
+func (data *GameData) proposalsEvent() sseEvent {
+	data.gameStateMutex.Lock()
+	templateData := proposalsTemplateData{
+		VotingWindowSeconds: int(data.session.config.votingWindow.Seconds()),
+		Proposals:           make([]proposalView, 0, len(data.proposals)),
+	}
+	for _, proposal := range data.proposals {
+		templateData.Proposals = append(templateData.Proposals, proposalView{
+			ID:           proposal.ID,
+			ProposerName: proposal.ProposerName,
+			Question:     proposal.Question,
+			Votes:        len(proposal.voterIDs),
+		})
+	}
+	data.gameStateMutex.Unlock()
+
+	sort.SliceStable(templateData.Proposals, func(i, j int) bool {
+		return templateData.Proposals[i].Votes > templateData.Proposals[j].Votes
+	})
+
+	var proposalsBytes bytes.Buffer
+	err := gameTemplate.ExecuteTemplate(&proposalsBytes, "proposals.html", templateData)
+	if err != nil {
+		log.Error().Str("GameID", data.gameID).Err(err).Msg("Failed to write proposals template")
+	}
+	return sseEvent{name: "proposals", data: proposalsBytes.String()}
+}
+
+// --------------------------------------------------------------------------------
+// Routing Functions
+// --------------------------------------------------------------------------------
+
+// Propose a candidate question -- only guessers may do this, and only in voting mode.
+func (data *GameData) handleProposeQuestion(w http.ResponseWriter, r *http.Request) {
+	question := truncateRunes(r.FormValue("response"), responseMaxLength)
+	isOracle := r.Context().Value("IsOracle").(bool)
+	if len(question) == 0 || isOracle || !data.session.config.votingMode {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	err := data.addProposal(playerFromRequest(r), question)
+	if err != nil {
+		log.Debug().Err(err).Msg("Proposal Not Allowed!")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	data.session.broadcaster.broadcast(data.proposalsEvent())
+}
+
+// Vote for a proposal -- only guessers may do this.
+func (data *GameData) handleUpvoteProposal(w http.ResponseWriter, r *http.Request) {
+	proposalID, err := strconv.Atoi(r.FormValue("proposal"))
+	isOracle := r.Context().Value("IsOracle").(bool)
+	if err != nil || isOracle {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	err = data.upvoteProposal(playerFromRequest(r), proposalID)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	data.session.broadcaster.broadcast(data.proposalsEvent())
+}
+
+// Close the voting window early, asking the top proposal -- only the oracle may do this.
+func (data *GameData) handleTakeTopProposal(w http.ResponseWriter, r *http.Request) {
+	isOracle := r.Context().Value("IsOracle").(bool)
+	if !isOracle {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	if !data.closeVotingWindow(data.currentVotingWindowNumber()) {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,193 @@
+package game
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseVotingWindow(t *testing.T) {
+	testCases := []struct {
+		value string
+		want  time.Duration
+	}{
+		{"", votingWindowDefault},
+		{"soon", votingWindowDefault},
+		{"30", 30 * time.Second},
+		{"1", votingWindowMin},
+		{"-5", votingWindowMin},
+		{"86400", votingWindowMax},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.value, func(t *testing.T) {
+			if got := parseVotingWindow(testCase.value); got != testCase.want {
+				t.Errorf("got %v, want %v", got, testCase.want)
+			}
+		})
+	}
+}
+
+func TestVoting(t *testing.T) {
+	server := newTestServer(t)
+	oracle := server.newPlayer(t)
+	alice := server.newPlayer(t)
+	bob := server.newPlayer(t)
+	carol := server.newPlayer(t)
+
+	// The longest window, so the timer never closes it during the test.
+	gameID := oracle.createGame(url.Values{"voting": {"on"}, "votingWindow": {"600"}})
+	for name, player := range map[string]*testPlayer{"Alice": alice, "Bob": bob, "Carol": carol} {
+		player.mustPost(gameID, "setName", url.Values{"name": {name}})
+	}
+	openGameID := oracle.createGame(url.Values{})
+
+	propose := func(question string) url.Values { return url.Values{"response": {question}} }
+	upvote := func(proposalID int) url.Values { return url.Values{"proposal": {strconv.Itoa(proposalID)}} }
+
+	steps := []struct {
+		name       string
+		player     *testPlayer
+		gameID     string
+		route      string
+		form       url.Values
+		wantStatus int
+	}{
+		{"oracle proposes", oracle, gameID, "proposeQuestion", propose("Is it blue?"), http.StatusBadRequest},
+		{"empty proposal", alice, gameID, "proposeQuestion", propose(""), http.StatusBadRequest},
+		{"proposal outside voting mode", alice, openGameID, "proposeQuestion", propose("Is it blue?"), http.StatusBadRequest},
+		{"first proposal", alice, gameID, "proposeQuestion", propose("Is it blue?"), http.StatusOK},
+		{"second proposal", bob, gameID, "proposeQuestion", propose("Is it red?"), http.StatusOK},
+		{"long proposal", carol, gameID, "proposeQuestion", propose(strings.Repeat("a", responseMaxLength+1)), http.StatusOK},
+		{"more proposals", carol, gameID, "proposeQuestion", propose("Is it green?"), http.StatusOK},
+		{"last proposal", carol, gameID, "proposeQuestion", propose("Is it yellow?"), http.StatusOK},
+		{"too many proposals", carol, gameID, "proposeQuestion", propose("Is it purple?"), http.StatusBadRequest},
+		{"vote", carol, gameID, "upvoteProposal", upvote(2), http.StatusOK},
+		{"repeated vote", carol, gameID, "upvoteProposal", upvote(2), http.StatusOK},
+		{"proposer votes again", alice, gameID, "upvoteProposal", upvote(1), http.StatusOK},
+		{"vote for missing proposal", alice, gameID, "upvoteProposal", upvote(99), http.StatusBadRequest},
+		{"invalid vote", alice, gameID, "upvoteProposal", url.Values{"proposal": {"first"}}, http.StatusBadRequest},
+		{"oracle votes", oracle, gameID, "upvoteProposal", upvote(1), http.StatusBadRequest},
+		{"guesser takes top proposal", alice, gameID, "takeTopProposal", nil, http.StatusUnauthorized},
+		{"oracle takes top proposal", oracle, gameID, "takeTopProposal", nil, http.StatusOK},
+		{"no proposals left", oracle, gameID, "takeTopProposal", nil, http.StatusBadRequest},
+		{"vote after window closed", alice, gameID, "upvoteProposal", upvote(1), http.StatusBadRequest},
+		{"proposal while awaiting answer", alice, gameID, "proposeQuestion", propose("Is it green?"), http.StatusBadRequest},
+	}
+
+	for _, step := range steps {
+		status, body := step.player.post("/game/"+step.gameID+"/"+step.route, step.form)
+		if status != step.wantStatus {
+			t.Fatalf("%s: responded %d, want %d: %s", step.name, status, step.wantStatus, body)
+		}
+	}
+
+	// Bob's proposal had two votes to one for every other proposal, so it was asked in Bob's name.
+	state := oracle.state(gameID)
+	if state.Status != roundStatus_AwaitingAnswer || len(state.Round.Entries) != 1 {
+		t.Fatalf("status %s with entries %+v, want the top proposal awaiting an answer", state.Status, state.Round.Entries)
+	}
+	if entry := state.Round.Entries[0]; entry.Question != "Is it red?" || entry.AskerName != "Bob" {
+		t.Errorf("asked %q by %s, want the top proposal by Bob", entry.Question, entry.AskerName)
+	}
+}
+
+func TestTopProposal(t *testing.T) {
+	server := newTestServer(t)
+	oracle := server.newPlayer(t)
+	gameID := oracle.createGame(url.Values{"voting": {"on"}, "votingWindow": {"600"}})
+	session, _ := server.master.sessionByID(gameID)
+	data := session.currentRound()
+
+	alice := playerIdentity{ID: "alice", Name: "Alice"}
+	bob := playerIdentity{ID: "bob", Name: "Bob"}
+	carol := playerIdentity{ID: "carol", Name: "Carol"}
+
+	testCases := []struct {
+		name      string
+		proposers []playerIdentity
+
+		// Votes for each proposal, by position, on top of the vote of the proposer.
+		votes        map[int][]playerIdentity
+		staleWindow  bool
+		wantProposer string
+		wantOK       bool
+	}{
+		{"single proposal", []playerIdentity{alice}, nil, false, "alice", true},
+		{"most votes", []playerIdentity{alice, bob}, map[int][]playerIdentity{1: {carol}}, false, "bob", true},
+		{"earliest wins tie", []playerIdentity{alice, bob, carol}, map[int][]playerIdentity{1: {alice}, 2: {alice}}, false, "bob", true},
+		{"repeated votes count once", []playerIdentity{alice, bob}, map[int][]playerIdentity{1: {bob, bob}}, false, "alice", true},
+		{"no proposals", nil, nil, false, "", false},
+		{"stale window", []playerIdentity{alice}, nil, true, "", false},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			votingWindowNumber := data.currentVotingWindowNumber()
+			proposalIDs := make([]int, 0, len(testCase.proposers))
+			for _, proposer := range testCase.proposers {
+				if err := data.addProposal(proposer, "Is it "+proposer.Name+"?"); err != nil {
+					t.Fatalf("failed to propose: %v", err)
+				}
+				proposalIDs = append(proposalIDs, data.nextProposalID)
+			}
+			for position, voters := range testCase.votes {
+				for _, voter := range voters {
+					data.upvoteProposal(voter, proposalIDs[position])
+				}
+			}
+			if testCase.staleWindow {
+				votingWindowNumber -= 1
+			}
+
+			data.gameStateMutex.Lock()
+			defer data.gameStateMutex.Unlock()
+			topProposal, ok := data.topProposal(votingWindowNumber)
+			if ok != testCase.wantOK || (ok && topProposal.ProposerID != testCase.wantProposer) {
+				t.Errorf("got proposal %+v, %v, want proposal by %q, %v", topProposal, ok, testCase.wantProposer, testCase.wantOK)
+			}
+			if len(data.proposals) != len(testCase.proposers) {
+				t.Errorf("%d proposals left, want all %d kept", len(data.proposals), len(testCase.proposers))
+			}
+
+			// Leave the round with no proposals and a closed window for the next case.
+			data.clearProposals()
+		})
+	}
+}
+
+func TestTopProposalKeptWhenNotAsked(t *testing.T) {
+	server := newTestServer(t)
+	oracle := server.newPlayer(t)
+	alice := server.newPlayer(t)
+	gameID := oracle.createGame(url.Values{"voting": {"on"}, "votingWindow": {"600"}, "questionBudget": {"2"}, "hintCost": {"2"}})
+	alice.join(gameID)
+	session, _ := server.master.sessionByID(gameID)
+	data := session.currentRound()
+
+	alice.mustPost(gameID, "proposeQuestion", url.Values{"response": {"Is it blue?"}})
+	votingWindowNumber := data.currentVotingWindowNumber()
+
+	// The hint uses the rest of the budget, so the top proposal cannot be asked.
+	oracle.mustPost(gameID, "giveHint", url.Values{"response": {"It is not a colour"}})
+	if status, _ := oracle.post("/game/"+gameID+"/takeTopProposal", nil); status != http.StatusBadRequest {
+		t.Errorf("taking an unaskable proposal responded %d, want %d", status, http.StatusBadRequest)
+	}
+
+	data.gameStateMutex.Lock()
+	if len(data.proposals) != 1 || data.proposals[0].Question != "Is it blue?" {
+		t.Errorf("proposals = %+v, want the proposal kept", data.proposals)
+	}
+	if data.votingWindowNumber != votingWindowNumber || data.votingTimer != nil {
+		t.Errorf("voting window %d with timer %v, want window %d kept without a timer", data.votingWindowNumber, data.votingTimer, votingWindowNumber)
+	}
+	data.gameStateMutex.Unlock()
+
+	state := oracle.state(gameID)
+	if state.Status != roundStatus_AwaitingQuestion || len(state.Round.Entries) != 1 || state.QuestionsRemaining != 0 {
+		t.Errorf("status %s with entries %+v and %d questions remaining, want only the hint", state.Status, state.Round.Entries, state.QuestionsRemaining)
+	}
+}
@@ -0,0 +1,218 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/hmcalister/twentyquestions/transcript"
+	"github.com/hmcalister/twentyquestions/webhooks"
+)
+
+// --------------------------------------------------------------------------------
+// Events
+// --------------------------------------------------------------------------------
+
+// Let webhooks know a new session was created.
+func (session *GameSession) publishGameCreated() {
+	session.master.webhooks.Publish(webhooks.EventType_GameCreated, session.gameID, webhooks.GameCreatedData{
+		Title:          session.config.title,
+		Category:       session.config.category,
+		Public:         session.config.isPublic,
+		QuestionBudget: session.config.questionBudget,
+		ComputerOracle: session.config.computerOracle,
+		URL:            fmt.Sprintf("/game/%s/", session.gameID),
+	})
+}
+
+// Let webhooks know a question was asked.
+func (data *GameData) publishQuestionAsked(pair questionAnswerPair) {
+	data.session.master.webhooks.Publish(webhooks.EventType_QuestionAsked, data.gameID, webhooks.QuestionAskedData{
+		Round:     data.roundNumber,
+		Index:     pair.Index,
+		AskerName: pair.AskerName,
+		Question:  pair.Question,
+	})
+}
+
+// Let webhooks know the oracle answered a question.
+func (data *GameData) publishAnswerGiven(pair questionAnswerPair) {
+	data.session.master.webhooks.Publish(webhooks.EventType_AnswerGiven, data.gameID, webhooks.AnswerGivenData{
+		Round:    data.roundNumber,
+		Index:    pair.Index,
+		Question: pair.Question,
+		Answer:   pair.Answer,
+	})
+}
+
+// Let webhooks know a round is over, with the finished round and the scores after it.
+func (session *GameSession) publishGameOver(round *GameData) {
+	winnerID := round.roundWinnerID()
+
+	session.sessionMutex.Lock()
+	gameOverData := webhooks.GameOverData{
+		Players: make([]transcript.Player, 0, len(session.players)),
+	}
+	winnerName := ""
+	for _, sessionPlayer := range session.players {
+		gameOverData.Players = append(gameOverData.Players, transcript.Player{
+			Name:  sessionPlayer.Name,
+			Score: sessionPlayer.Score,
+		})
+		if sessionPlayer.ID == winnerID {
+			winnerName = sessionPlayer.Name
+		}
+	}
+	session.sessionMutex.Unlock()
+
+	gameOverData.Round = round.transcriptRound(true)
+	gameOverData.Round.WinnerName = winnerName
+	session.master.webhooks.Publish(webhooks.EventType_GameOver, session.gameID, gameOverData)
+}
+
+// --------------------------------------------------------------------------------
+// Routing Functions
+// --------------------------------------------------------------------------------
+
+// A new subscription, as returned to its creator -- the only time the secret is shown.
+type webhookSubscriptionCreated struct {
+	webhooks.Subscription
+	Secret string `json:"secret"`
+}
+
+// The subscriptions and recent deliveries of a game, or of the whole server.
+type webhookListing struct {
+	Subscriptions []webhooks.Subscription `json:"subscriptions"`
+	Deliveries    []webhooks.Delivery     `json:"deliveries"`
+}
+
+// Subscribe to events from the form values url, secret and events, writing the new subscription as JSON.
+// Returns the subscription, and false if it could not be made (the error has been written).
+func (master *GameMaster) writeNewWebhookSubscription(w http.ResponseWriter, r *http.Request, gameID string) (webhooks.Subscription, bool) {
+	r.ParseForm()
+	eventTypes, err := webhooks.ParseEventTypes(r.Form["events"])
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, err.Error())
+		return webhooks.Subscription{}, false
+	}
+
+	subscription, err := master.webhooks.Subscribe(r.FormValue("url"), r.FormValue("secret"), gameID, eventTypes)
+	switch {
+	case errors.Is(err, webhooks.ErrGameWebhooksDisabled):
+		writeJSONError(w, http.StatusForbidden, err.Error())
+	case errors.Is(err, webhooks.ErrTooManySubscriptions):
+		writeJSONError(w, http.StatusConflict, err.Error())
+	case err != nil:
+		writeJSONError(w, http.StatusBadRequest, err.Error())
+	default:
+		writeJSON(w, http.StatusCreated, webhookSubscriptionCreated{
+			Subscription: subscription,
+			Secret:       subscription.Secret,
+		})
+		return subscription, true
+	}
+	return webhooks.Subscription{}, false
+}
+
+// Send a ping to a subscription, writing the resulting delivery as JSON.
+func (master *GameMaster) writeWebhookTest(w http.ResponseWriter, subscriptionID string) {
+	delivery, err := master.webhooks.Test(subscriptionID)
+	if err != nil {
+		writeJSONError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, delivery)
+}
+
+// Check the requesting player is the oracle, and that the subscription named in the URL (if any) belongs to this session.
+// Writes an error and returns false otherwise.
+func (session *GameSession) checkWebhookRequest(w http.ResponseWriter, r *http.Request) bool {
+	isOracle := r.Context().Value("IsOracle").(bool)
+	if !isOracle {
+		writeJSONError(w, http.StatusUnauthorized, "only the oracle may manage webhooks")
+		return false
+	}
+
+	subscriptionID := chi.URLParam(r, "webhookID")
+	if subscriptionID == "" {
+		return true
+	}
+	subscription, ok := session.master.webhooks.Subscription(subscriptionID)
+	if !ok || subscription.GameID != session.gameID {
+		writeJSONError(w, http.StatusNotFound, webhooks.ErrNoSubscription.Error())
+		return false
+	}
+	return true
+}
+
+// List the webhook subscriptions of the session and their recent deliveries -- only the oracle may do this.
+func (session *GameSession) handleListWebhooks(w http.ResponseWriter, r *http.Request) {
+	if !session.checkWebhookRequest(w, r) {
+		return
+	}
+	writeJSON(w, http.StatusOK, webhookListing{
+		Subscriptions: session.master.webhooks.GameSubscriptions(session.gameID),
+		Deliveries:    session.master.webhooks.GameDeliveries(session.gameID),
+	})
+}
+
+// Subscribe to the events of the session -- only the oracle may do this, and only if the server allows it.
+func (session *GameSession) handleAddWebhook(w http.ResponseWriter, r *http.Request) {
+	if !session.checkWebhookRequest(w, r) {
+		return
+	}
+	subscription, ok := session.master.writeNewWebhookSubscription(w, r, session.gameID)
+	if ok {
+		session.currentRound().audit(auditAction_WebhookAdded, playerFromRequest(r), subscription.ID)
+	}
+}
+
+// Remove a webhook subscription of the session -- only the oracle may do this.
+func (session *GameSession) handleRemoveWebhook(w http.ResponseWriter, r *http.Request) {
+	if !session.checkWebhookRequest(w, r) {
+		return
+	}
+	subscriptionID := chi.URLParam(r, "webhookID")
+	session.master.webhooks.Unsubscribe(subscriptionID)
+	session.currentRound().audit(auditAction_WebhookRemoved, playerFromRequest(r), subscriptionID)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Send a ping to a webhook subscription of the session -- only the oracle may do this.
+func (session *GameSession) handleTestWebhook(w http.ResponseWriter, r *http.Request) {
+	if !session.checkWebhookRequest(w, r) {
+		return
+	}
+	session.master.writeWebhookTest(w, chi.URLParam(r, "webhookID"))
+}
+
+// List every webhook subscription, server-wide and per game, and their recent deliveries.
+// A subscription query parameter limits the deliveries to a single subscription.
+func (master *GameMaster) adminListWebhooks(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, webhookListing{
+		Subscriptions: master.webhooks.Subscriptions(),
+		Deliveries:    master.webhooks.Deliveries(r.URL.Query().Get("subscription")),
+	})
+}
+
+// Add a server-wide webhook subscription, sent events from every game.
+func (master *GameMaster) adminAddWebhook(w http.ResponseWriter, r *http.Request) {
+	master.writeNewWebhookSubscription(w, r, "")
+}
+
+// Remove any webhook subscription.
+func (master *GameMaster) adminRemoveWebhook(w http.ResponseWriter, r *http.Request) {
+	err := master.webhooks.Unsubscribe(chi.URLParam(r, "webhookID"))
+	if err != nil {
+		writeJSONError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Send a ping to any webhook subscription.
+func (master *GameMaster) adminTestWebhook(w http.ResponseWriter, r *http.Request) {
+	master.writeWebhookTest(w, chi.URLParam(r, "webhookID"))
+}
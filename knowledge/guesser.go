@@ -0,0 +1,161 @@
+package knowledge
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+const (
+	// The factor the weight of an entity is multiplied by for each answer that contradicts it.
+	// Contradicted entities are kept rather than removed, in case the oracle made a mistake.
+	contradictionWeight float64 = 0.05
+
+	// The probability the most likely entity must reach before it is guessed.
+	guessConfidence float64 = 0.8
+
+	// The information gain below which no question is worth asking, and the most likely entity is guessed instead.
+	minimumInformationGain float64 = 0.01
+)
+
+// A question asked in a round, along with the answer given by the oracle.
+type Observation struct {
+	Question string
+	Answer   string
+}
+
+// Interpret the answer of an oracle as yes, no, or unknown, from the first word of the answer.
+func ParseAnswer(text string) Answer {
+	words := strings.Fields(normalize(text))
+	if len(words) == 0 {
+		return Answer_Unknown
+	}
+
+	switch words[0] {
+	case "yes", "yeah", "yep", "yup", "y", "correct", "true", "sure", "definitely", "absolutely", "mostly", "usually":
+		return Answer_Yes
+	case "no", "nope", "nah", "n", "false", "not", "never", "rarely":
+		return Answer_No
+	default:
+		return Answer_Unknown
+	}
+}
+
+// Phrase a guess of the entity as a question.
+func guessQuestion(name string) string {
+	article := "a"
+	if strings.ContainsRune("aeiou", rune(strings.ToLower(name)[0])) {
+		article = "an"
+	}
+	return fmt.Sprintf("Is it %s %s?", article, name)
+}
+
+// Calculate the binary entropy of a probability, in bits.
+func binaryEntropy(probability float64) float64 {
+	if probability <= 0 || probability >= 1 {
+		return 0
+	}
+	return -probability*math.Log2(probability) - (1-probability)*math.Log2(1-probability)
+}
+
+// Choose the next question to ask about a secret in the category, given everything asked so far in the round.
+// An empty category considers every entity.
+//
+// Each entity is weighted by how well it agrees with the observed answers. The question asked is the attribute
+// with the greatest information gain over the weighted entities, unless the most likely entity is likely enough
+// to be guessed, there are no questions remaining after this one, or no attribute would tell the entities apart.
+// Returns false if an entity was guessed correctly, or every entity has been guessed, so there is nothing left to ask.
+func (base *Base) NextQuestion(category string, observations []Observation, questionsRemaining int) (string, bool) {
+	base.baseMutex.RLock()
+	defer base.baseMutex.RUnlock()
+
+	weights := make(map[string]float64)
+	for _, entity := range base.entities {
+		if category == "" || entity.Category == category {
+			weights[entity.Name] = 1
+		}
+	}
+
+	// Apply every observation -- entities guessed incorrectly are removed, and entities contradicting an answer are weighted down.
+	askedAttributes := make(map[string]bool)
+	for _, observation := range observations {
+		answer := ParseAnswer(observation.Answer)
+		if guessedEntity, ok := base.matchGuess(observation.Question); ok {
+			if answer == Answer_Yes {
+				return "", false
+			}
+			delete(weights, guessedEntity.Name)
+			continue
+		}
+
+		attribute, ok := base.matchAttribute(observation.Question)
+		if !ok {
+			continue
+		}
+		askedAttributes[attribute.Name] = true
+		if answer == Answer_Unknown {
+			continue
+		}
+		for _, entity := range base.entities {
+			value, ok := entity.Attributes[attribute.Name]
+			if _, isCandidate := weights[entity.Name]; isCandidate && ok && value != (answer == Answer_Yes) {
+				weights[entity.Name] *= contradictionWeight
+			}
+		}
+	}
+	if len(weights) == 0 {
+		return "", false
+	}
+
+	totalWeight := 0.0
+	likeliestName := ""
+	for _, entity := range base.entities {
+		weight, ok := weights[entity.Name]
+		if !ok {
+			continue
+		}
+		totalWeight += weight
+		if likeliestName == "" || weight > weights[likeliestName] {
+			likeliestName = entity.Name
+		}
+	}
+	if weights[likeliestName]/totalWeight >= guessConfidence || questionsRemaining <= 1 {
+		return guessQuestion(likeliestName), true
+	}
+
+	// Find the unasked attribute that best splits the weighted entities. Entities with no value for the attribute are not split.
+	bestQuestion := ""
+	bestGain := minimumInformationGain
+	for _, attribute := range base.attributes {
+		if askedAttributes[attribute.Name] {
+			continue
+		}
+
+		yesWeight, noWeight := 0.0, 0.0
+		for _, entity := range base.entities {
+			weight, ok := weights[entity.Name]
+			value, known := entity.Attributes[attribute.Name]
+			if !ok || !known {
+				continue
+			}
+			if value {
+				yesWeight += weight
+			} else {
+				noWeight += weight
+			}
+		}
+		if yesWeight+noWeight == 0 {
+			continue
+		}
+
+		gain := (yesWeight + noWeight) / totalWeight * binaryEntropy(yesWeight/(yesWeight+noWeight))
+		if gain > bestGain {
+			bestQuestion = attribute.Question
+			bestGain = gain
+		}
+	}
+	if bestQuestion == "" {
+		return guessQuestion(likeliestName), true
+	}
+	return bestQuestion, true
+}
@@ -0,0 +1,91 @@
+package knowledge
+
+import (
+	"testing"
+)
+
+func TestParseAnswer(t *testing.T) {
+	testCases := []struct {
+		text string
+		want Answer
+	}{
+		{"Yes", Answer_Yes},
+		{"yes, mostly", Answer_Yes},
+		{"  Absolutely!", Answer_Yes},
+		{"No", Answer_No},
+		{"Not really", Answer_No},
+		{"nope.", Answer_No},
+		{"Maybe", Answer_Unknown},
+		{"I don't know", Answer_Unknown},
+		{"", Answer_Unknown},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.text, func(t *testing.T) {
+			if got := ParseAnswer(testCase.text); got != testCase.want {
+				t.Errorf("got %s, want %s", got, testCase.want)
+			}
+		})
+	}
+}
+
+func TestNextQuestion(t *testing.T) {
+	base := newTestBase(t)
+
+	testCases := []struct {
+		name               string
+		category           string
+		observations       []Observation
+		questionsRemaining int
+		want               string
+		wantOK             bool
+	}{
+		{"best split first", "Animals", nil, 20, "Can it fly?", true},
+		{"asked attribute skipped", "Animals", []Observation{
+			{"Can it fly?", "No"},
+		}, 20, "Does it live in water?", true},
+		{"unknown answer still skips attribute", "Animals", []Observation{
+			{"Can it fly?", "Maybe"},
+		}, 20, "Does it live in water?", true},
+		{"confident guess", "Animals", []Observation{
+			{"Can it fly?", "No"},
+			{"Does it live in water?", "Yes"},
+		}, 20, "Is it a Whale?", true},
+		{"last question is a guess", "Animals", nil, 1, "Is it an Eagle?", true},
+		{"incorrect guesses removed", "Animals", []Observation{
+			{"Is it an eagle?", "No"},
+			{"Is it a whale?", "No"},
+			{"Is it a polar bear?", "No"},
+		}, 20, "Is it a Dog?", true},
+		{"contradicted entities kept", "Animals", []Observation{
+			{"Is it an animal?", "No"},
+		}, 20, "Can it fly?", true},
+		{"nothing splits the entities", "Animals", []Observation{
+			{"Is it an eagle?", "No"},
+			{"Is it a whale?", "No"},
+			{"Can it fly?", "No"},
+			{"Does it live in water?", "No"},
+			{"Is it an animal?", "Yes"},
+		}, 20, "Is it a Dog?", true},
+		{"single entity in category", "Vehicles", nil, 20, "Is it an Airplane?", true},
+		{"every category", "", []Observation{
+			{"Can it fly?", "Yes"},
+		}, 20, "Is it an animal?", true},
+		{"correct guess", "Animals", []Observation{
+			{"Is it a dog?", "Yes"},
+		}, 19, "", false},
+		{"every entity guessed", "Vehicles", []Observation{
+			{"Is it an airplane?", "No"},
+		}, 19, "", false},
+		{"unknown category", "Minerals", nil, 20, "", false},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			got, ok := base.NextQuestion(testCase.category, testCase.observations, testCase.questionsRemaining)
+			if got != testCase.want || ok != testCase.wantOK {
+				t.Errorf("got %q, %v, want %q, %v", got, ok, testCase.want, testCase.wantOK)
+			}
+		})
+	}
+}
@@ -0,0 +1,302 @@
+package knowledge
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+var (
+	// Openings of a question that directly guesses the secret, longest first so only the longest is removed.
+	guessPrefixes = []string{"are you thinking of", "is the secret", "is the answer", "is it", "it is", "it s"}
+)
+
+// Enum for Answer, the answer to a yes or no question about an entity.
+type Answer int
+
+const (
+	Answer_Unknown Answer = iota
+	Answer_Yes     Answer = iota
+	Answer_No      Answer = iota
+)
+
+// The answer as given by an oracle.
+func (answer Answer) String() string {
+	switch answer {
+	case Answer_Yes:
+		return "Yes"
+	case Answer_No:
+		return "No"
+	default:
+		return "I don't know"
+	}
+}
+
+// A yes or no property of an entity, along with the question asking about it and the keywords that identify that question.
+type Attribute struct {
+	Name     string   `json:"name"`
+	Question string   `json:"question"`
+	Keywords []string `json:"keywords"`
+}
+
+// A thing that may be the secret, and the value of each attribute for it. Attributes missing from the map are unknown.
+type Entity struct {
+	Name       string          `json:"name"`
+	Category   string          `json:"category"`
+	Attributes map[string]bool `json:"attributes"`
+}
+
+// The knowledge base as stored in a JSON file:
+//
+//	{
+//	  "attributes": [
+//	    {"name": "canFly", "question": "Can it fly?", "keywords": ["fly", "wings"]}
+//	  ],
+//	  "entities": [
+//	    {"name": "eagle", "category": "Animals", "attributes": {"canFly": true}}
+//	  ]
+//	}
+type baseFile struct {
+	Attributes []Attribute `json:"attributes"`
+	Entities   []Entity    `json:"entities"`
+}
+
+// Check every attribute and entity is named, that names are unique, and that entities only use known attributes.
+func (file baseFile) validate() error {
+	attributeNames := make(map[string]bool)
+	for _, attribute := range file.Attributes {
+		if strings.TrimSpace(attribute.Name) == "" || strings.TrimSpace(attribute.Question) == "" {
+			return errors.New("attribute must have a name and a question")
+		}
+		if attributeNames[attribute.Name] {
+			return fmt.Errorf("attribute %s is defined twice", attribute.Name)
+		}
+		attributeNames[attribute.Name] = true
+	}
+
+	entityNames := make(map[string]bool)
+	for _, entity := range file.Entities {
+		if normalize(entity.Name) == "" || strings.TrimSpace(entity.Category) == "" {
+			return errors.New("entity must have a name and a category")
+		}
+		if entityNames[normalize(entity.Name)] {
+			return fmt.Errorf("entity %s is defined twice", entity.Name)
+		}
+		entityNames[normalize(entity.Name)] = true
+		for attributeName := range entity.Attributes {
+			if !attributeNames[attributeName] {
+				return fmt.Errorf("entity %s has unknown attribute %s", entity.Name, attributeName)
+			}
+		}
+	}
+	return nil
+}
+
+// --------------------------------------------------------------------------------
+// Base
+// --------------------------------------------------------------------------------
+
+// A knowledge base of entities and their yes or no attributes, used by the computer players.
+type Base struct {
+	attributes []Attribute
+
+	// The entities loaded from the knowledge base file.
+	builtinEntities []Entity
+
+	// Facts learned from completed games, keyed by the normalized name of the entity.
+	learned map[string]*LearnedEntity
+
+	// File the learned facts are saved to, or empty to keep them in memory only.
+	learnedPath string
+
+	// The built in entities with the learned facts merged in -- these are the entities the computer players use.
+	entities []Entity
+
+	// Mutex to handle async reading and changing of the knowledge base.
+	baseMutex sync.RWMutex
+}
+
+// Load and validate the knowledge base from a JSON file.
+func Load(path string) (*Base, error) {
+	baseBytes, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var file baseFile
+	err = json.Unmarshal(baseBytes, &file)
+	if err != nil {
+		return nil, err
+	}
+	err = file.validate()
+	if err != nil {
+		return nil, fmt.Errorf("knowledge base %s: %w", path, err)
+	}
+
+	base := &Base{
+		attributes:      file.Attributes,
+		builtinEntities: file.Entities,
+		learned:         make(map[string]*LearnedEntity),
+	}
+	base.mergeLearned()
+
+	// The question of each attribute must match the attribute, so the answers to questions asked by the computer guesser are understood.
+	for _, attribute := range base.attributes {
+		if matched, ok := base.matchAttribute(attribute.Question); !ok || matched.Name != attribute.Name {
+			return nil, fmt.Errorf("knowledge base %s: question of attribute %s does not match its keywords", path, attribute.Name)
+		}
+	}
+	return base, nil
+}
+
+// The names of all entities in the category, or of every entity if the category is empty.
+func (base *Base) Entities(category string) []string {
+	base.baseMutex.RLock()
+	defer base.baseMutex.RUnlock()
+
+	names := make([]string, 0, len(base.entities))
+	for _, entity := range base.entities {
+		if category == "" || entity.Category == category {
+			names = append(names, entity.Name)
+		}
+	}
+	return names
+}
+
+// Find the entity with the given name, ignoring case and punctuation.
+//
+// The caller must hold the baseMutex.
+func (base *Base) entityByName(name string) (Entity, bool) {
+	for _, entity := range base.entities {
+		if normalize(entity.Name) == normalize(name) {
+			return entity, true
+		}
+	}
+	return Entity{}, false
+}
+
+// Find the attribute a question asks about, from the longest keyword found in the question.
+// Returns false if no keyword is found.
+//
+// The caller must hold the baseMutex.
+func (base *Base) matchAttribute(question string) (Attribute, bool) {
+	matched := Attribute{}
+	matchedWords := 0
+	for _, attribute := range base.attributes {
+		for _, keyword := range attribute.Keywords {
+			words := len(strings.Fields(normalize(keyword)))
+			if words > matchedWords && containsPhrase(question, keyword) {
+				matched = attribute
+				matchedWords = words
+			}
+		}
+	}
+	return matched, matchedWords > 0
+}
+
+// Find the entity a question names as a guess, e.g. "Is it a dog?". Returns false if no entity is named.
+//
+// The caller must hold the baseMutex.
+func (base *Base) matchEntity(question string) (Entity, bool) {
+	matched := Entity{}
+	matchedWords := 0
+	for _, entity := range base.entities {
+		words := len(strings.Fields(normalize(entity.Name)))
+		if words > matchedWords && (containsPhrase(question, entity.Name) || containsPhrase(question, entity.Name+"s")) {
+			matched = entity
+			matchedWords = words
+		}
+	}
+	return matched, matchedWords > 0
+}
+
+// Find the entity a question is directly guessing, e.g. "Is it a dog?" or "Are you thinking of the Eiffel Tower?".
+// Returns false if the question is not a direct guess.
+//
+// The caller must hold the baseMutex.
+func (base *Base) matchGuess(question string) (Entity, bool) {
+	guess := normalize(question)
+	for _, prefix := range guessPrefixes {
+		if strings.HasPrefix(guess, prefix+" ") {
+			guess = strings.TrimPrefix(guess, prefix+" ")
+			break
+		}
+	}
+	for _, article := range []string{"a", "an", "the", "some"} {
+		guess = strings.TrimPrefix(guess, article+" ")
+	}
+
+	for _, entity := range base.entities {
+		name := normalize(entity.Name)
+		if guess == name || guess == name+"s" {
+			return entity, true
+		}
+	}
+	return Entity{}, false
+}
+
+// Answer a question about the secret entity. Direct guesses are checked first, then the question is matched
+// to an attribute by its keywords, and otherwise is treated as a guess if it names an entity anywhere.
+// Returns true if the question correctly guessed the secret.
+//
+// Questions that match nothing, or ask about an attribute the secret has no value for, are answered Answer_Unknown.
+func (base *Base) Answer(secret string, question string) (Answer, bool) {
+	base.baseMutex.RLock()
+	defer base.baseMutex.RUnlock()
+
+	secretEntity, ok := base.entityByName(secret)
+	if !ok {
+		return Answer_Unknown, false
+	}
+
+	if guessedEntity, ok := base.matchGuess(question); ok {
+		if guessedEntity.Name == secretEntity.Name {
+			return Answer_Yes, true
+		}
+		return Answer_No, false
+	}
+
+	if attribute, ok := base.matchAttribute(question); ok {
+		value, ok := secretEntity.Attributes[attribute.Name]
+		switch {
+		case !ok:
+			return Answer_Unknown, false
+		case value:
+			return Answer_Yes, false
+		default:
+			return Answer_No, false
+		}
+	}
+
+	if guessedEntity, ok := base.matchEntity(question); ok {
+		if guessedEntity.Name == secretEntity.Name {
+			return Answer_Yes, true
+		}
+		return Answer_No, false
+	}
+	return Answer_Unknown, false
+}
+
+// --------------------------------------------------------------------------------
+// Utility Functions
+// --------------------------------------------------------------------------------
+
+// Lowercase the text, replacing everything other than letters and digits with single spaces.
+func normalize(text string) string {
+	return strings.Join(strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}), " ")
+}
+
+// Check if the text contains the phrase as whole words, ignoring case and punctuation.
+func containsPhrase(text string, phrase string) bool {
+	normalizedPhrase := normalize(phrase)
+	if normalizedPhrase == "" {
+		return false
+	}
+	return strings.Contains(" "+normalize(text)+" ", " "+normalizedPhrase+" ")
+}
@@ -0,0 +1,145 @@
+package knowledge
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// A small knowledge base: four animals, one of them missing a value, and a vehicle.
+const testBaseJSON = `{
+  "attributes": [
+    {"name": "isAnimal", "question": "Is it an animal?", "keywords": ["animal"]},
+    {"name": "canFly", "question": "Can it fly?", "keywords": ["fly", "flies"]},
+    {"name": "livesInWater", "question": "Does it live in water?", "keywords": ["water", "swim"]},
+    {"name": "isLarge", "question": "Is it large?", "keywords": ["large", "big"]}
+  ],
+  "entities": [
+    {"name": "Eagle", "category": "Animals", "attributes": {"isAnimal": true, "canFly": true, "livesInWater": false, "isLarge": false}},
+    {"name": "Dog", "category": "Animals", "attributes": {"isAnimal": true, "canFly": false, "livesInWater": false, "isLarge": false}},
+    {"name": "Whale", "category": "Animals", "attributes": {"isAnimal": true, "canFly": false, "livesInWater": true, "isLarge": true}},
+    {"name": "Polar Bear", "category": "Animals", "attributes": {"isAnimal": true, "canFly": false, "livesInWater": false}},
+    {"name": "Airplane", "category": "Vehicles", "attributes": {"isAnimal": false, "canFly": true, "livesInWater": false, "isLarge": true}}
+  ]
+}`
+
+// Write a knowledge base file to a temporary directory, returning its path.
+func writeTestBase(t *testing.T, baseJSON string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "base.json")
+	if err := os.WriteFile(path, []byte(baseJSON), 0o644); err != nil {
+		t.Fatalf("failed to write knowledge base: %v", err)
+	}
+	return path
+}
+
+// Load the test knowledge base, failing the test if it cannot be loaded.
+func newTestBase(t *testing.T) *Base {
+	t.Helper()
+	base, err := Load(writeTestBase(t, testBaseJSON))
+	if err != nil {
+		t.Fatalf("failed to load knowledge base: %v", err)
+	}
+	return base
+}
+
+func TestLoadRejectsInvalidBase(t *testing.T) {
+	testCases := []struct {
+		name     string
+		baseJSON string
+	}{
+		{"invalid JSON", `{"attributes": [`},
+		{"attribute without question", `{"attributes": [{"name": "canFly", "keywords": ["fly"]}]}`},
+		{"attribute defined twice", `{"attributes": [
+			{"name": "canFly", "question": "Can it fly?", "keywords": ["fly"]},
+			{"name": "canFly", "question": "Does it fly?", "keywords": ["fly"]}]}`},
+		{"entity without category", `{"entities": [{"name": "Eagle"}]}`},
+		{"entity defined twice", `{"entities": [{"name": "Eagle", "category": "Animals"}, {"name": "eagle!", "category": "Birds"}]}`},
+		{"unknown attribute", `{"entities": [{"name": "Eagle", "category": "Animals", "attributes": {"canFly": true}}]}`},
+		{"question does not match keywords", `{"attributes": [{"name": "canFly", "question": "Does it have wings?", "keywords": ["fly"]}]}`},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			if _, err := Load(writeTestBase(t, testCase.baseJSON)); err == nil {
+				t.Error("loaded an invalid knowledge base")
+			}
+		})
+	}
+}
+
+func TestMatchGuess(t *testing.T) {
+	base := newTestBase(t)
+
+	testCases := []struct {
+		question string
+		want     string
+		wantOK   bool
+	}{
+		{"Is it a dog?", "Dog", true},
+		{"is it an EAGLE!!", "Eagle", true},
+		{"Is it dogs?", "Dog", true},
+		{"Are you thinking of the polar bear?", "Polar Bear", true},
+		{"Is the secret some whales?", "Whale", true},
+		{"It's an airplane", "Airplane", true},
+		{"Dog?", "Dog", true},
+		{"Is it a cat?", "", false},
+		{"Is it a big dog?", "", false},
+		{"Does it chase a dog?", "", false},
+		{"Is it a bear?", "", false},
+		{"", "", false},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.question, func(t *testing.T) {
+			got, ok := base.matchGuess(testCase.question)
+			if ok != testCase.wantOK || got.Name != testCase.want {
+				t.Errorf("got %q, %v, want %q, %v", got.Name, ok, testCase.want, testCase.wantOK)
+			}
+		})
+	}
+}
+
+func TestAnswer(t *testing.T) {
+	base := newTestBase(t)
+
+	testCases := []struct {
+		name        string
+		secret      string
+		question    string
+		want        Answer
+		wantCorrect bool
+	}{
+		{"correct guess", "Dog", "Is it a dog?", Answer_Yes, true},
+		{"guess ignores case", "polar bear", "Is it a Polar Bear?", Answer_Yes, true},
+		{"incorrect guess", "Dog", "Is it an eagle?", Answer_No, false},
+		{"attribute yes", "Dog", "Is it an animal?", Answer_Yes, false},
+		{"attribute no", "Dog", "Can it fly?", Answer_No, false},
+		{"attribute by other keyword", "Whale", "Can it swim?", Answer_Yes, false},
+		{"attribute without value", "Polar Bear", "Is it large?", Answer_Unknown, false},
+		{"attribute before named entity", "Whale", "Is it as big as a whale?", Answer_Yes, false},
+		{"entity named in question", "Dog", "Does it chase a dog?", Answer_Yes, true},
+		{"other entity named in question", "Dog", "Does it chase an airplane?", Answer_No, false},
+		{"no match", "Dog", "What colour is it?", Answer_Unknown, false},
+		{"unknown secret", "Unicorn", "Is it an animal?", Answer_Unknown, false},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			got, correct := base.Answer(testCase.secret, testCase.question)
+			if got != testCase.want || correct != testCase.wantCorrect {
+				t.Errorf("got %s, %v, want %s, %v", got, correct, testCase.want, testCase.wantCorrect)
+			}
+		})
+	}
+}
+
+func TestEntities(t *testing.T) {
+	base := newTestBase(t)
+	if got := base.Entities("Vehicles"); len(got) != 1 || got[0] != "Airplane" {
+		t.Errorf("vehicles = %v, want Airplane", got)
+	}
+	if got := base.Entities(""); len(got) != 5 {
+		t.Errorf("got %d entities in every category, want 5", len(got))
+	}
+}
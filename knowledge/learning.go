@@ -0,0 +1,412 @@
+package knowledge
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+const (
+	// The number of answers needed before a learned fact is used by the computer players.
+	learnedMinimumAnswers int = 2
+
+	// The fraction of answers that must agree before a learned fact is used by the computer players.
+	learnedMinimumAgreement float64 = 0.75
+
+	// The number of usable facts an entity that is not built in needs before the computer players use it.
+	learnedEntityMinimumAttributes int = 5
+)
+
+// The answers given about one attribute of an entity across completed games.
+type LearnedFact struct {
+	Yes int `json:"yes"`
+	No  int `json:"no"`
+
+	// Set once an admin has accepted a value, which is then used instead of the answers and any built in value.
+	Reviewed bool `json:"reviewed"`
+	Value    bool `json:"value"`
+}
+
+// Get the value the computer players should use for the fact. Returns false if the answers are too few, or disagree too much.
+func (fact LearnedFact) value() (bool, bool) {
+	if fact.Reviewed {
+		return fact.Value, true
+	}
+
+	total := fact.Yes + fact.No
+	if total < learnedMinimumAnswers {
+		return false, false
+	}
+	if float64(fact.Yes) >= learnedMinimumAgreement*float64(total) {
+		return true, true
+	}
+	if float64(fact.No) >= learnedMinimumAgreement*float64(total) {
+		return false, true
+	}
+	return false, false
+}
+
+// Check if the fact needs review -- the answers disagree with each other, or with the built in value.
+func (fact LearnedFact) isContradiction(builtinValue bool, hasBuiltinValue bool) bool {
+	if fact.Reviewed {
+		return false
+	}
+	if hasBuiltinValue {
+		return (builtinValue && fact.No > 0) || (!builtinValue && fact.Yes > 0)
+	}
+	return fact.Yes > 0 && fact.No > 0
+}
+
+// The facts learned about a secret from completed games, keyed by attribute name.
+type LearnedEntity struct {
+	Name     string                  `json:"name"`
+	Category string                  `json:"category"`
+	Facts    map[string]*LearnedFact `json:"facts"`
+}
+
+// The learned facts as stored in a JSON file.
+type learnedFile struct {
+	Entities []*LearnedEntity `json:"entities"`
+}
+
+// A learned fact, as shown to an admin for review.
+type ReviewItem struct {
+	Entity    string
+	Category  string
+	Attribute string
+	Question  string
+
+	Yes int
+	No  int
+
+	// The value from the knowledge base file, either "Yes", "No", or empty if the file has no value.
+	BuiltinValue string
+
+	// The value the computer players use, either "Yes", "No", or empty if the fact is not used.
+	UsedValue string
+
+	Reviewed      bool
+	Contradiction bool
+}
+
+// Format a value for review, or an empty string if there is no value.
+func reviewValue(value bool, ok bool) string {
+	if !ok {
+		return ""
+	}
+	if value {
+		return Answer_Yes.String()
+	}
+	return Answer_No.String()
+}
+
+// --------------------------------------------------------------------------------
+// Learning
+// --------------------------------------------------------------------------------
+
+// Load the learned facts from a file, if it exists, and save all learned facts to it from now on.
+func (base *Base) UseLearnedFile(path string) error {
+	base.baseMutex.Lock()
+	defer base.baseMutex.Unlock()
+
+	learnedBytes, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	if err == nil {
+		var file learnedFile
+		err = json.Unmarshal(learnedBytes, &file)
+		if err != nil {
+			return fmt.Errorf("learned knowledge %s: %w", path, err)
+		}
+		for _, learnedEntity := range file.Entities {
+			if normalize(learnedEntity.Name) == "" {
+				return fmt.Errorf("learned knowledge %s: entity must have a name", path)
+			}
+			if learnedEntity.Facts == nil {
+				learnedEntity.Facts = make(map[string]*LearnedFact)
+			}
+			for attributeName := range learnedEntity.Facts {
+				if _, ok := base.attributeByName(attributeName); !ok {
+					return fmt.Errorf("learned knowledge %s: entity %s has unknown attribute %s", path, learnedEntity.Name, attributeName)
+				}
+			}
+			base.learned[normalize(learnedEntity.Name)] = learnedEntity
+		}
+	}
+
+	base.learnedPath = path
+	base.mergeLearned()
+	return nil
+}
+
+// Find the attribute with the given name.
+//
+// The caller must hold the baseMutex.
+func (base *Base) attributeByName(name string) (Attribute, bool) {
+	for _, attribute := range base.attributes {
+		if attribute.Name == name {
+			return attribute, true
+		}
+	}
+	return Attribute{}, false
+}
+
+// Find the built in entity with the given name, ignoring case and punctuation.
+//
+// The caller must hold the baseMutex.
+func (base *Base) builtinEntityByName(name string) (Entity, bool) {
+	for _, entity := range base.builtinEntities {
+		if normalize(entity.Name) == normalize(name) {
+			return entity, true
+		}
+	}
+	return Entity{}, false
+}
+
+// Rebuild the entities used by the computer players from the built in entities and the learned facts.
+// Reviewed facts replace built in values, and other usable facts only fill in values the file does not have.
+//
+// The caller must hold the baseMutex.
+func (base *Base) mergeLearned() {
+	entities := make([]Entity, 0, len(base.builtinEntities)+len(base.learned))
+	for _, builtinEntity := range base.builtinEntities {
+		entity := Entity{
+			Name:       builtinEntity.Name,
+			Category:   builtinEntity.Category,
+			Attributes: make(map[string]bool, len(base.attributes)),
+		}
+		for attributeName, value := range builtinEntity.Attributes {
+			entity.Attributes[attributeName] = value
+		}
+		if learnedEntity, ok := base.learned[normalize(builtinEntity.Name)]; ok {
+			for attributeName, fact := range learnedEntity.Facts {
+				_, hasBuiltinValue := builtinEntity.Attributes[attributeName]
+				if value, ok := fact.value(); ok && (fact.Reviewed || !hasBuiltinValue) {
+					entity.Attributes[attributeName] = value
+				}
+			}
+		}
+		entities = append(entities, entity)
+	}
+
+	// Secrets that are not built in are only used once enough is known about them, sorted so the order is stable.
+	learnedKeys := make([]string, 0, len(base.learned))
+	for key := range base.learned {
+		learnedKeys = append(learnedKeys, key)
+	}
+	sort.Strings(learnedKeys)
+	for _, key := range learnedKeys {
+		learnedEntity := base.learned[key]
+		if _, ok := base.builtinEntityByName(learnedEntity.Name); ok {
+			continue
+		}
+
+		entity := Entity{
+			Name:       learnedEntity.Name,
+			Category:   learnedEntity.Category,
+			Attributes: make(map[string]bool),
+		}
+		for attributeName, fact := range learnedEntity.Facts {
+			if value, ok := fact.value(); ok {
+				entity.Attributes[attributeName] = value
+			}
+		}
+		if len(entity.Attributes) >= learnedEntityMinimumAttributes {
+			entities = append(entities, entity)
+		}
+	}
+	base.entities = entities
+}
+
+// Save the learned facts to the learned file, if there is one. The file is written atomically, so a crash never leaves a partial file.
+//
+// The caller must hold the baseMutex.
+func (base *Base) saveLearned() error {
+	if base.learnedPath == "" {
+		return nil
+	}
+
+	file := learnedFile{
+		Entities: make([]*LearnedEntity, 0, len(base.learned)),
+	}
+	for _, learnedEntity := range base.learned {
+		file.Entities = append(file.Entities, learnedEntity)
+	}
+	sort.Slice(file.Entities, func(i, j int) bool {
+		return normalize(file.Entities[i].Name) < normalize(file.Entities[j].Name)
+	})
+
+	learnedBytes, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return err
+	}
+	temporaryPath := base.learnedPath + ".tmp"
+	err = os.WriteFile(temporaryPath, learnedBytes, 0o644)
+	if err != nil {
+		return err
+	}
+	return os.Rename(temporaryPath, base.learnedPath)
+}
+
+// Learn from the questions of a completed round with a revealed secret. Each answered question that matches
+// an attribute is counted as an answer for that attribute of the secret -- guesses and unknown answers are skipped.
+// Secrets that are not built in are learned under the given category.
+//
+// Returns the number of answers learned.
+func (base *Base) Learn(secret string, category string, observations []Observation) (int, error) {
+	secret = strings.TrimSpace(secret)
+	if normalize(secret) == "" {
+		return 0, errors.New("secret must not be blank")
+	}
+
+	base.baseMutex.Lock()
+	defer base.baseMutex.Unlock()
+
+	learnedEntity, ok := base.learned[normalize(secret)]
+	if !ok {
+		learnedEntity = &LearnedEntity{
+			Name:     secret,
+			Category: category,
+			Facts:    make(map[string]*LearnedFact),
+		}
+		if builtinEntity, ok := base.builtinEntityByName(secret); ok {
+			learnedEntity.Name = builtinEntity.Name
+			learnedEntity.Category = builtinEntity.Category
+		}
+	}
+
+	learnedCount := 0
+	for _, observation := range observations {
+		if _, ok := base.matchGuess(observation.Question); ok {
+			continue
+		}
+		attribute, ok := base.matchAttribute(observation.Question)
+		if !ok {
+			continue
+		}
+
+		answer := ParseAnswer(observation.Answer)
+		if answer == Answer_Unknown {
+			continue
+		}
+		fact, ok := learnedEntity.Facts[attribute.Name]
+		if !ok {
+			fact = &LearnedFact{}
+			learnedEntity.Facts[attribute.Name] = fact
+		}
+		if answer == Answer_Yes {
+			fact.Yes += 1
+		} else {
+			fact.No += 1
+		}
+		learnedCount += 1
+	}
+	if learnedCount == 0 {
+		return 0, nil
+	}
+
+	base.learned[normalize(secret)] = learnedEntity
+	base.mergeLearned()
+	return learnedCount, base.saveLearned()
+}
+
+// --------------------------------------------------------------------------------
+// Review
+// --------------------------------------------------------------------------------
+
+// List every learned fact for review, contradictions first, then facts not yet reviewed, then by entity and attribute.
+func (base *Base) Review() []ReviewItem {
+	base.baseMutex.RLock()
+	defer base.baseMutex.RUnlock()
+
+	items := make([]ReviewItem, 0)
+	for _, learnedEntity := range base.learned {
+		builtinEntity, _ := base.builtinEntityByName(learnedEntity.Name)
+		for attributeName, fact := range learnedEntity.Facts {
+			attribute, _ := base.attributeByName(attributeName)
+			builtinValue, hasBuiltinValue := builtinEntity.Attributes[attributeName]
+			item := ReviewItem{
+				Entity:        learnedEntity.Name,
+				Category:      learnedEntity.Category,
+				Attribute:     attributeName,
+				Question:      attribute.Question,
+				Yes:           fact.Yes,
+				No:            fact.No,
+				BuiltinValue:  reviewValue(builtinValue, hasBuiltinValue),
+				Reviewed:      fact.Reviewed,
+				Contradiction: fact.isContradiction(builtinValue, hasBuiltinValue),
+			}
+			if value, ok := fact.value(); ok && (fact.Reviewed || !hasBuiltinValue) {
+				item.UsedValue = reviewValue(value, true)
+			} else {
+				item.UsedValue = item.BuiltinValue
+			}
+			items = append(items, item)
+		}
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].Contradiction != items[j].Contradiction {
+			return items[i].Contradiction
+		}
+		if items[i].Reviewed != items[j].Reviewed {
+			return !items[i].Reviewed
+		}
+		if normalize(items[i].Entity) != normalize(items[j].Entity) {
+			return normalize(items[i].Entity) < normalize(items[j].Entity)
+		}
+		return items[i].Attribute < items[j].Attribute
+	})
+	return items
+}
+
+// Find a learned fact by entity and attribute name.
+//
+// The caller must hold the baseMutex.
+func (base *Base) learnedFact(entityName string, attributeName string) (*LearnedEntity, *LearnedFact, error) {
+	learnedEntity, ok := base.learned[normalize(entityName)]
+	if !ok {
+		return nil, nil, errors.New("no facts learned about entity")
+	}
+	fact, ok := learnedEntity.Facts[attributeName]
+	if !ok {
+		return nil, nil, errors.New("no fact learned about attribute")
+	}
+	return learnedEntity, fact, nil
+}
+
+// Accept a value for a learned fact, which the computer players then use instead of the answers and any built in value.
+func (base *Base) Accept(entityName string, attributeName string, value bool) error {
+	base.baseMutex.Lock()
+	defer base.baseMutex.Unlock()
+
+	_, fact, err := base.learnedFact(entityName, attributeName)
+	if err != nil {
+		return err
+	}
+	fact.Reviewed = true
+	fact.Value = value
+	base.mergeLearned()
+	return base.saveLearned()
+}
+
+// Discard a learned fact, along with the entity if it has no facts left.
+func (base *Base) Discard(entityName string, attributeName string) error {
+	base.baseMutex.Lock()
+	defer base.baseMutex.Unlock()
+
+	learnedEntity, _, err := base.learnedFact(entityName, attributeName)
+	if err != nil {
+		return err
+	}
+	delete(learnedEntity.Facts, attributeName)
+	if len(learnedEntity.Facts) == 0 {
+		delete(base.learned, normalize(entityName))
+	}
+	base.mergeLearned()
+	return base.saveLearned()
+}
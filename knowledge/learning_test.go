@@ -0,0 +1,216 @@
+package knowledge
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestLearnedFact(t *testing.T) {
+	testCases := []struct {
+		name              string
+		fact              LearnedFact
+		builtinValue      bool
+		hasBuiltinValue   bool
+		wantValue         bool
+		wantUsable        bool
+		wantContradiction bool
+	}{
+		{"single answer", LearnedFact{Yes: 1}, false, false, false, false, false},
+		{"agreeing answers", LearnedFact{Yes: 2}, false, false, true, true, false},
+		{"mostly no", LearnedFact{Yes: 1, No: 3}, false, false, false, true, true},
+		{"split answers", LearnedFact{Yes: 1, No: 1}, false, false, false, false, true},
+		{"agrees with built in yes", LearnedFact{Yes: 3}, true, true, true, true, false},
+		{"contradicts built in yes", LearnedFact{Yes: 3, No: 1}, true, true, true, true, true},
+		{"contradicts built in no", LearnedFact{Yes: 1}, false, true, false, false, true},
+		{"reviewed", LearnedFact{Yes: 5, No: 5, Reviewed: true, Value: false}, true, true, false, true, false},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			value, usable := testCase.fact.value()
+			if value != testCase.wantValue || usable != testCase.wantUsable {
+				t.Errorf("value = %v, %v, want %v, %v", value, usable, testCase.wantValue, testCase.wantUsable)
+			}
+			if got := testCase.fact.isContradiction(testCase.builtinValue, testCase.hasBuiltinValue); got != testCase.wantContradiction {
+				t.Errorf("contradiction = %v, want %v", got, testCase.wantContradiction)
+			}
+		})
+	}
+}
+
+// Find the review item for a fact, failing the test if there is none.
+func findReviewItem(t *testing.T, base *Base, entity string, attribute string) ReviewItem {
+	t.Helper()
+	for _, item := range base.Review() {
+		if item.Entity == entity && item.Attribute == attribute {
+			return item
+		}
+	}
+	t.Fatalf("no review item for %s %s", entity, attribute)
+	return ReviewItem{}
+}
+
+func TestLearn(t *testing.T) {
+	base := newTestBase(t)
+	observations := []Observation{
+		{"Can it fly?", "Yes"},
+		{"Is it large?", "Yes"},
+		{"Does it live in water?", "Maybe"},
+		{"What colour is it?", "White"},
+		{"Is it a dog?", "No"},
+	}
+	for range 2 {
+		learned, err := base.Learn("polar bear", "Ignored", observations)
+		if err != nil {
+			t.Fatalf("failed to learn: %v", err)
+		}
+		if learned != 2 {
+			t.Errorf("learned %d answers, want only the answered attribute questions", learned)
+		}
+	}
+
+	testCases := []struct {
+		name              string
+		attribute         string
+		wantYes           int
+		wantBuiltin       string
+		wantUsed          string
+		wantContradiction bool
+	}{
+		{"contradicts built in value", "canFly", 2, "No", "No", true},
+		{"fills in missing value", "isLarge", 2, "", "Yes", false},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			item := findReviewItem(t, base, "Polar Bear", testCase.attribute)
+			if item.Yes != testCase.wantYes || item.BuiltinValue != testCase.wantBuiltin || item.UsedValue != testCase.wantUsed || item.Contradiction != testCase.wantContradiction {
+				t.Errorf("got %+v, want %d yes, built in %q, used %q and contradiction %v",
+					item, testCase.wantYes, testCase.wantBuiltin, testCase.wantUsed, testCase.wantContradiction)
+			}
+		})
+	}
+
+	if items := base.Review(); !items[0].Contradiction {
+		t.Errorf("first review item %+v is not the contradiction", items[0])
+	}
+	if answer, _ := base.Answer("Polar Bear", "Is it big?"); answer != Answer_Yes {
+		t.Errorf("learned value not used, answered %s", answer)
+	}
+	if answer, _ := base.Answer("Polar Bear", "Can it fly?"); answer != Answer_No {
+		t.Errorf("built in value not kept over contradicting answers, answered %s", answer)
+	}
+	if _, err := base.Learn("  ", "Animals", observations); err == nil {
+		t.Error("learned about a blank secret")
+	}
+}
+
+func TestReviewLearnedFacts(t *testing.T) {
+	testCases := []struct {
+		name              string
+		review            func(base *Base) error
+		wantAnswer        Answer
+		wantContradiction bool
+		wantItem          bool
+	}{
+		{"not reviewed", func(base *Base) error { return nil }, Answer_No, true, true},
+		{"accepted", func(base *Base) error { return base.Accept("dog", "canFly", true) }, Answer_Yes, false, true},
+		{"discarded", func(base *Base) error { return base.Discard("Dog", "canFly") }, Answer_No, false, false},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			base := newTestBase(t)
+			if _, err := base.Learn("Dog", "Animals", []Observation{{"Can it fly?", "Yes"}}); err != nil {
+				t.Fatalf("failed to learn: %v", err)
+			}
+			if err := testCase.review(base); err != nil {
+				t.Fatalf("failed to review: %v", err)
+			}
+
+			if answer, _ := base.Answer("Dog", "Can it fly?"); answer != testCase.wantAnswer {
+				t.Errorf("answered %s, want %s", answer, testCase.wantAnswer)
+			}
+			items := base.Review()
+			if (len(items) > 0) != testCase.wantItem {
+				t.Fatalf("review items = %+v, want item %v", items, testCase.wantItem)
+			}
+			if testCase.wantItem && items[0].Contradiction != testCase.wantContradiction {
+				t.Errorf("contradiction = %v, want %v", items[0].Contradiction, testCase.wantContradiction)
+			}
+		})
+	}
+
+	base := newTestBase(t)
+	if err := base.Accept("Dog", "canFly", true); err == nil {
+		t.Error("accepted a fact that was never learned")
+	}
+}
+
+func TestLearnNewEntity(t *testing.T) {
+	// Enough attributes for a new entity to be used once every one of them is learned.
+	basePath := writeTestBase(t, `{
+  "attributes": [
+    {"name": "isAnimal", "question": "Is it an animal?", "keywords": ["animal"]},
+    {"name": "canFly", "question": "Can it fly?", "keywords": ["fly"]},
+    {"name": "livesInWater", "question": "Does it live in water?", "keywords": ["water"]},
+    {"name": "isLarge", "question": "Is it large?", "keywords": ["large"]},
+    {"name": "isPet", "question": "Is it a pet?", "keywords": ["pet"]}
+  ],
+  "entities": []
+}`)
+	base, err := Load(basePath)
+	if err != nil {
+		t.Fatalf("failed to load knowledge base: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "learned.json")
+	if err := base.UseLearnedFile(path); err != nil {
+		t.Fatalf("failed to use learned file: %v", err)
+	}
+
+	observations := []Observation{
+		{"Is it an animal?", "Yes"},
+		{"Can it fly?", "No"},
+		{"Does it live in water?", "Yes"},
+		{"Is it large?", "No"},
+		{"Is it a pet?", "Yes"},
+	}
+	testCases := []struct {
+		name         string
+		observations []Observation
+		wantUsed     bool
+	}{
+		{"single answers", observations, false},
+		{"too few attributes", observations[:4], false},
+		{"enough attributes", observations[4:], true},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			if _, err := base.Learn("Goldfish", "Animals", testCase.observations); err != nil {
+				t.Fatalf("failed to learn: %v", err)
+			}
+			if got := len(base.Entities("Animals")) > 0; got != testCase.wantUsed {
+				t.Errorf("entity used = %v, want %v", got, testCase.wantUsed)
+			}
+		})
+	}
+
+	reloaded, err := Load(writeTestBase(t, `{"attributes": [{"name": "isPet", "question": "Is it a pet?", "keywords": ["pet"]}]}`))
+	if err != nil {
+		t.Fatalf("failed to load knowledge base: %v", err)
+	}
+	if err := reloaded.UseLearnedFile(path); err == nil {
+		t.Error("loaded learned facts about attributes missing from the knowledge base")
+	}
+	reloaded, err = Load(basePath)
+	if err != nil {
+		t.Fatalf("failed to load knowledge base: %v", err)
+	}
+	if err := reloaded.UseLearnedFile(path); err != nil {
+		t.Fatalf("failed to reload learned file: %v", err)
+	}
+	if answer, correct := reloaded.Answer("goldfish", "Is it a pet?"); answer != Answer_Yes || correct {
+		t.Errorf("reloaded entity answered %s, %v, want Yes", answer, correct)
+	}
+}
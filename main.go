@@ -1,33 +1,74 @@
 package main
 
 import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"expvar"
 	"flag"
 	"fmt"
 	"html/template"
+	"io/fs"
+	"net"
 	"net/http"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc"
 	"gopkg.in/natefinch/lumberjack.v2"
 
+	"github.com/hmcalister/twentyquestions/archive"
+	"github.com/hmcalister/twentyquestions/audit"
 	"github.com/hmcalister/twentyquestions/game"
+	"github.com/hmcalister/twentyquestions/knowledge"
 	mymiddleware "github.com/hmcalister/twentyquestions/middleware"
+	"github.com/hmcalister/twentyquestions/stats"
+	"github.com/hmcalister/twentyquestions/webhooks"
+	"github.com/hmcalister/twentyquestions/wordpacks"
 )
 
 var (
 	indexTemplate = template.Must(template.New("index.html").ParseFiles("templates/index.html"))
 )
 
+const (
+	// How long to wait for requests in progress once the server is shutting down, before closing all connections.
+	shutdownTimeout time.Duration = 5 * time.Second
+)
+
 func main() {
 	// --------------------------------------------------------------------------------
 	// Flags
 	// --------------------------------------------------------------------------------
 
 	port := flag.Int("port", 3000, "The port to use for the HTTP server.")
+	grpcPort := flag.Int("grpcPort", 0, "The port to use for the gRPC server. Zero to disable the gRPC server.")
 	debugFlag := flag.Bool("debug", false, "Flag for debug level with console log outputs.")
+	maxGames := flag.Int("maxGames", 1000, "The maximum number of games alive at once. Zero for no limit.")
+	maxGamesPerIP := flag.Int("maxGamesPerIP", 10, "The maximum number of games alive at once created from a single IP address. Zero for no limit.")
+	maxConnectionsPerGame := flag.Int("maxConnectionsPerGame", 50, "The maximum number of connections to a single game. Zero for no limit.")
+	archiveDirectory := flag.String("archiveDir", "", "The directory to keep the archive of completed games in. Empty to keep the archive in memory only.")
+	wordPackDirectory := flag.String("wordPackDir", "", "A directory of custom word packs, loaded in addition to the built in packs.")
+	learnedKnowledgeFile := flag.String("learnedKnowledgeFile", "", "The file to keep the knowledge learned from completed games in. Empty to keep learned knowledge in memory only.")
+	adminPassword := flag.String("adminPassword", "", "The password for the admin pages, with the username admin. Empty to disable the admin pages.")
+	statsDirectory := flag.String("statsDir", "", "The directory to keep the results of finished rounds in, for player statistics and leaderboards. Empty to keep them in memory only.")
+	playerKeyFile := flag.String("playerKeyFile", "", "The file to keep the key player identities are signed with, created if missing, so players keep their identity and statistics across restarts. Empty for a new key on every start.")
+	archiveRetention := flag.Duration("archiveRetention", 30*24*time.Hour, "How long completed games are kept in the archive. Zero to keep games forever.")
+	webhookURL := flag.String("webhookURL", "", "A URL to send the events of every game to. Empty for no server-wide webhook.")
+	webhookSecret := flag.String("webhookSecret", "", "The secret to sign requests to the webhookURL with. Required with webhookURL.")
+	gameWebhooks := flag.Bool("gameWebhooks", false, "Flag to let the oracle of a game add webhooks for that game. Lets any player have the server send requests to any URL.")
+	auditLogFile := flag.String("auditLogFile", "./logs/audit.log", "The file to keep the audit log of game actions in, rotated alongside it. Empty to disable the audit log.")
+	trustedProxies := flag.String("trustedProxies", "", "Comma separated IP addresses or CIDR ranges of reverse proxies trusted to give the client address in X-Forwarded-For or X-Real-IP. Empty to always use the address of the connection.")
+	drainPeriod := flag.Duration("drainPeriod", 10*time.Second, "How long to report not ready before shutting down on SIGINT or SIGTERM, so load balancers can stop sending players.")
 	flag.Parse()
 
 	// --------------------------------------------------------------------------------
@@ -59,8 +100,15 @@ func main() {
 	// Router and Middlewares
 	// --------------------------------------------------------------------------------
 
+	trustedProxyPrefixes, err := mymiddleware.ParseTrustedProxies(*trustedProxies)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to parse trusted proxies")
+	}
+
 	router := chi.NewRouter()
-	router.Use(mymiddleware.ZerologLogger)
+	router.Use(mymiddleware.RealIPFromTrustedProxies(trustedProxyPrefixes))
+	// Health checks are requested often, so are not logged.
+	router.Use(mymiddleware.ZerologLoggerExcept("/healthz", "/readyz", "/version"))
 	router.Use(mymiddleware.RecoverWithInternalServerError)
 	router.Use(middleware.NoCache)
 
@@ -72,31 +120,208 @@ func main() {
 	router.Handle("/static/*", http.StripPrefix("/static/", staticFS))
 
 	// --------------------------------------------------------------------------------
-	// Home Template
+	// Game Router
 	// --------------------------------------------------------------------------------
 
-	router.Get("/", func(w http.ResponseWriter, r *http.Request) {
-		err := indexTemplate.Execute(w, nil)
+	// The built in word packs are always loaded, along with any custom packs.
+	wordPackDirectories := []string{"data/wordpacks"}
+	if *wordPackDirectory != "" {
+		wordPackDirectories = append(wordPackDirectories, *wordPackDirectory)
+	}
+	wordPacks, err := wordpacks.Load(wordPackDirectories...)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to load word packs")
+	}
+
+	knowledgeBase, err := knowledge.Load("data/knowledge/base.json")
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to load knowledge base")
+	}
+	if *learnedKnowledgeFile != "" {
+		err = knowledgeBase.UseLearnedFile(*learnedKnowledgeFile)
 		if err != nil {
-			log.Error().Err(err).Msg("Failed to execute indexTemplate")
+			log.Fatal().Err(err).Msg("Failed to load learned knowledge")
 		}
-	})
+	}
+
+	// Completed games are archived in memory, or on disk if an archive directory is given.
+	var archiveStore archive.Store = archive.NewMemoryStore()
+	if *archiveDirectory != "" {
+		diskStore, err := archive.NewDiskStore(*archiveDirectory)
+		if err != nil {
+			log.Fatal().Err(err).Msg("Failed to open archive directory")
+		}
+		archiveStore = diskStore
+	}
+
+	// Round results for player statistics are kept in memory, or on disk if a stats directory is given.
+	statsTracker := stats.NewTracker()
+	if *statsDirectory != "" {
+		statsTracker, err = stats.OpenTracker(*statsDirectory)
+		if err != nil {
+			log.Fatal().Err(err).Msg("Failed to open stats directory")
+		}
+	}
+
+	// Players are new on every start unless the key their identities are signed with is kept.
+	var playerKey []byte
+	if *playerKeyFile != "" {
+		playerKey, err = loadOrCreatePlayerKey(*playerKeyFile)
+		if err != nil {
+			log.Fatal().Err(err).Msg("Failed to load player key")
+		}
+	}
+
+	// Game events are sent to the server-wide webhook given on the command line, along with any added by the admin or the games.
+	webhookDispatcher := webhooks.NewDispatcher(*gameWebhooks)
+	if *webhookURL != "" {
+		if *webhookSecret == "" {
+			log.Fatal().Msg("A webhookSecret is required with the webhookURL")
+		}
+		_, err = webhookDispatcher.Subscribe(*webhookURL, *webhookSecret, "", nil)
+		if err != nil {
+			log.Fatal().Err(err).Msg("Failed to subscribe webhookURL")
+		}
+	}
+
+	// Game actions are audited in their own file, apart from the server log.
+	var auditLog *audit.Log
+	if *auditLogFile != "" {
+		auditLog, err = audit.Open(*auditLogFile)
+		if err != nil {
+			log.Fatal().Err(err).Msg("Failed to open audit log")
+		}
+	}
+
+	gameRouter := game.NewGameMaster(game.CapacityLimits{
+		MaxGames:              *maxGames,
+		MaxGamesPerIP:         *maxGamesPerIP,
+		MaxConnectionsPerGame: *maxConnectionsPerGame,
+	}, archive.New(archiveStore, *archiveRetention), statsTracker, playerKey, wordPacks, knowledgeBase, webhookDispatcher, auditLog)
+	router.Mount("/game", gameRouter.Router)
+	router.Mount("/lobby", gameRouter.LobbyRouter)
+	router.Mount("/api", gameRouter.APIRouter)
+	router.Mount("/archive", gameRouter.ArchiveRouter)
+	router.Mount("/leaderboard", gameRouter.LeaderboardRouter)
+
+	// The admin pages, and the metrics, are only available with a password.
+	gameRouter.AdminRouter.Handle("/debug/vars", expvar.Handler())
+	if *adminPassword != "" {
+		// The browser sends the password with every request, so forms on other sites must not be able to use it.
+		router.With(
+			middleware.BasicAuth("Twenty Questions Admin", map[string]string{"admin": *adminPassword}),
+			mymiddleware.RejectCrossOrigin,
+		).Mount("/admin", gameRouter.AdminRouter)
+	}
 
 	// --------------------------------------------------------------------------------
-	// Game Router
+	// Health Checks
 	// --------------------------------------------------------------------------------
 
-	gameRouter := game.NewGameMaster()
-	router.Mount("/game", gameRouter.Router)
+	router.Get("/healthz", gameRouter.ServeHealth)
+	router.Get("/readyz", gameRouter.ServeReadiness)
+	router.Get("/version", gameRouter.ServeVersion)
+
+	// --------------------------------------------------------------------------------
+	// Home Template
+	// --------------------------------------------------------------------------------
+
+	router.Get("/", func(w http.ResponseWriter, r *http.Request) {
+		err := indexTemplate.Execute(w, struct {
+			Categories []string
+		}{
+			Categories: gameRouter.Categories(),
+		})
+		if err != nil {
+			log.Error().Err(err).Msg("Failed to execute indexTemplate")
+		}
+	})
 
 	// --------------------------------------------------------------------------------
 	// Serve
 	// --------------------------------------------------------------------------------
 
+	// The gRPC server plays the same games as the HTTP server, on its own port.
+	var grpcServer *grpc.Server
+	if *grpcPort != 0 {
+		grpcBindAddress := fmt.Sprintf("localhost:%v", *grpcPort)
+		grpcListener, err := net.Listen("tcp", grpcBindAddress)
+		if err != nil {
+			log.Fatal().Err(err).Msg("Failed to listen for gRPC")
+		}
+		grpcServer = grpc.NewServer()
+		gameRouter.RegisterGRPC(grpcServer)
+		go func() {
+			log.Info().Msgf("Starting gRPC server on %v", grpcBindAddress)
+			err := grpcServer.Serve(grpcListener)
+			if err != nil {
+				log.Fatal().Err(err).Msg("Error during gRPC serve")
+			}
+		}()
+	}
+
 	targetBindAddress := fmt.Sprintf("localhost:%v", *port)
+	server := &http.Server{
+		Addr:    targetBindAddress,
+		Handler: router,
+	}
+
+	// On SIGINT or SIGTERM, report not ready for the drain period, then shut down. Connections still open
+	// after the shutdown timeout (such as SSE connections) are closed.
+	shutdownComplete := make(chan struct{})
+	go func() {
+		signals := make(chan os.Signal, 1)
+		signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+		<-signals
+
+		gameRouter.Drain()
+		time.Sleep(*drainPeriod)
+
+		log.Info().Msg("Shutting down")
+		if grpcServer != nil {
+			grpcServer.Stop()
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		err := server.Shutdown(ctx)
+		if err != nil {
+			server.Close()
+		}
+		close(shutdownComplete)
+	}()
+
 	log.Info().Msgf("Starting server on %v", targetBindAddress)
-	err := http.ListenAndServe(targetBindAddress, router)
-	if err != nil {
+	err = server.ListenAndServe()
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
 		log.Fatal().Err(err).Msg("Error during http listen and serve")
 	}
+	<-shutdownComplete
+}
+
+// Read the key player identities are signed with from the file, or create the file with a new random key if it does not exist.
+func loadOrCreatePlayerKey(filename string) ([]byte, error) {
+	encodedKey, err := os.ReadFile(filename)
+	if err == nil {
+		key, err := hex.DecodeString(strings.TrimSpace(string(encodedKey)))
+		if err != nil || len(key) == 0 {
+			return nil, fmt.Errorf("player key file %s is not a hex key", filename)
+		}
+		return key, nil
+	}
+	if !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+
+	key := make([]byte, 64)
+	_, err = rand.Read(key)
+	if err != nil {
+		return nil, err
+	}
+	err = os.MkdirAll(filepath.Dir(filename), 0755)
+	if err != nil {
+		return nil, err
+	}
+	// Anyone with the key can sign in as any player, so only the server may read it.
+	err = os.WriteFile(filename, []byte(hex.EncodeToString(key)+"\n"), 0600)
+	return key, err
 }
@@ -0,0 +1,91 @@
+package middleware
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// Parse a comma separated list of IP addresses and CIDR ranges, e.g. "10.0.0.1,192.168.0.0/16".
+func ParseTrustedProxies(value string) ([]netip.Prefix, error) {
+	trustedProxies := make([]netip.Prefix, 0)
+	for _, field := range strings.Split(value, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		if strings.Contains(field, "/") {
+			prefix, err := netip.ParsePrefix(field)
+			if err != nil {
+				return nil, fmt.Errorf("invalid trusted proxy %q: %w", field, err)
+			}
+			trustedProxies = append(trustedProxies, prefix.Masked())
+			continue
+		}
+		address, err := netip.ParseAddr(field)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q: %w", field, err)
+		}
+		trustedProxies = append(trustedProxies, netip.PrefixFrom(address, address.BitLen()))
+	}
+	return trustedProxies, nil
+}
+
+// Set the remote address of a request to the client address given by a trusted proxy, in the X-Forwarded-For or X-Real-IP header.
+//
+// Headers are only believed if the request came directly from one of the trusted proxies, so clients cannot choose their own address.
+// The client is the last address in X-Forwarded-For that is not itself a trusted proxy, as earlier addresses may be forged by the client.
+func RealIPFromTrustedProxies(trustedProxies []netip.Prefix) func(http.Handler) http.Handler {
+	isTrusted := func(address netip.Addr) bool {
+		for _, prefix := range trustedProxies {
+			if prefix.Contains(address.Unmap()) {
+				return true
+			}
+		}
+		return false
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			remoteAddress, err := remoteAddr(r)
+			if err != nil || !isTrusted(remoteAddress) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			clientAddress := ""
+			forwardedFor := strings.Split(strings.Join(r.Header.Values("X-Forwarded-For"), ","), ",")
+			for i := len(forwardedFor) - 1; i >= 0; i-- {
+				address, err := netip.ParseAddr(strings.TrimSpace(forwardedFor[i]))
+				if err != nil {
+					break
+				}
+				clientAddress = address.String()
+				if !isTrusted(address) {
+					break
+				}
+			}
+			if clientAddress == "" {
+				if address, err := netip.ParseAddr(strings.TrimSpace(r.Header.Get("X-Real-IP"))); err == nil {
+					clientAddress = address.String()
+				}
+			}
+
+			if clientAddress != "" {
+				r.RemoteAddr = net.JoinHostPort(clientAddress, "0")
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// Get the IP address a request came from directly.
+func remoteAddr(r *http.Request) (netip.Addr, error) {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return netip.ParseAddr(host)
+}
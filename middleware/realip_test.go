@@ -0,0 +1,67 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRealIPFromTrustedProxies(t *testing.T) {
+	trustedProxies, err := ParseTrustedProxies("10.0.0.1, 192.168.0.0/16")
+	if err != nil {
+		t.Fatalf("failed to parse trusted proxies: %v", err)
+	}
+
+	testCases := []struct {
+		name         string
+		remoteAddr   string
+		forwardedFor string
+		realIP       string
+		wantRemoteIP string
+	}{
+		{"untrusted client forging forwarded for", "203.0.113.7:5000", "1.2.3.4", "", "203.0.113.7"},
+		{"untrusted client forging real IP", "203.0.113.7:5000", "", "1.2.3.4", "203.0.113.7"},
+		{"trusted proxy", "10.0.0.1:5000", "198.51.100.2", "", "198.51.100.2"},
+		{"trusted proxy in range", "192.168.4.5:5000", "198.51.100.2", "", "198.51.100.2"},
+		{"client forging an earlier hop", "10.0.0.1:5000", "1.2.3.4, 198.51.100.2", "", "198.51.100.2"},
+		{"chain of trusted proxies", "10.0.0.1:5000", "198.51.100.2, 192.168.0.9", "", "198.51.100.2"},
+		{"trusted proxy with real IP", "10.0.0.1:5000", "", "198.51.100.2", "198.51.100.2"},
+		{"trusted proxy without headers", "10.0.0.1:5000", "", "", "10.0.0.1"},
+		{"trusted proxy with malformed header", "10.0.0.1:5000", "not an address", "", "10.0.0.1"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			var gotRemoteAddr string
+			handler := RealIPFromTrustedProxies(trustedProxies)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotRemoteAddr = r.RemoteAddr
+			}))
+
+			request := httptest.NewRequest(http.MethodGet, "/", nil)
+			request.RemoteAddr = testCase.remoteAddr
+			if testCase.forwardedFor != "" {
+				request.Header.Set("X-Forwarded-For", testCase.forwardedFor)
+			}
+			if testCase.realIP != "" {
+				request.Header.Set("X-Real-IP", testCase.realIP)
+			}
+			handler.ServeHTTP(httptest.NewRecorder(), request)
+
+			gotAddress, err := remoteAddr(&http.Request{RemoteAddr: gotRemoteAddr})
+			if err != nil {
+				t.Fatalf("remote address %q is not an address: %v", gotRemoteAddr, err)
+			}
+			if gotAddress.String() != testCase.wantRemoteIP {
+				t.Errorf("remote address = %s, want %s", gotAddress, testCase.wantRemoteIP)
+			}
+		})
+	}
+}
+
+func TestParseTrustedProxiesRejectsInvalid(t *testing.T) {
+	for _, value := range []string{"not an address", "10.0.0.0/99"} {
+		if _, err := ParseTrustedProxies(value); err == nil {
+			t.Errorf("ParseTrustedProxies(%q) succeeded, want an error", value)
+		}
+	}
+}
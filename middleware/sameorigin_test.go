@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRejectCrossOrigin(t *testing.T) {
+	testCases := []struct {
+		name         string
+		method       string
+		secFetchSite string
+		origin       string
+		wantStatus   int
+	}{
+		{"safe method from another site", http.MethodGet, "cross-site", "https://evil.example", http.StatusOK},
+		{"same origin", http.MethodPost, "same-origin", "http://game.example", http.StatusOK},
+		{"typed into the address bar", http.MethodPost, "none", "", http.StatusOK},
+		{"another site", http.MethodPost, "cross-site", "https://evil.example", http.StatusForbidden},
+		{"same site, other origin", http.MethodPost, "same-site", "https://other.game.example", http.StatusForbidden},
+		{"another site without fetch metadata", http.MethodPost, "", "https://evil.example", http.StatusForbidden},
+		{"same origin without fetch metadata", http.MethodDelete, "", "http://game.example", http.StatusOK},
+		{"not a browser", http.MethodPost, "", "", http.StatusOK},
+	}
+
+	handler := RejectCrossOrigin(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			request := httptest.NewRequest(testCase.method, "http://game.example/admin/notice", nil)
+			if testCase.secFetchSite != "" {
+				request.Header.Set("Sec-Fetch-Site", testCase.secFetchSite)
+			}
+			if testCase.origin != "" {
+				request.Header.Set("Origin", testCase.origin)
+			}
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, request)
+			if recorder.Code != testCase.wantStatus {
+				t.Errorf("status = %d, want %d", recorder.Code, testCase.wantStatus)
+			}
+		})
+	}
+}
@@ -14,24 +14,44 @@ import (
 //
 // Inspiration taken from https://github.com/ironstar-io/chizerolog/blob/master/main.go
 func ZerologLogger(next http.Handler) http.Handler {
+	return ZerologLoggerExcept()(next)
+}
+
+// Perform logging of requests as ZerologLogger, except for requests to the given paths -- such as health checks,
+// which are requested often and would drown out other requests.
+func ZerologLoggerExcept(paths ...string) func(http.Handler) http.Handler {
+	unloggedPaths := make(map[string]bool, len(paths))
+	for _, path := range paths {
+		unloggedPaths[path] = true
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if unloggedPaths[r.URL.Path] {
+				next.ServeHTTP(w, r)
+				return
+			}
+			logRequest(next, w, r)
+		})
+	}
+}
+
+// Serve a request, then log it.
+func logRequest(next http.Handler, w http.ResponseWriter, r *http.Request) {
+	wrappedWriter := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+	requestTimeReceived := time.Now()
+
+	next.ServeHTTP(wrappedWriter, r)
 
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-
-		wrappedWriter := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
-		requestTimeReceived := time.Now()
-
-		next.ServeHTTP(wrappedWriter, r)
-
-		requestTimeResolved := time.Now()
-		log.Info().
-			Str("URL", r.URL.Path).
-			Str("Protocol", r.Proto).
-			Str("RemoteIP", r.RemoteAddr).
-			Int("Status", wrappedWriter.Status()).
-			Str("UserAgent", r.Header.Get("User-Agent")).
-			Float32("Latency_ms", float32(requestTimeResolved.Sub(requestTimeReceived).Nanoseconds()/1_000_000.0)).
-			Int64("BytesReceived", r.ContentLength).
-			Int("BytesSent", wrappedWriter.BytesWritten()).
-			Send()
-	})
+	requestTimeResolved := time.Now()
+	log.Info().
+		Str("URL", r.URL.Path).
+		Str("Protocol", r.Proto).
+		Str("RemoteIP", r.RemoteAddr).
+		Int("Status", wrappedWriter.Status()).
+		Str("UserAgent", r.Header.Get("User-Agent")).
+		Float32("Latency_ms", float32(requestTimeResolved.Sub(requestTimeReceived).Nanoseconds()/1_000_000.0)).
+		Int64("BytesReceived", r.ContentLength).
+		Int("BytesSent", wrappedWriter.BytesWritten()).
+		Send()
 }
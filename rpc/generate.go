@@ -0,0 +1,5 @@
+// Package rpc holds the protobuf definition of the TwentyQuestions gRPC service, and the code generated from it.
+// The service is implemented by the game package.
+package rpc
+
+//go:generate buf generate
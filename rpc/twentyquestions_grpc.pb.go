@@ -0,0 +1,356 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.4.0
+// - protoc             (unknown)
+// source: twentyquestions.proto
+
+package rpc
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.62.0 or later.
+const _ = grpc.SupportPackageIsVersion8
+
+const (
+	TwentyQuestions_CreateGame_FullMethodName = "/twentyquestions.v1.TwentyQuestions/CreateGame"
+	TwentyQuestions_JoinGame_FullMethodName   = "/twentyquestions.v1.TwentyQuestions/JoinGame"
+	TwentyQuestions_Ask_FullMethodName        = "/twentyquestions.v1.TwentyQuestions/Ask"
+	TwentyQuestions_Answer_FullMethodName     = "/twentyquestions.v1.TwentyQuestions/Answer"
+	TwentyQuestions_Verdict_FullMethodName    = "/twentyquestions.v1.TwentyQuestions/Verdict"
+	TwentyQuestions_WatchGame_FullMethodName  = "/twentyquestions.v1.TwentyQuestions/WatchGame"
+)
+
+// TwentyQuestionsClient is the client API for TwentyQuestions service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Twenty Questions over gRPC, for services that prefer it to the HTML forms. Backed by the same games as the web server.
+//
+// Players are identified by the "player" metadata key, holding the same token as the "player" cookie of the web server.
+// Calls without a valid token are made as a new player, and the token for that player is sent back in the "player"
+// header -- send it with every later call to act as the same player. The token is also reissued when the name changes.
+//
+// Games with a passphrase also need the "game-token" metadata key, holding the token returned by JoinGame.
+type TwentyQuestionsClient interface {
+	// Create a new game, with the calling player as the oracle of the first round unless playing against the computer oracle.
+	CreateGame(ctx context.Context, in *CreateGameRequest, opts ...grpc.CallOption) (*CreateGameResponse, error)
+	// Join a game, entering the passphrase if the game is protected.
+	JoinGame(ctx context.Context, in *JoinGameRequest, opts ...grpc.CallOption) (*JoinGameResponse, error)
+	// Ask a question as a guesser.
+	Ask(ctx context.Context, in *AskRequest, opts ...grpc.CallOption) (*GameState, error)
+	// Answer the pending question as the oracle.
+	Answer(ctx context.Context, in *AnswerRequest, opts ...grpc.CallOption) (*GameState, error)
+	// End the round with a verdict as the oracle.
+	Verdict(ctx context.Context, in *VerdictRequest, opts ...grpc.CallOption) (*GameState, error)
+	// Watch the current state of the game -- sent once on connection, then after every change.
+	WatchGame(ctx context.Context, in *WatchGameRequest, opts ...grpc.CallOption) (TwentyQuestions_WatchGameClient, error)
+}
+
+type twentyQuestionsClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewTwentyQuestionsClient(cc grpc.ClientConnInterface) TwentyQuestionsClient {
+	return &twentyQuestionsClient{cc}
+}
+
+func (c *twentyQuestionsClient) CreateGame(ctx context.Context, in *CreateGameRequest, opts ...grpc.CallOption) (*CreateGameResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateGameResponse)
+	err := c.cc.Invoke(ctx, TwentyQuestions_CreateGame_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *twentyQuestionsClient) JoinGame(ctx context.Context, in *JoinGameRequest, opts ...grpc.CallOption) (*JoinGameResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(JoinGameResponse)
+	err := c.cc.Invoke(ctx, TwentyQuestions_JoinGame_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *twentyQuestionsClient) Ask(ctx context.Context, in *AskRequest, opts ...grpc.CallOption) (*GameState, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GameState)
+	err := c.cc.Invoke(ctx, TwentyQuestions_Ask_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *twentyQuestionsClient) Answer(ctx context.Context, in *AnswerRequest, opts ...grpc.CallOption) (*GameState, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GameState)
+	err := c.cc.Invoke(ctx, TwentyQuestions_Answer_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *twentyQuestionsClient) Verdict(ctx context.Context, in *VerdictRequest, opts ...grpc.CallOption) (*GameState, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GameState)
+	err := c.cc.Invoke(ctx, TwentyQuestions_Verdict_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *twentyQuestionsClient) WatchGame(ctx context.Context, in *WatchGameRequest, opts ...grpc.CallOption) (TwentyQuestions_WatchGameClient, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &TwentyQuestions_ServiceDesc.Streams[0], TwentyQuestions_WatchGame_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &twentyQuestionsWatchGameClient{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type TwentyQuestions_WatchGameClient interface {
+	Recv() (*GameState, error)
+	grpc.ClientStream
+}
+
+type twentyQuestionsWatchGameClient struct {
+	grpc.ClientStream
+}
+
+func (x *twentyQuestionsWatchGameClient) Recv() (*GameState, error) {
+	m := new(GameState)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// TwentyQuestionsServer is the server API for TwentyQuestions service.
+// All implementations must embed UnimplementedTwentyQuestionsServer
+// for forward compatibility
+//
+// Twenty Questions over gRPC, for services that prefer it to the HTML forms. Backed by the same games as the web server.
+//
+// Players are identified by the "player" metadata key, holding the same token as the "player" cookie of the web server.
+// Calls without a valid token are made as a new player, and the token for that player is sent back in the "player"
+// header -- send it with every later call to act as the same player. The token is also reissued when the name changes.
+//
+// Games with a passphrase also need the "game-token" metadata key, holding the token returned by JoinGame.
+type TwentyQuestionsServer interface {
+	// Create a new game, with the calling player as the oracle of the first round unless playing against the computer oracle.
+	CreateGame(context.Context, *CreateGameRequest) (*CreateGameResponse, error)
+	// Join a game, entering the passphrase if the game is protected.
+	JoinGame(context.Context, *JoinGameRequest) (*JoinGameResponse, error)
+	// Ask a question as a guesser.
+	Ask(context.Context, *AskRequest) (*GameState, error)
+	// Answer the pending question as the oracle.
+	Answer(context.Context, *AnswerRequest) (*GameState, error)
+	// End the round with a verdict as the oracle.
+	Verdict(context.Context, *VerdictRequest) (*GameState, error)
+	// Watch the current state of the game -- sent once on connection, then after every change.
+	WatchGame(*WatchGameRequest, TwentyQuestions_WatchGameServer) error
+	mustEmbedUnimplementedTwentyQuestionsServer()
+}
+
+// UnimplementedTwentyQuestionsServer must be embedded to have forward compatible implementations.
+type UnimplementedTwentyQuestionsServer struct {
+}
+
+func (UnimplementedTwentyQuestionsServer) CreateGame(context.Context, *CreateGameRequest) (*CreateGameResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateGame not implemented")
+}
+func (UnimplementedTwentyQuestionsServer) JoinGame(context.Context, *JoinGameRequest) (*JoinGameResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method JoinGame not implemented")
+}
+func (UnimplementedTwentyQuestionsServer) Ask(context.Context, *AskRequest) (*GameState, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Ask not implemented")
+}
+func (UnimplementedTwentyQuestionsServer) Answer(context.Context, *AnswerRequest) (*GameState, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Answer not implemented")
+}
+func (UnimplementedTwentyQuestionsServer) Verdict(context.Context, *VerdictRequest) (*GameState, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Verdict not implemented")
+}
+func (UnimplementedTwentyQuestionsServer) WatchGame(*WatchGameRequest, TwentyQuestions_WatchGameServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchGame not implemented")
+}
+func (UnimplementedTwentyQuestionsServer) mustEmbedUnimplementedTwentyQuestionsServer() {}
+
+// UnsafeTwentyQuestionsServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to TwentyQuestionsServer will
+// result in compilation errors.
+type UnsafeTwentyQuestionsServer interface {
+	mustEmbedUnimplementedTwentyQuestionsServer()
+}
+
+func RegisterTwentyQuestionsServer(s grpc.ServiceRegistrar, srv TwentyQuestionsServer) {
+	s.RegisterService(&TwentyQuestions_ServiceDesc, srv)
+}
+
+func _TwentyQuestions_CreateGame_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateGameRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TwentyQuestionsServer).CreateGame(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TwentyQuestions_CreateGame_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TwentyQuestionsServer).CreateGame(ctx, req.(*CreateGameRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TwentyQuestions_JoinGame_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(JoinGameRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TwentyQuestionsServer).JoinGame(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TwentyQuestions_JoinGame_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TwentyQuestionsServer).JoinGame(ctx, req.(*JoinGameRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TwentyQuestions_Ask_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AskRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TwentyQuestionsServer).Ask(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TwentyQuestions_Ask_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TwentyQuestionsServer).Ask(ctx, req.(*AskRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TwentyQuestions_Answer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AnswerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TwentyQuestionsServer).Answer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TwentyQuestions_Answer_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TwentyQuestionsServer).Answer(ctx, req.(*AnswerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TwentyQuestions_Verdict_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VerdictRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TwentyQuestionsServer).Verdict(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TwentyQuestions_Verdict_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TwentyQuestionsServer).Verdict(ctx, req.(*VerdictRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TwentyQuestions_WatchGame_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchGameRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TwentyQuestionsServer).WatchGame(m, &twentyQuestionsWatchGameServer{ServerStream: stream})
+}
+
+type TwentyQuestions_WatchGameServer interface {
+	Send(*GameState) error
+	grpc.ServerStream
+}
+
+type twentyQuestionsWatchGameServer struct {
+	grpc.ServerStream
+}
+
+func (x *twentyQuestionsWatchGameServer) Send(m *GameState) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// TwentyQuestions_ServiceDesc is the grpc.ServiceDesc for TwentyQuestions service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var TwentyQuestions_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "twentyquestions.v1.TwentyQuestions",
+	HandlerType: (*TwentyQuestionsServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateGame",
+			Handler:    _TwentyQuestions_CreateGame_Handler,
+		},
+		{
+			MethodName: "JoinGame",
+			Handler:    _TwentyQuestions_JoinGame_Handler,
+		},
+		{
+			MethodName: "Ask",
+			Handler:    _TwentyQuestions_Ask_Handler,
+		},
+		{
+			MethodName: "Answer",
+			Handler:    _TwentyQuestions_Answer_Handler,
+		},
+		{
+			MethodName: "Verdict",
+			Handler:    _TwentyQuestions_Verdict_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchGame",
+			Handler:       _TwentyQuestions_WatchGame_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "twentyquestions.proto",
+}
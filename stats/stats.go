@@ -0,0 +1,324 @@
+package stats
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// Maximum number of round results kept -- the oldest results are dropped first.
+	maxResults int = 100000
+)
+
+// Enum for Window, the time windows statistics can be filtered by.
+type Window int
+
+const (
+	Window_All   Window = iota
+	Window_Day   Window = iota
+	Window_Week  Window = iota
+	Window_Month Window = iota
+)
+
+// Parse a window from its name, as used in URLs.
+func ParseWindow(name string) (Window, error) {
+	switch name {
+	case "all", "":
+		return Window_All, nil
+	case "day":
+		return Window_Day, nil
+	case "week":
+		return Window_Week, nil
+	case "month":
+		return Window_Month, nil
+	default:
+		return Window_All, errors.New("unknown time window")
+	}
+}
+
+// The name of the window, as used in URLs.
+func (window Window) String() string {
+	switch window {
+	case Window_Day:
+		return "day"
+	case Window_Week:
+		return "week"
+	case Window_Month:
+		return "month"
+	default:
+		return "all"
+	}
+}
+
+// The earliest time included in the window, or the zero time for all time.
+func (window Window) Since(now time.Time) time.Time {
+	switch window {
+	case Window_Day:
+		return now.Add(-24 * time.Hour)
+	case Window_Week:
+		return now.Add(-7 * 24 * time.Hour)
+	case Window_Month:
+		return now.Add(-30 * 24 * time.Hour)
+	default:
+		return time.Time{}
+	}
+}
+
+// Enum for Metric, the statistics a leaderboard can be ranked by.
+type Metric int
+
+const (
+	Metric_Wins           Metric = iota
+	Metric_GamesPlayed    Metric = iota
+	Metric_QuestionsToWin Metric = iota
+	Metric_OracleGames    Metric = iota
+	Metric_AnswerLatency  Metric = iota
+)
+
+// Parse a metric from its name, as used in URLs.
+func ParseMetric(name string) (Metric, error) {
+	switch name {
+	case "wins", "":
+		return Metric_Wins, nil
+	case "games":
+		return Metric_GamesPlayed, nil
+	case "questions":
+		return Metric_QuestionsToWin, nil
+	case "oracle":
+		return Metric_OracleGames, nil
+	case "latency":
+		return Metric_AnswerLatency, nil
+	default:
+		return Metric_Wins, errors.New("unknown metric")
+	}
+}
+
+// The name of the metric, as used in URLs.
+func (metric Metric) String() string {
+	switch metric {
+	case Metric_GamesPlayed:
+		return "games"
+	case Metric_QuestionsToWin:
+		return "questions"
+	case Metric_OracleGames:
+		return "oracle"
+	case Metric_AnswerLatency:
+		return "latency"
+	default:
+		return "wins"
+	}
+}
+
+// --------------------------------------------------------------------------------
+// Round Results and Player Statistics
+// --------------------------------------------------------------------------------
+
+// A player taking part in a round.
+type Player struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// The result of a single finished round -- each round is one game of twenty questions.
+type RoundResult struct {
+	EndedAt time.Time `json:"endedAt"`
+
+	// The oracle of the round, or a zero Player if the oracle was not a person, e.g. the computer oracle.
+	// Rounds without an oracle still count for the guessers.
+	Oracle   Player   `json:"oracle"`
+	Guessers []Player `json:"guessers"`
+
+	// The guesser who asked the final question of a correct round, or empty if the guessers were incorrect.
+	WinnerID string `json:"winnerID,omitempty"`
+
+	// The number of questions asked in the round, excluding hints and retracted questions.
+	QuestionsAsked int `json:"questionsAsked"`
+
+	// How long the oracle took to answer each question.
+	AnswerDurations []time.Duration `json:"answerDurations"`
+}
+
+// Aggregate statistics of a single player over a time window.
+type PlayerStats struct {
+	PlayerID string `json:"playerID"`
+	Name     string `json:"name"`
+
+	// Rounds played as either guesser or oracle.
+	GamesPlayed int `json:"gamesPlayed"`
+
+	// Rounds won by asking the final question as a guesser, and the average number of questions asked in those rounds.
+	GuesserWins             int     `json:"guesserWins"`
+	AverageQuestionsToSolve float64 `json:"averageQuestionsToSolve"`
+
+	// Rounds hosted as the oracle, and the average time taken to answer a question.
+	OracleGames          int     `json:"oracleGames"`
+	AverageAnswerSeconds float64 `json:"averageAnswerSeconds"`
+
+	// Totals used to find the averages.
+	questionsToSolve int
+	answers          int
+	answerDuration   time.Duration
+}
+
+// Check if the player has a value for the metric -- players are left off leaderboards for metrics they have no value for.
+func (playerStats PlayerStats) hasMetric(metric Metric) bool {
+	switch metric {
+	case Metric_QuestionsToWin:
+		return playerStats.GuesserWins > 0
+	case Metric_OracleGames:
+		return playerStats.OracleGames > 0
+	case Metric_AnswerLatency:
+		return playerStats.answers > 0
+	default:
+		return true
+	}
+}
+
+// Check if the player ranks above another for the metric. Fewer questions and faster answers rank higher.
+func (playerStats PlayerStats) ranksAbove(other PlayerStats, metric Metric) bool {
+	switch metric {
+	case Metric_GamesPlayed:
+		return playerStats.GamesPlayed > other.GamesPlayed
+	case Metric_QuestionsToWin:
+		return playerStats.AverageQuestionsToSolve < other.AverageQuestionsToSolve
+	case Metric_OracleGames:
+		return playerStats.OracleGames > other.OracleGames
+	case Metric_AnswerLatency:
+		return playerStats.AverageAnswerSeconds < other.AverageAnswerSeconds
+	default:
+		return playerStats.GuesserWins > other.GuesserWins
+	}
+}
+
+// --------------------------------------------------------------------------------
+// Tracker
+// --------------------------------------------------------------------------------
+
+// Records the results of finished rounds and aggregates them into player statistics.
+// Results are kept in memory, and also appended to a file if the tracker was opened from a directory.
+type Tracker struct {
+	// All recorded results, oldest first.
+	results []RoundResult
+
+	// The directory the results file is kept in, or empty if the results are only kept in memory.
+	directory string
+
+	// Mutex to handle async recording and reading of results, and to ensure the file is written in the same order.
+	resultsMutex sync.RWMutex
+}
+
+// Create a tracker keeping results in memory only -- the statistics are lost when the server restarts.
+func NewTracker() *Tracker {
+	return &Tracker{
+		results: make([]RoundResult, 0),
+	}
+}
+
+// Record the result of a finished round, appending it to the results file if there is one.
+// The result is kept in memory even if it could not be saved.
+func (tracker *Tracker) RecordRound(result RoundResult) error {
+	tracker.resultsMutex.Lock()
+	defer tracker.resultsMutex.Unlock()
+
+	tracker.results = append(tracker.results, result)
+	if len(tracker.results) > maxResults {
+		tracker.results = tracker.results[len(tracker.results)-maxResults:]
+	}
+	if tracker.directory == "" {
+		return nil
+	}
+	return appendResult(tracker.resultsPath(), result)
+}
+
+// Aggregate the statistics of all players over the rounds that ended within the window.
+func (tracker *Tracker) aggregate(window Window) map[string]*PlayerStats {
+	since := window.Since(time.Now())
+	allStats := make(map[string]*PlayerStats)
+
+	// Results are recorded in order, so later rounds update the name to the most recent one used.
+	statsFor := func(player Player) *PlayerStats {
+		playerStats, ok := allStats[player.ID]
+		if !ok {
+			playerStats = &PlayerStats{PlayerID: player.ID}
+			allStats[player.ID] = playerStats
+		}
+		playerStats.Name = player.Name
+		return playerStats
+	}
+
+	tracker.resultsMutex.RLock()
+	defer tracker.resultsMutex.RUnlock()
+	for _, result := range tracker.results {
+		if result.EndedAt.Before(since) {
+			continue
+		}
+
+		if result.Oracle.ID != "" {
+			oracleStats := statsFor(result.Oracle)
+			oracleStats.GamesPlayed += 1
+			oracleStats.OracleGames += 1
+			for _, answerDuration := range result.AnswerDurations {
+				oracleStats.answers += 1
+				oracleStats.answerDuration += answerDuration
+			}
+		}
+
+		for _, guesser := range result.Guessers {
+			guesserStats := statsFor(guesser)
+			guesserStats.GamesPlayed += 1
+			if guesser.ID == result.WinnerID {
+				guesserStats.GuesserWins += 1
+				guesserStats.questionsToSolve += result.QuestionsAsked
+			}
+		}
+	}
+
+	for _, playerStats := range allStats {
+		if playerStats.GuesserWins > 0 {
+			playerStats.AverageQuestionsToSolve = float64(playerStats.questionsToSolve) / float64(playerStats.GuesserWins)
+		}
+		if playerStats.answers > 0 {
+			playerStats.AverageAnswerSeconds = playerStats.answerDuration.Seconds() / float64(playerStats.answers)
+		}
+	}
+	return allStats
+}
+
+// Get the statistics of a single player within the window. Returns false if the player has not played in the window.
+func (tracker *Tracker) Player(playerID string, window Window) (PlayerStats, bool) {
+	playerStats, ok := tracker.aggregate(window)[playerID]
+	if !ok {
+		return PlayerStats{}, false
+	}
+	return *playerStats, true
+}
+
+// Rank the players within the window by the metric, returning at most limit players.
+// Ties are broken by games played, then by name.
+func (tracker *Tracker) Leaderboard(window Window, metric Metric, limit int) []PlayerStats {
+	leaderboard := make([]PlayerStats, 0)
+	for _, playerStats := range tracker.aggregate(window) {
+		if playerStats.hasMetric(metric) {
+			leaderboard = append(leaderboard, *playerStats)
+		}
+	}
+
+	sort.Slice(leaderboard, func(i, j int) bool {
+		if leaderboard[i].ranksAbove(leaderboard[j], metric) {
+			return true
+		}
+		if leaderboard[j].ranksAbove(leaderboard[i], metric) {
+			return false
+		}
+		if leaderboard[i].GamesPlayed != leaderboard[j].GamesPlayed {
+			return leaderboard[i].GamesPlayed > leaderboard[j].GamesPlayed
+		}
+		return leaderboard[i].Name < leaderboard[j].Name
+	})
+	if len(leaderboard) > limit {
+		leaderboard = leaderboard[:limit]
+	}
+	return leaderboard
+}
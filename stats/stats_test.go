@@ -0,0 +1,207 @@
+package stats
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+var (
+	alice = Player{ID: "alice", Name: "Alice"}
+	bob   = Player{ID: "bob", Name: "Bob"}
+	carol = Player{ID: "carol", Name: "Carol"}
+)
+
+// Results of a few rounds: Alice hosts twice, Bob wins twice, Carol wins once, and one round has no oracle.
+func testResults(now time.Time) []RoundResult {
+	return []RoundResult{
+		{EndedAt: now.Add(-40 * 24 * time.Hour), Oracle: alice, Guessers: []Player{bob, carol}, WinnerID: "carol", QuestionsAsked: 5, AnswerDurations: []time.Duration{10 * time.Second}},
+		{EndedAt: now.Add(-2 * 24 * time.Hour), Oracle: alice, Guessers: []Player{bob}, WinnerID: "bob", QuestionsAsked: 10, AnswerDurations: []time.Duration{2 * time.Second, 4 * time.Second}},
+		{EndedAt: now.Add(-time.Hour), Oracle: carol, Guessers: []Player{bob}, WinnerID: "bob", QuestionsAsked: 20, AnswerDurations: []time.Duration{time.Second}},
+		{EndedAt: now.Add(-time.Hour), Guessers: []Player{carol}, QuestionsAsked: 20},
+	}
+}
+
+func TestPlayerStats(t *testing.T) {
+	tracker := NewTracker()
+	for _, result := range testResults(time.Now()) {
+		tracker.RecordRound(result)
+	}
+
+	testCases := []struct {
+		name     string
+		playerID string
+		window   Window
+		want     PlayerStats
+		wantOK   bool
+	}{
+		{"oracle all time", "alice", Window_All, PlayerStats{PlayerID: "alice", Name: "Alice", GamesPlayed: 2, OracleGames: 2, AverageAnswerSeconds: 16.0 / 3}, true},
+		{"oracle this week", "alice", Window_Week, PlayerStats{PlayerID: "alice", Name: "Alice", GamesPlayed: 1, OracleGames: 1, AverageAnswerSeconds: 3}, true},
+		{"oracle today", "alice", Window_Day, PlayerStats{}, false},
+		{"guesser all time", "bob", Window_All, PlayerStats{PlayerID: "bob", Name: "Bob", GamesPlayed: 3, GuesserWins: 2, AverageQuestionsToSolve: 15}, true},
+		{"guesser and oracle", "carol", Window_All, PlayerStats{PlayerID: "carol", Name: "Carol", GamesPlayed: 3, GuesserWins: 1, AverageQuestionsToSolve: 5, OracleGames: 1, AverageAnswerSeconds: 1}, true},
+		{"round without oracle", "carol", Window_Day, PlayerStats{PlayerID: "carol", Name: "Carol", GamesPlayed: 2, OracleGames: 1, AverageAnswerSeconds: 1}, true},
+		{"unknown player", "dave", Window_All, PlayerStats{}, false},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			got, ok := tracker.Player(testCase.playerID, testCase.window)
+			if ok != testCase.wantOK {
+				t.Fatalf("found = %v, want %v", ok, testCase.wantOK)
+			}
+			if !ok {
+				return
+			}
+			got.questionsToSolve, got.answers, got.answerDuration = 0, 0, 0
+			if got != testCase.want {
+				t.Errorf("got %+v, want %+v", got, testCase.want)
+			}
+		})
+	}
+}
+
+func TestLeaderboard(t *testing.T) {
+	tracker := NewTracker()
+	for _, result := range testResults(time.Now()) {
+		tracker.RecordRound(result)
+	}
+
+	testCases := []struct {
+		name   string
+		window Window
+		metric Metric
+		limit  int
+		want   []string
+	}{
+		{"wins", Window_All, Metric_Wins, 10, []string{"bob", "carol", "alice"}},
+		{"games", Window_All, Metric_GamesPlayed, 10, []string{"bob", "carol", "alice"}},
+		{"fewest questions", Window_All, Metric_QuestionsToWin, 10, []string{"carol", "bob"}},
+		{"oracle games", Window_All, Metric_OracleGames, 10, []string{"alice", "carol"}},
+		{"fastest answers", Window_All, Metric_AnswerLatency, 10, []string{"carol", "alice"}},
+		{"wins today", Window_Day, Metric_Wins, 10, []string{"bob", "carol"}},
+		{"limited", Window_All, Metric_Wins, 1, []string{"bob"}},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			leaderboard := tracker.Leaderboard(testCase.window, testCase.metric, testCase.limit)
+			got := make([]string, 0, len(leaderboard))
+			for _, playerStats := range leaderboard {
+				got = append(got, playerStats.PlayerID)
+			}
+			if len(got) != len(testCase.want) {
+				t.Fatalf("got %v, want %v", got, testCase.want)
+			}
+			for i := range got {
+				if got[i] != testCase.want[i] {
+					t.Fatalf("got %v, want %v", got, testCase.want)
+				}
+			}
+		})
+	}
+}
+
+func TestParseWindowAndMetric(t *testing.T) {
+	for _, name := range []string{"day", "week", "month", "all"} {
+		window, err := ParseWindow(name)
+		if err != nil || window.String() != name {
+			t.Errorf("window %s parsed as %s, %v", name, window, err)
+		}
+	}
+	for _, name := range []string{"wins", "games", "questions", "oracle", "latency"} {
+		metric, err := ParseMetric(name)
+		if err != nil || metric.String() != name {
+			t.Errorf("metric %s parsed as %s, %v", name, metric, err)
+		}
+	}
+	if _, err := ParseWindow("decade"); err == nil {
+		t.Error("parsed an unknown window")
+	}
+	if _, err := ParseMetric("style"); err == nil {
+		t.Error("parsed an unknown metric")
+	}
+}
+
+func TestOpenTracker(t *testing.T) {
+	now := time.Now()
+	results := testResults(now)
+
+	testCases := []struct {
+		name string
+
+		// Bytes appended to the results file after the results are recorded, before the tracker is reopened.
+		appended  string
+		wantCount int
+		wantErr   bool
+	}{
+		{"reloads results", "", len(results), false},
+		{"skips partial last line", `{"endedAt":"20`, len(results), false},
+		{"rejects invalid line", "not json\n{}\n", 0, true},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			directory := filepath.Join(t.TempDir(), "stats")
+			tracker, err := OpenTracker(directory)
+			if err != nil {
+				t.Fatalf("failed to open tracker: %v", err)
+			}
+			for _, result := range results {
+				if err := tracker.RecordRound(result); err != nil {
+					t.Fatalf("failed to record round: %v", err)
+				}
+			}
+			resultsFile, err := os.OpenFile(filepath.Join(directory, resultsFilename), os.O_WRONLY|os.O_APPEND, 0o644)
+			if err != nil {
+				t.Fatalf("failed to open results file: %v", err)
+			}
+			resultsFile.WriteString(testCase.appended)
+			resultsFile.Close()
+
+			reopened, err := OpenTracker(directory)
+			if (err != nil) != testCase.wantErr {
+				t.Fatalf("error = %v, want error %v", err, testCase.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if len(reopened.results) != testCase.wantCount {
+				t.Fatalf("reloaded %d results, want %d", len(reopened.results), testCase.wantCount)
+			}
+			if got, _ := reopened.Player("bob", Window_All); got.GuesserWins != 2 {
+				t.Errorf("bob has %d wins after reloading, want 2", got.GuesserWins)
+			}
+
+			// Results recorded after reopening, e.g. after a partial line, must survive the next restart too.
+			reopened.RecordRound(results[0])
+			reopenedAgain, err := OpenTracker(directory)
+			if err != nil {
+				t.Fatalf("failed to reopen tracker again: %v", err)
+			}
+			if len(reopenedAgain.results) != testCase.wantCount+1 {
+				t.Errorf("reloaded %d results after another round, want %d", len(reopenedAgain.results), testCase.wantCount+1)
+			}
+		})
+	}
+}
+
+func TestTrackerPing(t *testing.T) {
+	if err := NewTracker().Ping(); err != nil {
+		t.Errorf("in memory tracker failed ping: %v", err)
+	}
+
+	directory := t.TempDir()
+	tracker, err := OpenTracker(directory)
+	if err != nil {
+		t.Fatalf("failed to open tracker: %v", err)
+	}
+	if err := tracker.Ping(); err != nil {
+		t.Errorf("tracker failed ping: %v", err)
+	}
+	os.RemoveAll(directory)
+	if err := tracker.Ping(); err == nil {
+		t.Error("tracker with a missing directory passed ping")
+	}
+}
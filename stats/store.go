@@ -0,0 +1,137 @@
+package stats
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+const (
+	// Name of the file results are appended to, in the directory of the tracker.
+	resultsFilename string = "results.jsonl"
+)
+
+// Open a tracker saving results to a file in the given directory, creating the directory if needed and loading any
+// results already there, so the statistics survive restarts. Each result is appended to the file as a line of JSON.
+//
+// If the file holds more results than are kept, it is rewritten with only the newest, so it does not grow forever.
+// It is also rewritten without a partial last line, left by a crash partway through an append.
+func OpenTracker(directory string) (*Tracker, error) {
+	err := os.MkdirAll(directory, 0o755)
+	if err != nil {
+		return nil, err
+	}
+
+	tracker := &Tracker{
+		results:   make([]RoundResult, 0),
+		directory: directory,
+	}
+	results, partialLine, err := readResults(tracker.resultsPath())
+	if err != nil {
+		return nil, err
+	}
+	if len(results) > maxResults || partialLine {
+		results = results[max(len(results)-maxResults, 0):]
+		err = writeResults(tracker.resultsPath(), results)
+		if err != nil {
+			return nil, err
+		}
+	}
+	tracker.results = results
+	return tracker, nil
+}
+
+// The file results are saved in.
+func (tracker *Tracker) resultsPath() string {
+	return filepath.Join(tracker.directory, resultsFilename)
+}
+
+// Check the results can still be saved, by creating and removing a temporary file in the directory.
+// A tracker kept in memory is always fine.
+func (tracker *Tracker) Ping() error {
+	if tracker.directory == "" {
+		return nil
+	}
+	probeFile, err := os.CreateTemp(tracker.directory, ".ping-*.tmp")
+	if err != nil {
+		return err
+	}
+	probeFile.Close()
+	return os.Remove(probeFile.Name())
+}
+
+// Read every result from a results file, oldest first. A missing file has no results.
+// Returns true if the last line was partial, and so was skipped.
+func readResults(resultsPath string) ([]RoundResult, bool, error) {
+	resultsFile, err := os.Open(resultsPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return make([]RoundResult, 0), false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	defer resultsFile.Close()
+
+	results := make([]RoundResult, 0)
+	scanner := bufio.NewScanner(resultsFile)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	// A crash partway through an append leaves a partial last line, which is skipped rather than failing to start.
+	// Any other invalid line is an error.
+	var invalidLineErr error
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		if invalidLineErr != nil {
+			return nil, false, invalidLineErr
+		}
+		var result RoundResult
+		err = json.Unmarshal(scanner.Bytes(), &result)
+		if err != nil {
+			invalidLineErr = fmt.Errorf("invalid result on line %d of %s: %w", lineNumber, resultsPath, err)
+			continue
+		}
+		results = append(results, result)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, false, fmt.Errorf("failed to read %s: %w", resultsPath, err)
+	}
+	return results, invalidLineErr != nil, nil
+}
+
+// Append a result to the results file as a single write, so results are never interleaved.
+func appendResult(resultsPath string, result RoundResult) error {
+	line, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+	resultsFile, err := os.OpenFile(resultsPath, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	_, err = resultsFile.Write(append(line, '\n'))
+	return errors.Join(err, resultsFile.Close())
+}
+
+// Replace the results file with the given results. The file is written atomically, so a crash never loses results.
+func writeResults(resultsPath string, results []RoundResult) error {
+	temporaryPath := resultsPath + ".tmp"
+	temporaryFile, err := os.Create(temporaryPath)
+	if err != nil {
+		return err
+	}
+	writer := bufio.NewWriter(temporaryFile)
+	encoder := json.NewEncoder(writer)
+	for _, result := range results {
+		err = encoder.Encode(result)
+		if err != nil {
+			temporaryFile.Close()
+			return err
+		}
+	}
+	err = errors.Join(writer.Flush(), temporaryFile.Close())
+	if err != nil {
+		return err
+	}
+	return os.Rename(temporaryPath, resultsPath)
+}
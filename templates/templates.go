@@ -0,0 +1,7 @@
+// Package templates holds the HTML templates of the server, embedded so the server does not depend on its working directory.
+package templates
+
+import "embed"
+
+//go:embed *.html
+var Files embed.FS
@@ -0,0 +1,157 @@
+package transcript
+
+import (
+	"errors"
+	"time"
+)
+
+// Enum for Format, the formats a transcript can be written in.
+type Format int
+
+const (
+	Format_JSON     Format = iota
+	Format_CSV      Format = iota
+	Format_Markdown Format = iota
+)
+
+// Parse a format from its name, as used in export URLs.
+func ParseFormat(name string) (Format, error) {
+	switch name {
+	case "json", "":
+		return Format_JSON, nil
+	case "csv":
+		return Format_CSV, nil
+	case "md", "markdown":
+		return Format_Markdown, nil
+	default:
+		return Format_JSON, errors.New("unknown transcript format")
+	}
+}
+
+// The content type of a transcript written in this format.
+func (format Format) ContentType() string {
+	switch format {
+	case Format_CSV:
+		return "text/csv; charset=utf-8"
+	case Format_Markdown:
+		return "text/markdown; charset=utf-8"
+	default:
+		return "application/json"
+	}
+}
+
+// The file extension of a transcript written in this format.
+func (format Format) FileExtension() string {
+	switch format {
+	case Format_CSV:
+		return "csv"
+	case Format_Markdown:
+		return "md"
+	default:
+		return "json"
+	}
+}
+
+// --------------------------------------------------------------------------------
+// Transcript Data
+// --------------------------------------------------------------------------------
+
+// A record of a game of Twenty Questions, including every round played.
+type Transcript struct {
+	GameID     string    `json:"gameID"`
+	Title      string    `json:"title"`
+	CreatedAt  time.Time `json:"createdAt"`
+	ExportedAt time.Time `json:"exportedAt"`
+
+	Players []Player `json:"players"`
+	Rounds  []Round  `json:"rounds"`
+}
+
+// A player in the game, with their cumulative score.
+type Player struct {
+	Name  string `json:"name"`
+	Score int    `json:"score"`
+}
+
+// Outcomes of a round.
+const (
+	Outcome_InProgress = "inProgress"
+	Outcome_Correct    = "correct"
+	Outcome_Incorrect  = "incorrect"
+
+	// The round was ended by an admin, without a verdict or a winner.
+	Outcome_Ended = "ended"
+)
+
+// A single round of the game.
+type Round struct {
+	Number     int       `json:"number"`
+	OracleName string    `json:"oracleName"`
+	StartedAt  time.Time `json:"startedAt"`
+
+	// The time the oracle gave their verdict, or nil if the round is in progress.
+	EndedAt *time.Time `json:"endedAt,omitempty"`
+
+	// One of Outcome_InProgress, Outcome_Correct, Outcome_Incorrect or Outcome_Ended.
+	Outcome    string `json:"outcome"`
+	WinnerName string `json:"winnerName,omitempty"`
+
+	// The secret the oracle was thinking of -- only included once the round is over, or for the oracle.
+	Secret string `json:"secret,omitempty"`
+
+	Entries []Entry `json:"entries"`
+}
+
+// Kinds of entries in a round.
+const (
+	EntryKind_Question = "question"
+	EntryKind_Hint     = "hint"
+)
+
+// A question and answer, or a hint from the oracle.
+type Entry struct {
+	// One of EntryKind_Question or EntryKind_Hint.
+	Kind string `json:"kind"`
+
+	// The number of the question in the round. Zero for hints and retracted questions.
+	Index int `json:"index,omitempty"`
+
+	// The number of questions this entry used from the budget of the round.
+	Cost int `json:"cost"`
+
+	AskerName string `json:"askerName,omitempty"`
+	Question  string `json:"question,omitempty"`
+	Answer    string `json:"answer,omitempty"`
+	Hint      string `json:"hint,omitempty"`
+	Retracted bool   `json:"retracted,omitempty"`
+
+	// The time the question was asked (or the hint given), and the time it was answered -- nil if not yet answered.
+	AskedAt    time.Time  `json:"askedAt"`
+	AnsweredAt *time.Time `json:"answeredAt,omitempty"`
+
+	Revisions []Revision `json:"revisions,omitempty"`
+}
+
+// A change made to an entry after it was added.
+type Revision struct {
+	Time          time.Time `json:"time"`
+	EditorName    string    `json:"editorName"`
+	Description   string    `json:"description"`
+	PreviousValue string    `json:"previousValue"`
+}
+
+// How long the round lasted, or zero if the round is in progress.
+func (round Round) Duration() time.Duration {
+	if round.EndedAt == nil {
+		return 0
+	}
+	return round.EndedAt.Sub(round.StartedAt)
+}
+
+// How long the oracle took to answer the question, or zero if it has not been answered.
+func (entry Entry) AnswerDuration() time.Duration {
+	if entry.AnsweredAt == nil {
+		return 0
+	}
+	return entry.AnsweredAt.Sub(entry.AskedAt)
+}
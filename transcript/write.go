@@ -0,0 +1,187 @@
+package transcript
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// Escapes characters with meaning in Markdown, so player input is shown as written.
+	markdownEscaper = strings.NewReplacer(
+		`\`, `\\`, "`", "\\`", "*", `\*`, "_", `\_`, "[", `\[`, "]", `\]`,
+		"#", `\#`, "<", `\<`, ">", `\>`, "|", `\|`, "\n", " ",
+	)
+)
+
+// Write the transcript in the given format.
+func Write(w io.Writer, transcript Transcript, format Format) error {
+	switch format {
+	case Format_CSV:
+		return WriteCSV(w, transcript)
+	case Format_Markdown:
+		return WriteMarkdown(w, transcript)
+	default:
+		return WriteJSON(w, transcript)
+	}
+}
+
+// Write the transcript as indented JSON.
+func WriteJSON(w io.Writer, transcript Transcript) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(transcript)
+}
+
+// Write the transcript as CSV, with one row per entry. Rounds without entries are written as a single row.
+func WriteCSV(w io.Writer, transcript Transcript) error {
+	csvWriter := csv.NewWriter(w)
+	err := csvWriter.Write([]string{
+		"round", "oracle", "outcome", "winner", "secret", "started_at", "ended_at",
+		"kind", "index", "cost", "asker", "question", "answer", "hint", "retracted", "revisions", "asked_at", "answered_at",
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, round := range transcript.Rounds {
+		roundColumns := []string{
+			strconv.Itoa(round.Number), round.OracleName, round.Outcome, round.WinnerName, round.Secret,
+			formatCSVTime(&round.StartedAt), formatCSVTime(round.EndedAt),
+		}
+		if len(round.Entries) == 0 {
+			err = csvWriter.Write(append(roundColumns, make([]string, 11)...))
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		for _, entry := range round.Entries {
+			err = csvWriter.Write(append(roundColumns,
+				entry.Kind,
+				strconv.Itoa(entry.Index),
+				strconv.Itoa(entry.Cost),
+				entry.AskerName,
+				entry.Question,
+				entry.Answer,
+				entry.Hint,
+				strconv.FormatBool(entry.Retracted),
+				strconv.Itoa(len(entry.Revisions)),
+				formatCSVTime(&entry.AskedAt),
+				formatCSVTime(entry.AnsweredAt),
+			))
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	csvWriter.Flush()
+	return csvWriter.Error()
+}
+
+// Write the transcript as a Markdown document, suitable for pasting into a wiki.
+func WriteMarkdown(w io.Writer, transcript Transcript) error {
+	var builder strings.Builder
+
+	fmt.Fprintf(&builder, "# %s\n\n", escapeMarkdown(transcript.Title))
+	fmt.Fprintf(&builder, "Game `%s`, created %s, exported %s.\n\n", transcript.GameID, formatTime(transcript.CreatedAt), formatTime(transcript.ExportedAt))
+
+	builder.WriteString("## Players\n\n")
+	for _, player := range transcript.Players {
+		fmt.Fprintf(&builder, "- %s: %d point(s)\n", escapeMarkdown(player.Name), player.Score)
+	}
+	builder.WriteString("\n")
+
+	for _, round := range transcript.Rounds {
+		fmt.Fprintf(&builder, "## Round %d - %s is the Oracle\n\n", round.Number, escapeMarkdown(round.OracleName))
+		fmt.Fprintf(&builder, "Started %s. %s\n\n", formatTime(round.StartedAt), describeOutcome(round))
+		if round.EndedAt != nil {
+			fmt.Fprintf(&builder, "The round lasted %s.\n\n", formatDuration(round.Duration()))
+		}
+
+		for _, entry := range round.Entries {
+			builder.WriteString(markdownEntry(entry))
+		}
+		builder.WriteString("\n")
+	}
+
+	_, err := io.WriteString(w, builder.String())
+	return err
+}
+
+// Write a single entry as a Markdown list item, with any revisions as a nested list.
+func markdownEntry(entry Entry) string {
+	var builder strings.Builder
+
+	switch {
+	case entry.Kind == EntryKind_Hint:
+		fmt.Fprintf(&builder, "- **Hint:** %s", escapeMarkdown(entry.Hint))
+		if entry.Cost > 0 {
+			fmt.Fprintf(&builder, " (cost %d)", entry.Cost)
+		}
+	case entry.Retracted:
+		fmt.Fprintf(&builder, "- ~~%s~~ (retracted, asked by %s)", escapeMarkdown(entry.Question), escapeMarkdown(entry.AskerName))
+	default:
+		fmt.Fprintf(&builder, "- **Q%d:** %s (asked by %s)", entry.Index, escapeMarkdown(entry.Question), escapeMarkdown(entry.AskerName))
+		if entry.Answer != "" {
+			fmt.Fprintf(&builder, " **A:** %s (after %s)", escapeMarkdown(entry.Answer), formatDuration(entry.AnswerDuration()))
+		}
+	}
+	builder.WriteString("\n")
+
+	for _, revision := range entry.Revisions {
+		fmt.Fprintf(&builder, "  - _%s by %s at %s, was \"%s\"_\n",
+			escapeMarkdown(revision.Description), escapeMarkdown(revision.EditorName), formatTime(revision.Time), escapeMarkdown(revision.PreviousValue))
+	}
+	return builder.String()
+}
+
+// Describe the outcome of a round in a sentence.
+func describeOutcome(round Round) string {
+	var description string
+	switch round.Outcome {
+	case Outcome_Correct:
+		description = "The guessers were correct"
+	case Outcome_Incorrect:
+		description = "The guessers were incorrect"
+	case Outcome_Ended:
+		description = "The round was ended by an administrator"
+	default:
+		description = "The round is in progress"
+	}
+
+	if round.Outcome != Outcome_InProgress && round.WinnerName != "" {
+		description += fmt.Sprintf(", won by %s", escapeMarkdown(round.WinnerName))
+	}
+	if round.Secret != "" {
+		description += fmt.Sprintf(". The secret is **%s**", escapeMarkdown(round.Secret))
+	}
+	return description + "."
+}
+
+func escapeMarkdown(value string) string {
+	return markdownEscaper.Replace(value)
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format("2006-01-02 15:04:05 UTC")
+}
+
+// Format a time for a CSV column, or an empty string if the event has not happened.
+func formatCSVTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// Format a duration to the nearest second, e.g. "1m5s".
+func formatDuration(d time.Duration) string {
+	return d.Round(time.Second).String()
+}
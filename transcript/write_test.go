@@ -0,0 +1,181 @@
+package transcript
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+// A transcript of two rounds: a won round with a question, a hint and a revised, retracted question, and a round in progress without entries.
+func testTranscript() Transcript {
+	createdAt := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	answeredAt := createdAt.Add(90 * time.Second)
+	endedAt := createdAt.Add(5 * time.Minute)
+	return Transcript{
+		GameID:     "abc123",
+		Title:      "Friday *Fun*",
+		CreatedAt:  createdAt,
+		ExportedAt: endedAt,
+		Players:    []Player{{Name: "Alice", Score: 1}, {Name: "Bob_the_Oracle", Score: 0}},
+		Rounds: []Round{
+			{
+				Number: 1, OracleName: "Bob_the_Oracle", StartedAt: createdAt, EndedAt: &endedAt,
+				Outcome: Outcome_Correct, WinnerName: "Alice", Secret: "Giraffe",
+				Entries: []Entry{
+					{Kind: EntryKind_Question, Index: 1, Cost: 1, AskerName: "Alice", Question: "Is it tall?", Answer: "Yes", AskedAt: createdAt, AnsweredAt: &answeredAt},
+					{Kind: EntryKind_Hint, Cost: 2, Hint: "It lives in Africa", AskedAt: answeredAt},
+					{Kind: EntryKind_Question, Cost: 0, AskerName: "Alice", Question: "Is it | a cat?", Retracted: true, AskedAt: answeredAt,
+						Revisions: []Revision{{Time: answeredAt, EditorName: "Alice", Description: "Retracted", PreviousValue: "Is it a cat?"}}},
+				},
+			},
+			{Number: 2, OracleName: "Alice", StartedAt: endedAt, Outcome: Outcome_InProgress, Entries: []Entry{}},
+		},
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	testCases := []struct {
+		name          string
+		want          Format
+		wantErr       bool
+		wantExtension string
+	}{
+		{"", Format_JSON, false, "json"},
+		{"json", Format_JSON, false, "json"},
+		{"csv", Format_CSV, false, "csv"},
+		{"md", Format_Markdown, false, "md"},
+		{"markdown", Format_Markdown, false, "md"},
+		{"pdf", Format_JSON, true, "json"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			got, err := ParseFormat(testCase.name)
+			if (err != nil) != testCase.wantErr {
+				t.Fatalf("error = %v, want error %v", err, testCase.wantErr)
+			}
+			if got != testCase.want || got.FileExtension() != testCase.wantExtension {
+				t.Errorf("got format %d with extension %s, want %d with %s", got, got.FileExtension(), testCase.want, testCase.wantExtension)
+			}
+		})
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	var buffer bytes.Buffer
+	if err := Write(&buffer, testTranscript(), Format_JSON); err != nil {
+		t.Fatalf("failed to write transcript: %v", err)
+	}
+
+	var got Transcript
+	if err := json.Unmarshal(buffer.Bytes(), &got); err != nil {
+		t.Fatalf("failed to read transcript back: %v", err)
+	}
+	want := testTranscript()
+	if got.GameID != want.GameID || len(got.Rounds) != len(want.Rounds) || len(got.Rounds[0].Entries) != len(want.Rounds[0].Entries) {
+		t.Fatalf("read back %+v, want %+v", got, want)
+	}
+	if revisions := got.Rounds[0].Entries[2].Revisions; len(revisions) != 1 || revisions[0].PreviousValue != "Is it a cat?" {
+		t.Errorf("revisions = %+v, want the retraction", revisions)
+	}
+	if got.Rounds[1].EndedAt != nil || got.Rounds[1].Entries == nil {
+		t.Errorf("round in progress read back as %+v", got.Rounds[1])
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	var buffer bytes.Buffer
+	if err := Write(&buffer, testTranscript(), Format_CSV); err != nil {
+		t.Fatalf("failed to write transcript: %v", err)
+	}
+	rows, err := csv.NewReader(&buffer).ReadAll()
+	if err != nil {
+		t.Fatalf("failed to read CSV: %v", err)
+	}
+	if len(rows) != 5 {
+		t.Fatalf("got %d rows, want a header, three entries and an empty round", len(rows))
+	}
+
+	header := rows[0]
+	column := func(row []string, name string) string {
+		for i, columnName := range header {
+			if columnName == name {
+				return row[i]
+			}
+		}
+		t.Fatalf("no %s column", name)
+		return ""
+	}
+
+	testCases := []struct {
+		name   string
+		row    int
+		column string
+		want   string
+	}{
+		{"round of question", 1, "round", "1"},
+		{"winner", 1, "winner", "Alice"},
+		{"answer", 1, "answer", "Yes"},
+		{"asked at", 1, "asked_at", "2024-03-01T12:00:00Z"},
+		{"answered at", 1, "answered_at", "2024-03-01T12:01:30Z"},
+		{"hint", 2, "hint", "It lives in Africa"},
+		{"hint cost", 2, "cost", "2"},
+		{"question with separator", 3, "question", "Is it | a cat?"},
+		{"retracted", 3, "retracted", "true"},
+		{"revisions", 3, "revisions", "1"},
+		{"empty round", 4, "round", "2"},
+		{"empty round has no entry", 4, "kind", ""},
+		{"round in progress has no end", 4, "ended_at", ""},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			if len(rows[testCase.row]) != len(header) {
+				t.Fatalf("row %d has %d columns, want %d", testCase.row, len(rows[testCase.row]), len(header))
+			}
+			if got := column(rows[testCase.row], testCase.column); got != testCase.want {
+				t.Errorf("row %d %s = %q, want %q", testCase.row, testCase.column, got, testCase.want)
+			}
+		})
+	}
+}
+
+func TestWriteMarkdown(t *testing.T) {
+	var buffer bytes.Buffer
+	if err := Write(&buffer, testTranscript(), Format_Markdown); err != nil {
+		t.Fatalf("failed to write transcript: %v", err)
+	}
+	markdown := buffer.String()
+
+	testCases := []struct {
+		name string
+		want string
+	}{
+		{"escaped title", `# Friday \*Fun\*`},
+		{"created and exported", "Game `abc123`, created 2024-03-01 12:00:00 UTC, exported 2024-03-01 12:05:00 UTC."},
+		{"player", `- Bob\_the\_Oracle: 0 point(s)`},
+		{"round heading", `## Round 1 - Bob\_the\_Oracle is the Oracle`},
+		{"outcome", "The guessers were correct, won by Alice. The secret is **Giraffe**."},
+		{"duration", "The round lasted 5m0s."},
+		{"question and answer", "- **Q1:** Is it tall? (asked by Alice) **A:** Yes (after 1m30s)"},
+		{"hint with cost", "- **Hint:** It lives in Africa (cost 2)"},
+		{"retracted question", `- ~~Is it \| a cat?~~ (retracted, asked by Alice)`},
+		{"revision", `  - _Retracted by Alice at 2024-03-01 12:01:30 UTC, was "Is it a cat?"_`},
+		{"round in progress", "The round is in progress."},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			if !strings.Contains(markdown, testCase.want) {
+				t.Errorf("markdown does not contain %q:\n%s", testCase.want, markdown)
+			}
+		})
+	}
+
+	if strings.Contains(markdown, "The round lasted 0s") {
+		t.Error("round in progress has a duration")
+	}
+}
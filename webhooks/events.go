@@ -0,0 +1,74 @@
+package webhooks
+
+import (
+	"time"
+
+	"github.com/hmcalister/twentyquestions/transcript"
+)
+
+// Types of event sent to webhooks.
+const (
+	EventType_GameCreated   = "game.created"
+	EventType_QuestionAsked = "question.asked"
+	EventType_AnswerGiven   = "answer.given"
+	EventType_GameOver      = "game.over"
+
+	// Sent only when a subscription is tested, never by a game.
+	EventType_Ping = "ping"
+)
+
+var (
+	// The event types a subscription may choose from. Pings are always sent when testing a subscription.
+	EventTypes = []string{EventType_GameCreated, EventType_QuestionAsked, EventType_AnswerGiven, EventType_GameOver}
+)
+
+// An event as sent in the body of a webhook request.
+type Event struct {
+	// Unique to the event -- retries of a delivery send the same ID, so receivers may ignore duplicates.
+	ID     string    `json:"id"`
+	Type   string    `json:"type"`
+	GameID string    `json:"gameID,omitempty"`
+	Time   time.Time `json:"time"`
+
+	// One of the event data types below, matching the type of event.
+	Data interface{} `json:"data"`
+}
+
+// Data of a game.created event.
+type GameCreatedData struct {
+	Title          string `json:"title"`
+	Category       string `json:"category"`
+	Public         bool   `json:"public"`
+	QuestionBudget int    `json:"questionBudget"`
+	ComputerOracle bool   `json:"computerOracle"`
+
+	// The path of the game on the server, e.g. "/game/abc/".
+	URL string `json:"url"`
+}
+
+// Data of a question.asked event.
+type QuestionAskedData struct {
+	Round     int    `json:"round"`
+	Index     int    `json:"index"`
+	AskerName string `json:"askerName"`
+	Question  string `json:"question"`
+}
+
+// Data of an answer.given event.
+type AnswerGivenData struct {
+	Round    int    `json:"round"`
+	Index    int    `json:"index"`
+	Question string `json:"question"`
+	Answer   string `json:"answer"`
+}
+
+// Data of a game.over event, sent as each round of a game ends -- the finished round, and the scores after it.
+type GameOverData struct {
+	Round   transcript.Round    `json:"round"`
+	Players []transcript.Player `json:"players"`
+}
+
+// Data of a ping event.
+type PingData struct {
+	SubscriptionID string `json:"subscriptionID"`
+}
@@ -0,0 +1,523 @@
+package webhooks
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// Headers sent with every webhook request.
+	SignatureHeader = "X-TwentyQuestions-Signature"
+	EventHeader     = "X-TwentyQuestions-Event"
+	DeliveryHeader  = "X-TwentyQuestions-Delivery"
+
+	// Number of attempts made to deliver an event, and the default wait before the first retry -- doubled after every failed attempt.
+	maxAttempts    int           = 6
+	initialBackoff time.Duration = time.Second
+	maxBackoff     time.Duration = time.Minute
+
+	// How long a receiver has to respond to a single attempt.
+	requestTimeout time.Duration = 10 * time.Second
+
+	// Maximum number of deliveries kept in the delivery log -- the oldest deliveries are dropped first.
+	maxDeliveryLogSize int = 1000
+
+	// Maximum number of subscriptions a single game may have.
+	maxSubscriptionsPerGame int = 5
+
+	// Maximum number of bytes of a response read before it is discarded.
+	maxResponseBytes int64 = 64 * 1024
+)
+
+var (
+	ErrInvalidURL           = errors.New("webhook URL must be an absolute http or https URL")
+	ErrUnknownEventType     = errors.New("unknown event type")
+	ErrTooManySubscriptions = errors.New("game has too many webhook subscriptions")
+	ErrNoSubscription       = errors.New("no such webhook subscription")
+	ErrGameWebhooksDisabled = errors.New("webhooks for single games are disabled on this server")
+)
+
+// A URL that events are sent to, either for every game on the server or for a single game.
+type Subscription struct {
+	ID  string `json:"id"`
+	URL string `json:"url"`
+
+	// The event types sent to the URL. Empty to send every event type.
+	Events []string `json:"events"`
+
+	// The game the subscription belongs to, or empty for a server-wide subscription.
+	GameID string `json:"gameID,omitempty"`
+
+	CreatedAt time.Time `json:"createdAt"`
+
+	// The key each request body is signed with. Only shown when the subscription is created.
+	Secret string `json:"-"`
+}
+
+// Check if an event of the given type from the given game is sent to the subscription.
+func (subscription Subscription) wants(eventType string, gameID string) bool {
+	if subscription.GameID != "" && subscription.GameID != gameID {
+		return false
+	}
+	return len(subscription.Events) == 0 || slices.Contains(subscription.Events, eventType)
+}
+
+// States of a delivery.
+const (
+	DeliveryStatus_Pending   = "pending"
+	DeliveryStatus_Delivered = "delivered"
+	DeliveryStatus_Failed    = "failed"
+)
+
+// A single event sent to a single subscription, including all retries.
+type Delivery struct {
+	ID             string `json:"id"`
+	SubscriptionID string `json:"subscriptionID"`
+	URL            string `json:"url"`
+	EventID        string `json:"eventID"`
+	EventType      string `json:"eventType"`
+	GameID         string `json:"gameID,omitempty"`
+
+	Status   string `json:"status"`
+	Attempts int    `json:"attempts"`
+
+	// The status code of the last response, or zero if the receiver did not respond.
+	StatusCode int `json:"statusCode,omitempty"`
+
+	// Why the last attempt failed, if it did.
+	Error string `json:"error,omitempty"`
+
+	CreatedAt     time.Time  `json:"createdAt"`
+	LastAttemptAt *time.Time `json:"lastAttemptAt,omitempty"`
+
+	// When the next attempt is made, or nil if the delivery is no longer pending.
+	NextAttemptAt *time.Time `json:"nextAttemptAt,omitempty"`
+
+	// The game of the subscription, used to show a game only the deliveries of its own subscriptions.
+	subscriptionGameID string
+}
+
+// Sends events to subscribed URLs as signed JSON, retrying failed deliveries with exponential backoff.
+type Dispatcher struct {
+	httpClient *http.Client
+
+	// If subscriptions may be made for single games. Games are created anonymously, so this lets any player
+	// have the server send requests to a URL of their choosing.
+	allowGameSubscriptions bool
+
+	// The wait before the first retry of a delivery, and the longest wait between any two attempts.
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+
+	// All subscriptions, in the order they were created.
+	subscriptions []*Subscription
+
+	// The most recent deliveries, oldest first.
+	deliveries []*Delivery
+
+	// Mutex to handle async access to the subscriptions and deliveries.
+	mutex sync.Mutex
+}
+
+// Create a new dispatcher with no subscriptions, allowing subscriptions for single games only if set.
+func NewDispatcher(allowGameSubscriptions bool) *Dispatcher {
+	return &Dispatcher{
+		httpClient:             &http.Client{Timeout: requestTimeout},
+		allowGameSubscriptions: allowGameSubscriptions,
+		initialBackoff:         initialBackoff,
+		maxBackoff:             maxBackoff,
+		subscriptions:          make([]*Subscription, 0),
+		deliveries:             make([]*Delivery, 0),
+	}
+}
+
+// Check if subscriptions may be made for single games.
+func (dispatcher *Dispatcher) GameSubscriptionsAllowed() bool {
+	return dispatcher.allowGameSubscriptions
+}
+
+// --------------------------------------------------------------------------------
+// Subscriptions
+// --------------------------------------------------------------------------------
+
+// Parse event types from form values, each of which may list several types separated by commas.
+// Returns an error if any type is unknown. No types means every type.
+func ParseEventTypes(values []string) ([]string, error) {
+	eventTypes := make([]string, 0)
+	for _, value := range values {
+		for _, eventType := range strings.Split(value, ",") {
+			eventType = strings.TrimSpace(eventType)
+			if eventType == "" || slices.Contains(eventTypes, eventType) {
+				continue
+			}
+			if !slices.Contains(EventTypes, eventType) {
+				return nil, fmt.Errorf("%w: %s", ErrUnknownEventType, eventType)
+			}
+			eventTypes = append(eventTypes, eventType)
+		}
+	}
+	return eventTypes, nil
+}
+
+// Subscribe a URL to events -- from every game if gameID is empty, or only from the given game.
+// A random secret is created if none is given. No event types means every type.
+func (dispatcher *Dispatcher) Subscribe(target string, secret string, gameID string, eventTypes []string) (Subscription, error) {
+	if gameID != "" && !dispatcher.allowGameSubscriptions {
+		return Subscription{}, ErrGameWebhooksDisabled
+	}
+	targetURL, err := url.Parse(target)
+	if err != nil || (targetURL.Scheme != "http" && targetURL.Scheme != "https") || targetURL.Host == "" {
+		return Subscription{}, ErrInvalidURL
+	}
+	for _, eventType := range eventTypes {
+		if !slices.Contains(EventTypes, eventType) {
+			return Subscription{}, fmt.Errorf("%w: %s", ErrUnknownEventType, eventType)
+		}
+	}
+	if secret == "" {
+		secret = randomHex(32)
+	}
+
+	dispatcher.mutex.Lock()
+	defer dispatcher.mutex.Unlock()
+
+	if gameID != "" && len(dispatcher.subscriptionsOf(gameID)) >= maxSubscriptionsPerGame {
+		return Subscription{}, ErrTooManySubscriptions
+	}
+
+	subscription := &Subscription{
+		ID:        randomHex(8),
+		URL:       targetURL.String(),
+		Events:    slices.Clone(eventTypes),
+		GameID:    gameID,
+		CreatedAt: time.Now(),
+		Secret:    secret,
+	}
+	if subscription.Events == nil {
+		subscription.Events = make([]string, 0)
+	}
+	dispatcher.subscriptions = append(dispatcher.subscriptions, subscription)
+	log.Info().Str("SubscriptionID", subscription.ID).Str("GameID", gameID).Str("URL", subscription.URL).Msg("Webhook Subscribed")
+	return *subscription, nil
+}
+
+// Remove a subscription. Pending deliveries to the subscription are not retried.
+func (dispatcher *Dispatcher) Unsubscribe(subscriptionID string) error {
+	dispatcher.mutex.Lock()
+	defer dispatcher.mutex.Unlock()
+
+	for i, subscription := range dispatcher.subscriptions {
+		if subscription.ID == subscriptionID {
+			dispatcher.subscriptions = slices.Delete(dispatcher.subscriptions, i, i+1)
+			log.Info().Str("SubscriptionID", subscriptionID).Msg("Webhook Unsubscribed")
+			return nil
+		}
+	}
+	return ErrNoSubscription
+}
+
+// Remove every subscription of a game, e.g. when the game is deleted.
+func (dispatcher *Dispatcher) RemoveGame(gameID string) {
+	dispatcher.mutex.Lock()
+	defer dispatcher.mutex.Unlock()
+
+	dispatcher.subscriptions = slices.DeleteFunc(dispatcher.subscriptions, func(subscription *Subscription) bool {
+		return subscription.GameID == gameID
+	})
+}
+
+// Get a subscription by its ID.
+func (dispatcher *Dispatcher) Subscription(subscriptionID string) (Subscription, bool) {
+	dispatcher.mutex.Lock()
+	defer dispatcher.mutex.Unlock()
+
+	subscription := dispatcher.subscriptionByID(subscriptionID)
+	if subscription == nil {
+		return Subscription{}, false
+	}
+	return *subscription, true
+}
+
+// Get every subscription, server-wide and per game, in the order they were created.
+func (dispatcher *Dispatcher) Subscriptions() []Subscription {
+	dispatcher.mutex.Lock()
+	defer dispatcher.mutex.Unlock()
+
+	subscriptions := make([]Subscription, 0, len(dispatcher.subscriptions))
+	for _, subscription := range dispatcher.subscriptions {
+		subscriptions = append(subscriptions, *subscription)
+	}
+	return subscriptions
+}
+
+// Get the subscriptions of a single game, in the order they were created.
+func (dispatcher *Dispatcher) GameSubscriptions(gameID string) []Subscription {
+	dispatcher.mutex.Lock()
+	defer dispatcher.mutex.Unlock()
+
+	subscriptions := make([]Subscription, 0)
+	for _, subscription := range dispatcher.subscriptionsOf(gameID) {
+		subscriptions = append(subscriptions, *subscription)
+	}
+	return subscriptions
+}
+
+// Find a subscription by its ID, or nil if there is no such subscription.
+//
+// The caller must hold the mutex.
+func (dispatcher *Dispatcher) subscriptionByID(subscriptionID string) *Subscription {
+	for _, subscription := range dispatcher.subscriptions {
+		if subscription.ID == subscriptionID {
+			return subscription
+		}
+	}
+	return nil
+}
+
+// Find the subscriptions of a single game.
+//
+// The caller must hold the mutex.
+func (dispatcher *Dispatcher) subscriptionsOf(gameID string) []*Subscription {
+	subscriptions := make([]*Subscription, 0)
+	for _, subscription := range dispatcher.subscriptions {
+		if subscription.GameID == gameID {
+			subscriptions = append(subscriptions, subscription)
+		}
+	}
+	return subscriptions
+}
+
+// --------------------------------------------------------------------------------
+// Delivery
+// --------------------------------------------------------------------------------
+
+// Send an event to every subscription that wants it. Returns immediately -- events are delivered in the background.
+// Deliveries are independent, so a receiver may see events out of order if an earlier delivery had to be retried.
+func (dispatcher *Dispatcher) Publish(eventType string, gameID string, data interface{}) {
+	event, body, err := newEvent(eventType, gameID, data)
+	if err != nil {
+		log.Error().Str("EventType", eventType).Err(err).Msg("Failed to encode webhook event")
+		return
+	}
+
+	dispatcher.mutex.Lock()
+	defer dispatcher.mutex.Unlock()
+
+	for _, subscription := range dispatcher.subscriptions {
+		if subscription.wants(eventType, gameID) {
+			delivery := dispatcher.newDelivery(*subscription, event)
+			go dispatcher.deliver(*subscription, delivery, body)
+		}
+	}
+}
+
+// Send a ping event to a single subscription, making a single attempt and waiting for the result.
+func (dispatcher *Dispatcher) Test(subscriptionID string) (Delivery, error) {
+	dispatcher.mutex.Lock()
+	subscription := dispatcher.subscriptionByID(subscriptionID)
+	if subscription == nil {
+		dispatcher.mutex.Unlock()
+		return Delivery{}, ErrNoSubscription
+	}
+	event, body, err := newEvent(EventType_Ping, subscription.GameID, PingData{SubscriptionID: subscription.ID})
+	if err != nil {
+		dispatcher.mutex.Unlock()
+		return Delivery{}, err
+	}
+	delivery := dispatcher.newDelivery(*subscription, event)
+	testedSubscription := *subscription
+	dispatcher.mutex.Unlock()
+
+	statusCode, err := dispatcher.send(testedSubscription, delivery.ID, event, body)
+
+	dispatcher.mutex.Lock()
+	defer dispatcher.mutex.Unlock()
+	dispatcher.recordAttempt(delivery, statusCode, err, false)
+	return *delivery, nil
+}
+
+// Deliver an event to a subscription, retrying with exponential backoff until it is delivered,
+// the receiver rejects it outright, the subscription is removed, or every attempt is used.
+func (dispatcher *Dispatcher) deliver(subscription Subscription, delivery *Delivery, body []byte) {
+	backoff := dispatcher.initialBackoff
+	for {
+		statusCode, err := dispatcher.send(subscription, delivery.ID, Event{ID: delivery.EventID, Type: delivery.EventType}, body)
+
+		dispatcher.mutex.Lock()
+		canRetry := delivery.Attempts+1 < maxAttempts && dispatcher.subscriptionByID(subscription.ID) != nil
+		if canRetry {
+			nextAttemptAt := time.Now().Add(backoff)
+			delivery.NextAttemptAt = &nextAttemptAt
+		}
+		retrying := dispatcher.recordAttempt(delivery, statusCode, err, canRetry)
+		dispatcher.mutex.Unlock()
+
+		if !retrying {
+			return
+		}
+		time.Sleep(backoff)
+		backoff = min(2*backoff, dispatcher.maxBackoff)
+	}
+}
+
+// Make a single attempt to send an event, returning the status code of the response.
+func (dispatcher *Dispatcher) send(subscription Subscription, deliveryID string, event Event, body []byte) (int, error) {
+	request, err := http.NewRequest(http.MethodPost, subscription.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("User-Agent", "twentyquestions-webhooks")
+	request.Header.Set(EventHeader, event.Type)
+	request.Header.Set(DeliveryHeader, deliveryID)
+	request.Header.Set(SignatureHeader, Sign([]byte(subscription.Secret), body))
+
+	response, err := dispatcher.httpClient.Do(request)
+	if err != nil {
+		return 0, err
+	}
+	defer response.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(response.Body, maxResponseBytes))
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return response.StatusCode, fmt.Errorf("receiver responded %s", response.Status)
+	}
+	return response.StatusCode, nil
+}
+
+// Record the result of an attempt in the delivery log, returning true if the delivery should be retried.
+// Only server errors, timeouts and rate limiting are retried -- other responses mean the receiver will never accept the event.
+//
+// The caller must hold the mutex.
+func (dispatcher *Dispatcher) recordAttempt(delivery *Delivery, statusCode int, err error, canRetry bool) bool {
+	now := time.Now()
+	delivery.Attempts += 1
+	delivery.LastAttemptAt = &now
+	delivery.StatusCode = statusCode
+	delivery.Error = ""
+
+	if err == nil {
+		delivery.Status = DeliveryStatus_Delivered
+		delivery.NextAttemptAt = nil
+		log.Debug().Str("DeliveryID", delivery.ID).Str("EventType", delivery.EventType).Int("Attempts", delivery.Attempts).Msg("Webhook Delivered")
+		return false
+	}
+
+	delivery.Error = err.Error()
+	retryable := statusCode == 0 || statusCode >= 500 || statusCode == http.StatusTooManyRequests || statusCode == http.StatusRequestTimeout
+	if canRetry && retryable {
+		return true
+	}
+
+	delivery.Status = DeliveryStatus_Failed
+	delivery.NextAttemptAt = nil
+	log.Warn().Str("DeliveryID", delivery.ID).Str("URL", delivery.URL).Int("Attempts", delivery.Attempts).Err(err).Msg("Webhook Delivery Failed")
+	return false
+}
+
+// Add a pending delivery of an event to a subscription to the delivery log, dropping the oldest delivery if the log is full.
+//
+// The caller must hold the mutex.
+func (dispatcher *Dispatcher) newDelivery(subscription Subscription, event Event) *Delivery {
+	delivery := &Delivery{
+		ID:                 randomHex(8),
+		SubscriptionID:     subscription.ID,
+		URL:                subscription.URL,
+		EventID:            event.ID,
+		EventType:          event.Type,
+		GameID:             event.GameID,
+		Status:             DeliveryStatus_Pending,
+		CreatedAt:          event.Time,
+		subscriptionGameID: subscription.GameID,
+	}
+	dispatcher.deliveries = append(dispatcher.deliveries, delivery)
+	if len(dispatcher.deliveries) > maxDeliveryLogSize {
+		dispatcher.deliveries = dispatcher.deliveries[len(dispatcher.deliveries)-maxDeliveryLogSize:]
+	}
+	return delivery
+}
+
+// --------------------------------------------------------------------------------
+// Delivery Log
+// --------------------------------------------------------------------------------
+
+// Get the deliveries to a subscription, most recent first. An empty ID gets the deliveries to every subscription.
+func (dispatcher *Dispatcher) Deliveries(subscriptionID string) []Delivery {
+	return dispatcher.filterDeliveries(func(delivery *Delivery) bool {
+		return subscriptionID == "" || delivery.SubscriptionID == subscriptionID
+	})
+}
+
+// Get the deliveries to the subscriptions of a single game, most recent first.
+// Deliveries of events from the game to server-wide subscriptions are not included.
+func (dispatcher *Dispatcher) GameDeliveries(gameID string) []Delivery {
+	return dispatcher.filterDeliveries(func(delivery *Delivery) bool {
+		return delivery.subscriptionGameID == gameID
+	})
+}
+
+// Get the deliveries matching a filter, most recent first.
+func (dispatcher *Dispatcher) filterDeliveries(keep func(*Delivery) bool) []Delivery {
+	dispatcher.mutex.Lock()
+	defer dispatcher.mutex.Unlock()
+
+	deliveries := make([]Delivery, 0)
+	for i := len(dispatcher.deliveries) - 1; i >= 0; i-- {
+		if keep(dispatcher.deliveries[i]) {
+			deliveries = append(deliveries, *dispatcher.deliveries[i])
+		}
+	}
+	return deliveries
+}
+
+// --------------------------------------------------------------------------------
+// Utility Functions
+// --------------------------------------------------------------------------------
+
+// Sign a request body with a secret, giving the value of the signature header: "sha256=" followed by the hex encoded HMAC-SHA256.
+// Receivers should compute the same value from the raw body and compare it in constant time.
+func Sign(secret []byte, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Check the value of a signature header against a request body, as a receiver would.
+func Verify(secret []byte, body []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, body)), []byte(signature))
+}
+
+// Create a new event with a random ID, returning the event and its JSON encoding.
+func newEvent(eventType string, gameID string, data interface{}) (Event, []byte, error) {
+	event := Event{
+		ID:     randomHex(16),
+		Type:   eventType,
+		GameID: gameID,
+		Time:   time.Now(),
+		Data:   data,
+	}
+	body, err := json.Marshal(event)
+	return event, body, err
+}
+
+// Create a random hex string from the given number of random bytes.
+func randomHex(byteCount int) string {
+	randomBytes := make([]byte, byteCount)
+	rand.Read(randomBytes)
+	return hex.EncodeToString(randomBytes)
+}
@@ -0,0 +1,211 @@
+package webhooks
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// Silence the dispatcher log, so test failures are not buried in it.
+func TestMain(m *testing.M) {
+	zerolog.SetGlobalLevel(zerolog.Disabled)
+	os.Exit(m.Run())
+}
+
+// A request received by a test receiver.
+type receivedRequest struct {
+	at         time.Time
+	event      Event
+	eventType  string
+	deliveryID string
+	signed     bool
+}
+
+// Receiver that responds to each request with the next of a list of status codes, repeating the last once the list runs out.
+type testReceiver struct {
+	server      *httptest.Server
+	secret      string
+	statusCodes []int
+	requests    []receivedRequest
+	mutex       sync.Mutex
+}
+
+func newTestReceiver(t *testing.T, secret string, statusCodes []int) *testReceiver {
+	t.Helper()
+	receiver := &testReceiver{secret: secret, statusCodes: statusCodes}
+	receiver.server = httptest.NewServer(http.HandlerFunc(receiver.handle))
+	t.Cleanup(receiver.server.Close)
+	return receiver
+}
+
+func (receiver *testReceiver) handle(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	request := receivedRequest{
+		at:         time.Now(),
+		eventType:  r.Header.Get(EventHeader),
+		deliveryID: r.Header.Get(DeliveryHeader),
+		signed:     Verify([]byte(receiver.secret), body, r.Header.Get(SignatureHeader)),
+	}
+	json.Unmarshal(body, &request.event)
+
+	receiver.mutex.Lock()
+	defer receiver.mutex.Unlock()
+	receiver.requests = append(receiver.requests, request)
+	w.WriteHeader(receiver.statusCodes[min(len(receiver.requests), len(receiver.statusCodes))-1])
+}
+
+func (receiver *testReceiver) received() []receivedRequest {
+	receiver.mutex.Lock()
+	defer receiver.mutex.Unlock()
+	return append([]receivedRequest(nil), receiver.requests...)
+}
+
+// Create a dispatcher that retries quickly, so the test does not wait on the real backoff.
+func newTestDispatcher() *Dispatcher {
+	dispatcher := NewDispatcher(true)
+	dispatcher.initialBackoff = 10 * time.Millisecond
+	dispatcher.maxBackoff = 40 * time.Millisecond
+	return dispatcher
+}
+
+// Wait until the only delivery to a subscription is no longer pending, failing the test if it takes too long.
+func waitForDelivery(t *testing.T, dispatcher *Dispatcher, subscriptionID string) Delivery {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		deliveries := dispatcher.Deliveries(subscriptionID)
+		if len(deliveries) == 1 && deliveries[0].Status != DeliveryStatus_Pending {
+			return deliveries[0]
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("delivery to %s still pending: %+v", subscriptionID, dispatcher.Deliveries(subscriptionID))
+	return Delivery{}
+}
+
+func TestDeliver(t *testing.T) {
+	testCases := []struct {
+		name         string
+		statusCodes  []int
+		wantStatus   string
+		wantAttempts int
+		wantCode     int
+	}{
+		{"delivered", []int{http.StatusOK}, DeliveryStatus_Delivered, 1, http.StatusOK},
+		{"retries server errors", []int{http.StatusInternalServerError, http.StatusBadGateway, http.StatusNoContent}, DeliveryStatus_Delivered, 3, http.StatusNoContent},
+		{"retries rate limiting", []int{http.StatusTooManyRequests, http.StatusOK}, DeliveryStatus_Delivered, 2, http.StatusOK},
+		{"rejected outright", []int{http.StatusBadRequest}, DeliveryStatus_Failed, 1, http.StatusBadRequest},
+		{"gives up", []int{http.StatusServiceUnavailable}, DeliveryStatus_Failed, maxAttempts, http.StatusServiceUnavailable},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			receiver := newTestReceiver(t, "shh", testCase.statusCodes)
+			dispatcher := newTestDispatcher()
+			subscription, err := dispatcher.Subscribe(receiver.server.URL, "shh", "", nil)
+			if err != nil {
+				t.Fatalf("failed to subscribe: %v", err)
+			}
+
+			dispatcher.Publish(EventType_GameCreated, "abc", GameCreatedData{})
+			delivery := waitForDelivery(t, dispatcher, subscription.ID)
+
+			if delivery.Status != testCase.wantStatus || delivery.Attempts != testCase.wantAttempts || delivery.StatusCode != testCase.wantCode {
+				t.Errorf("delivery %s after %d attempts with status code %d, want %s after %d with %d",
+					delivery.Status, delivery.Attempts, delivery.StatusCode, testCase.wantStatus, testCase.wantAttempts, testCase.wantCode)
+			}
+			if (delivery.Error == "") != (testCase.wantStatus == DeliveryStatus_Delivered) {
+				t.Errorf("delivery error = %q with status %s", delivery.Error, delivery.Status)
+			}
+			if delivery.NextAttemptAt != nil || delivery.LastAttemptAt == nil {
+				t.Errorf("finished delivery has next attempt %v and last attempt %v", delivery.NextAttemptAt, delivery.LastAttemptAt)
+			}
+			if delivery.EventType != EventType_GameCreated || delivery.GameID != "abc" || delivery.URL != subscription.URL {
+				t.Errorf("delivery log entry %+v does not match the event", delivery)
+			}
+
+			requests := receiver.received()
+			if len(requests) != testCase.wantAttempts {
+				t.Fatalf("receiver got %d requests, want %d", len(requests), testCase.wantAttempts)
+			}
+			wantGap := dispatcher.initialBackoff
+			for i, request := range requests {
+				if !request.signed {
+					t.Errorf("request %d has an invalid signature", i)
+				}
+				if request.deliveryID != delivery.ID || request.eventType != EventType_GameCreated || request.event.ID != delivery.EventID {
+					t.Errorf("request %d has delivery %s, event %s %s, want delivery %s, event %s %s", i,
+						request.deliveryID, request.eventType, request.event.ID, delivery.ID, EventType_GameCreated, delivery.EventID)
+				}
+				if i == 0 {
+					continue
+				}
+				if gap := request.at.Sub(requests[i-1].at); gap < wantGap {
+					t.Errorf("retry %d came %v after the last attempt, want at least %v", i, gap, wantGap)
+				}
+				wantGap = min(2*wantGap, dispatcher.maxBackoff)
+			}
+		})
+	}
+}
+
+func TestSignature(t *testing.T) {
+	body := []byte(`{"type":"ping"}`)
+	signature := Sign([]byte("shh"), body)
+
+	testCases := []struct {
+		name      string
+		secret    string
+		body      []byte
+		signature string
+		want      bool
+	}{
+		{"valid", "shh", body, signature, true},
+		{"wrong secret", "loud", body, signature, false},
+		{"altered body", "shh", []byte(`{"type":"pong"}`), signature, false},
+		{"missing prefix", "shh", body, signature[len("sha256="):], false},
+		{"empty", "shh", body, "", false},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			if got := Verify([]byte(testCase.secret), testCase.body, testCase.signature); got != testCase.want {
+				t.Errorf("Verify = %v, want %v", got, testCase.want)
+			}
+		})
+	}
+}
+
+func TestTestDelivery(t *testing.T) {
+	receiver := newTestReceiver(t, "shh", []int{http.StatusInternalServerError})
+	dispatcher := newTestDispatcher()
+	subscription, err := dispatcher.Subscribe(receiver.server.URL, "shh", "abc", nil)
+	if err != nil {
+		t.Fatalf("failed to subscribe: %v", err)
+	}
+
+	delivery, err := dispatcher.Test(subscription.ID)
+	if err != nil {
+		t.Fatalf("failed to test subscription: %v", err)
+	}
+	// A test delivery is a single attempt, so the receiver can be checked without waiting on retries.
+	if delivery.Status != DeliveryStatus_Failed || delivery.Attempts != 1 || delivery.StatusCode != http.StatusInternalServerError {
+		t.Errorf("test delivery %s after %d attempts with status code %d, want failed after 1 with 500", delivery.Status, delivery.Attempts, delivery.StatusCode)
+	}
+	if requests := receiver.received(); len(requests) != 1 || !requests[0].signed || requests[0].eventType != EventType_Ping {
+		t.Errorf("receiver got %+v, want a single signed ping", requests)
+	}
+	if deliveries := dispatcher.GameDeliveries("abc"); len(deliveries) != 1 || deliveries[0].ID != delivery.ID {
+		t.Errorf("game delivery log = %+v, want the test delivery", deliveries)
+	}
+	if _, err := dispatcher.Test("missing"); err != ErrNoSubscription {
+		t.Errorf("testing a missing subscription gave %v, want %v", err, ErrNoSubscription)
+	}
+}
@@ -0,0 +1,161 @@
+package wordpacks
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+const (
+	// The category that matches every pack.
+	Category_Anything = "Anything"
+)
+
+// Enum for Difficulty, how hard a secret is to guess.
+type Difficulty int
+
+const (
+	Difficulty_Any    Difficulty = iota
+	Difficulty_Easy   Difficulty = iota
+	Difficulty_Medium Difficulty = iota
+	Difficulty_Hard   Difficulty = iota
+)
+
+// Parse a difficulty from its name, as used in forms.
+func ParseDifficulty(name string) (Difficulty, error) {
+	switch name {
+	case "any", "":
+		return Difficulty_Any, nil
+	case "easy":
+		return Difficulty_Easy, nil
+	case "medium":
+		return Difficulty_Medium, nil
+	case "hard":
+		return Difficulty_Hard, nil
+	default:
+		return Difficulty_Any, errors.New("unknown difficulty")
+	}
+}
+
+// A pack of secrets for a category, as stored in a JSON file:
+//
+//	{
+//	  "name": "Animals",
+//	  "category": "Animals",
+//	  "words": {
+//	    "easy": ["dog", "cat"],
+//	    "medium": ["platypus"],
+//	    "hard": ["axolotl"]
+//	  }
+//	}
+type Pack struct {
+	Name     string `json:"name"`
+	Category string `json:"category"`
+	Words    struct {
+		Easy   []string `json:"easy"`
+		Medium []string `json:"medium"`
+		Hard   []string `json:"hard"`
+	} `json:"words"`
+}
+
+// Get the words of the pack with the given difficulty -- all words for Difficulty_Any.
+func (pack Pack) wordsWithDifficulty(difficulty Difficulty) []string {
+	switch difficulty {
+	case Difficulty_Easy:
+		return pack.Words.Easy
+	case Difficulty_Medium:
+		return pack.Words.Medium
+	case Difficulty_Hard:
+		return pack.Words.Hard
+	default:
+		return slices.Concat(pack.Words.Easy, pack.Words.Medium, pack.Words.Hard)
+	}
+}
+
+// Check the pack has a name, a category and at least one word, and that no word is blank.
+func (pack Pack) validate() error {
+	if strings.TrimSpace(pack.Name) == "" || strings.TrimSpace(pack.Category) == "" {
+		return errors.New("pack must have a name and a category")
+	}
+	allWords := pack.wordsWithDifficulty(Difficulty_Any)
+	if len(allWords) == 0 {
+		return errors.New("pack must have at least one word")
+	}
+	if slices.ContainsFunc(allWords, func(word string) bool { return strings.TrimSpace(word) == "" }) {
+		return errors.New("pack must not have blank words")
+	}
+	return nil
+}
+
+// --------------------------------------------------------------------------------
+// Library
+// --------------------------------------------------------------------------------
+
+// All word packs available to the server.
+type Library struct {
+	packs []Pack
+}
+
+// Load every JSON pack from the given directories, in order. Returns an error if any pack is invalid.
+func Load(directories ...string) (*Library, error) {
+	library := &Library{
+		packs: make([]Pack, 0),
+	}
+
+	for _, directory := range directories {
+		packFiles, err := filepath.Glob(filepath.Join(directory, "*.json"))
+		if err != nil {
+			return nil, err
+		}
+
+		for _, packFile := range packFiles {
+			pack, err := loadPack(packFile)
+			if err != nil {
+				return nil, fmt.Errorf("word pack %s: %w", packFile, err)
+			}
+			library.packs = append(library.packs, pack)
+		}
+	}
+	return library, nil
+}
+
+// Load and validate a single pack from a JSON file.
+func loadPack(packFile string) (Pack, error) {
+	packBytes, err := os.ReadFile(packFile)
+	if err != nil {
+		return Pack{}, err
+	}
+
+	var pack Pack
+	err = json.Unmarshal(packBytes, &pack)
+	if err != nil {
+		return Pack{}, err
+	}
+	return pack, pack.validate()
+}
+
+// The categories of all packs, in the order they were loaded.
+func (library *Library) Categories() []string {
+	categories := make([]string, 0, len(library.packs))
+	for _, pack := range library.packs {
+		if !slices.Contains(categories, pack.Category) {
+			categories = append(categories, pack.Category)
+		}
+	}
+	return categories
+}
+
+// All words in the category with the given difficulty. Category_Anything includes the words of every pack.
+func (library *Library) Candidates(category string, difficulty Difficulty) []string {
+	candidates := make([]string, 0)
+	for _, pack := range library.packs {
+		if category == Category_Anything || pack.Category == category {
+			candidates = append(candidates, pack.wordsWithDifficulty(difficulty)...)
+		}
+	}
+	return candidates
+}
@@ -0,0 +1,140 @@
+package wordpacks
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// Write word pack files to a new temporary directory, keyed by file name, returning the directory.
+func writeTestPacks(t *testing.T, packs map[string]string) string {
+	t.Helper()
+	directory := t.TempDir()
+	for filename, packJSON := range packs {
+		if err := os.WriteFile(filepath.Join(directory, filename), []byte(packJSON), 0o644); err != nil {
+			t.Fatalf("failed to write word pack: %v", err)
+		}
+	}
+	return directory
+}
+
+// Two small packs, one in a built in category and one in a new category, beside a file that is not a pack and is ignored.
+var testPacks = map[string]string{
+	"pets.json":  `{"name": "Pets", "category": "Animals", "words": {"easy": ["dog", "cat"], "hard": ["axolotl"]}}`,
+	"space.json": `{"name": "Space", "category": "Space", "words": {"easy": ["moon"], "medium": ["comet"], "hard": ["quasar"]}}`,
+	"notes.txt":  `not a word pack`,
+}
+
+func TestParseDifficulty(t *testing.T) {
+	testCases := []struct {
+		name    string
+		want    Difficulty
+		wantErr bool
+	}{
+		{"", Difficulty_Any, false},
+		{"any", Difficulty_Any, false},
+		{"easy", Difficulty_Easy, false},
+		{"medium", Difficulty_Medium, false},
+		{"hard", Difficulty_Hard, false},
+		{"Hard", Difficulty_Any, true},
+		{"impossible", Difficulty_Any, true},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			difficulty, err := ParseDifficulty(testCase.name)
+			if difficulty != testCase.want || (err != nil) != testCase.wantErr {
+				t.Errorf("got %v, %v, want %v and error %v", difficulty, err, testCase.want, testCase.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	builtinLibrary, err := Load("../data/wordpacks")
+	if err != nil {
+		t.Fatalf("failed to load built in word packs: %v", err)
+	}
+	builtinCategories := builtinLibrary.Categories()
+	for _, category := range []string{"Animals", "Food", "Objects", "Places"} {
+		if !slices.Contains(builtinCategories, category) {
+			t.Errorf("built in categories %v do not include %s", builtinCategories, category)
+		}
+	}
+
+	testCases := []struct {
+		name           string
+		directories    []string
+		wantCategories []string
+	}{
+		{"no directories", nil, []string{}},
+		{"missing directory", []string{filepath.Join(t.TempDir(), "missing")}, []string{}},
+		{"custom packs", []string{writeTestPacks(t, testPacks)}, []string{"Animals", "Space"}},
+		{"custom packs after built in", []string{"../data/wordpacks", writeTestPacks(t, testPacks)}, append(slices.Clone(builtinCategories), "Space")},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			library, err := Load(testCase.directories...)
+			if err != nil {
+				t.Fatalf("failed to load: %v", err)
+			}
+			if categories := library.Categories(); !slices.Equal(categories, testCase.wantCategories) {
+				t.Errorf("categories = %v, want %v", categories, testCase.wantCategories)
+			}
+		})
+	}
+}
+
+func TestLoadRejectsInvalidPack(t *testing.T) {
+	testCases := []struct {
+		name     string
+		packJSON string
+	}{
+		{"invalid JSON", `{"name": "Pets",`},
+		{"missing name", `{"category": "Animals", "words": {"easy": ["dog"]}}`},
+		{"blank category", `{"name": "Pets", "category": "  ", "words": {"easy": ["dog"]}}`},
+		{"no words", `{"name": "Pets", "category": "Animals", "words": {}}`},
+		{"blank word", `{"name": "Pets", "category": "Animals", "words": {"easy": ["dog", " "]}}`},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			directory := writeTestPacks(t, map[string]string{"pets.json": testCase.packJSON})
+			if _, err := Load("../data/wordpacks", directory); err == nil {
+				t.Error("loaded an invalid pack")
+			}
+		})
+	}
+}
+
+func TestCandidates(t *testing.T) {
+	library, err := Load(writeTestPacks(t, testPacks))
+	if err != nil {
+		t.Fatalf("failed to load: %v", err)
+	}
+
+	testCases := []struct {
+		name       string
+		category   string
+		difficulty Difficulty
+		want       []string
+	}{
+		{"every word of category", "Animals", Difficulty_Any, []string{"dog", "cat", "axolotl"}},
+		{"easy words of category", "Animals", Difficulty_Easy, []string{"dog", "cat"}},
+		{"no words of difficulty", "Animals", Difficulty_Medium, []string{}},
+		{"hard words of category", "Space", Difficulty_Hard, []string{"quasar"}},
+		{"easy words of every category", Category_Anything, Difficulty_Easy, []string{"dog", "cat", "moon"}},
+		{"every word", Category_Anything, Difficulty_Any, []string{"dog", "cat", "axolotl", "moon", "comet", "quasar"}},
+		{"unknown category", "Vehicles", Difficulty_Any, []string{}},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			if got := library.Candidates(testCase.category, testCase.difficulty); !slices.Equal(got, testCase.want) {
+				t.Errorf("got %v, want %v", got, testCase.want)
+			}
+		})
+	}
+}